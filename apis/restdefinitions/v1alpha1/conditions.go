@@ -0,0 +1,116 @@
+package v1alpha1
+
+import (
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types reporting each provisioning stage of a RestDefinition.
+const (
+	// TypeSpecFetched: the OAS document has been downloaded and parsed.
+	TypeSpecFetched rtv1.ConditionType = "SpecFetched"
+	// TypeSpecValid: the resource matches the operations of the OAS document.
+	TypeSpecValid rtv1.ConditionType = "SpecValid"
+	// TypeSchemaGenerated: the CRD schemas have been generated from the OAS document.
+	TypeSchemaGenerated rtv1.ConditionType = "SchemaGenerated"
+	// TypeCRDEstablished: the generated CRD is established and its names are accepted.
+	TypeCRDEstablished rtv1.ConditionType = "CRDEstablished"
+	// TypeRBACReady: the permissions of the dynamic controller are in place.
+	TypeRBACReady rtv1.ConditionType = "RBACReady"
+	// TypeControllerReady: the dynamic controller is deployed and ready.
+	TypeControllerReady rtv1.ConditionType = "ControllerReady"
+)
+
+// Reasons of the provisioning stage conditions.
+const (
+	ReasonFetched                  rtv1.ConditionReason = "Fetched"
+	ReasonFetchFailed              rtv1.ConditionReason = "FetchFailed"
+	ReasonValid                    rtv1.ConditionReason = "Valid"
+	ReasonInvalid                  rtv1.ConditionReason = "Invalid"
+	ReasonGenerated                rtv1.ConditionReason = "Generated"
+	ReasonGeneratedWithWarnings    rtv1.ConditionReason = "GeneratedWithWarnings"
+	ReasonGenerationFailed         rtv1.ConditionReason = "GenerationFailed"
+	ReasonBreakingChanges          rtv1.ConditionReason = "BreakingChanges"
+	ReasonEstablished              rtv1.ConditionReason = "Established"
+	ReasonNotFound                 rtv1.ConditionReason = "NotFound"
+	ReasonNotEstablished           rtv1.ConditionReason = "NotEstablished"
+	ReasonNamesNotAccepted         rtv1.ConditionReason = "NamesNotAccepted"
+	ReasonRBACApplied              rtv1.ConditionReason = "Applied"
+	ReasonRBACMissing              rtv1.ConditionReason = "Missing"
+	ReasonRBACOutdated             rtv1.ConditionReason = "Outdated"
+	ReasonDeployed                 rtv1.ConditionReason = "Deployed"
+	ReasonNotDeployed              rtv1.ConditionReason = "NotDeployed"
+	ReasonProgressing              rtv1.ConditionReason = "Progressing"
+	ReasonProgressDeadlineExceeded rtv1.ConditionReason = "ProgressDeadlineExceeded"
+	ReasonCrashLoopBackOff         rtv1.ConditionReason = "CrashLoopBackOff"
+	ReasonPoolConflict             rtv1.ConditionReason = "PoolConflict"
+)
+
+// A Condition that may apply to a RestDefinition.
+type Condition struct {
+	rtv1.Condition `json:",inline"`
+
+	// ObservedGeneration: the generation of the RestDefinition the condition was set for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// StageTrue returns a condition that indicates the provisioning stage ct succeeded.
+func StageTrue(ct rtv1.ConditionType, reason rtv1.ConditionReason) rtv1.Condition {
+	return rtv1.Condition{
+		Type:               ct,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+	}
+}
+
+// StageFalse returns a condition that indicates the provisioning stage ct did not
+// succeed (yet).
+func StageFalse(ct rtv1.ConditionType, reason rtv1.ConditionReason, msg string) rtv1.Condition {
+	return rtv1.Condition{
+		Type:               ct,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            msg,
+	}
+}
+
+// GetCondition returns the condition for the given ConditionType if exists,
+// otherwise returns a condition with unknown status.
+func (s *RestDefinitionStatus) GetCondition(ct rtv1.ConditionType) rtv1.Condition {
+	for _, c := range s.Conditions {
+		if c.Type == ct {
+			return c.Condition
+		}
+	}
+
+	return rtv1.Condition{Type: ct, Status: metav1.ConditionUnknown}
+}
+
+// SetConditions sets the supplied conditions observed at generation, replacing
+// any existing conditions of the same type. For a condition identical to the
+// existing one, ignoring the last transition time, only the observed generation
+// is updated: the condition did not transition.
+func (s *RestDefinitionStatus) SetConditions(generation int64, c ...rtv1.Condition) {
+	for _, new := range c {
+		exists := false
+		for i, existing := range s.Conditions {
+			if existing.Type != new.Type {
+				continue
+			}
+
+			exists = true
+			if existing.Equal(new) {
+				s.Conditions[i].ObservedGeneration = generation
+				continue
+			}
+
+			s.Conditions[i] = Condition{Condition: new, ObservedGeneration: generation}
+		}
+		if !exists {
+			s.Conditions = append(s.Conditions, Condition{Condition: new, ObservedGeneration: generation})
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package v1alpha1_test
+
+import (
+	"testing"
+	"time"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetConditions(t *testing.T) {
+	cr := &definitionv1alpha1.RestDefinition{}
+	cr.SetGeneration(1)
+
+	cr.SetConditions(rtv1.Available(),
+		definitionv1alpha1.StageFalse(definitionv1alpha1.TypeCRDEstablished, definitionv1alpha1.ReasonNotFound, "not found"))
+	if len(cr.Status.Conditions) != 2 {
+		t.Fatalf("expected 2 conditions, got %d", len(cr.Status.Conditions))
+	}
+	for _, c := range cr.Status.Conditions {
+		if c.ObservedGeneration != 1 {
+			t.Errorf("expected observedGeneration 1 for %s, got %d", c.Type, c.ObservedGeneration)
+		}
+	}
+
+	cr.SetGeneration(2)
+	cr.SetConditions(definitionv1alpha1.StageTrue(definitionv1alpha1.TypeCRDEstablished, definitionv1alpha1.ReasonEstablished))
+	if len(cr.Status.Conditions) != 2 {
+		t.Fatalf("expected 2 conditions, got %d", len(cr.Status.Conditions))
+	}
+
+	got := cr.GetCondition(definitionv1alpha1.TypeCRDEstablished)
+	if got.Status != metav1.ConditionTrue || got.Reason != definitionv1alpha1.ReasonEstablished {
+		t.Errorf("unexpected condition: %+v", got)
+	}
+	if cr.Status.Conditions[1].ObservedGeneration != 2 {
+		t.Errorf("expected observedGeneration 2, got %d", cr.Status.Conditions[1].ObservedGeneration)
+	}
+	if cr.Status.Conditions[0].ObservedGeneration != 1 {
+		t.Errorf("expected untouched condition to keep observedGeneration 1, got %d", cr.Status.Conditions[0].ObservedGeneration)
+	}
+
+	// The same condition observed at a new generation keeps its last transition time.
+	available := cr.GetCondition(rtv1.TypeReady)
+	cr.SetGeneration(3)
+	later := rtv1.Available()
+	later.LastTransitionTime = metav1.NewTime(available.LastTransitionTime.Add(time.Hour))
+	cr.SetConditions(later)
+	if got := cr.GetCondition(rtv1.TypeReady); !got.LastTransitionTime.Equal(&available.LastTransitionTime) {
+		t.Errorf("expected last transition time %s to be kept, got %s", available.LastTransitionTime, got.LastTransitionTime)
+	}
+	if cr.Status.Conditions[0].ObservedGeneration != 3 {
+		t.Errorf("expected observedGeneration 3, got %d", cr.Status.Conditions[0].ObservedGeneration)
+	}
+
+	if got := cr.GetCondition(definitionv1alpha1.TypeRBACReady); got.Status != metav1.ConditionUnknown {
+		t.Errorf("expected unknown status for a missing condition, got %s", got.Status)
+	}
+}
@@ -20,8 +20,152 @@ type VerbsDescription struct {
 	// +immutable
 	// +required
 	Path string `json:"path"`
+	// ResponsePointer: the JSON pointer to the resource in the response body, when the
+	// API wraps it in an envelope (i.e. '/data'). Defaults to the body itself.
+	// +optional
+	ResponsePointer string `json:"responsePointer,omitempty"`
+	// Pagination: how the results of a findby action are paged. The first page only is
+	// requested when not set.
+	// +optional
+	Pagination *Pagination `json:"pagination,omitempty"`
+	// Async: how the completion of an action answered with '202 Accepted' is polled.
+	// Supported by the create, update and delete actions.
+	// +optional
+	Async *Async `json:"async,omitempty"`
+}
+
+// Async describes a long-running operation: the API accepts the request with
+// '202 Accepted' and reports its progress on a status endpoint.
+type Async struct {
+	// StatusPath: the path of the status endpoint in the OAS Specification, polled with
+	// GET (i.e. '/operations/{operationId}'). Its parameters are read from the 202 response.
+	// +optional
+	StatusPath string `json:"statusPath,omitempty"`
+	// StatusHeader: the header of the 202 response holding the URL of the status
+	// endpoint (i.e. 'Location'), instead of statusPath.
+	// +optional
+	StatusHeader string `json:"statusHeader,omitempty"`
+	// StatePointer: the JSON pointer to the state of the operation in the response of
+	// the status endpoint (i.e. '/status')
+	// +required
+	StatePointer string `json:"statePointer"`
+	// SucceededValues: the states of an operation completed successfully
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	SucceededValues []string `json:"succeededValues"`
+	// FailedValues: the states of a failed operation
+	// +optional
+	FailedValues []string `json:"failedValues,omitempty"`
+}
+
+// Pagination strategies.
+const (
+	// PaginationPageNumber requests the pages by number, starting from 1 (i.e. '?page=2&per_page=50').
+	PaginationPageNumber = "PageNumber"
+	// PaginationCursor requests the next page with the cursor returned in the response body.
+	PaginationCursor = "Cursor"
+	// PaginationLinkHeader follows the 'next' link of the RFC 5988 Link header of the response.
+	PaginationLinkHeader = "LinkHeader"
+)
+
+// Pagination describes how a list endpoint pages its results.
+type Pagination struct {
+	// Strategy: how the next page is requested [PageNumber, Cursor, LinkHeader]
+	// +kubebuilder:validation:Enum=PageNumber;Cursor;LinkHeader
+	// +required
+	Strategy string `json:"strategy"`
+	// PageParameter: the query parameter holding the page number (PageNumber) or the
+	// cursor (Cursor). Required by these strategies.
+	// +optional
+	PageParameter string `json:"pageParameter,omitempty"`
+	// SizeParameter: the query parameter holding the size of the pages (i.e. 'per_page')
+	// +optional
+	SizeParameter string `json:"sizeParameter,omitempty"`
+	// PageSize: the size of the pages requested with sizeParameter
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PageSize *int `json:"pageSize,omitempty"`
+	// ItemsPointer: the JSON pointer to the items in the response body (i.e. '/data').
+	// Defaults to the body itself.
+	// +optional
+	ItemsPointer string `json:"itemsPointer,omitempty"`
+	// NextCursorPointer: the JSON pointer to the cursor of the next page in the response
+	// body (i.e. '/meta/next_cursor'). Required by the Cursor strategy.
+	// +optional
+	NextCursorPointer string `json:"nextCursorPointer,omitempty"`
+	// MaxPages: the maximum number of pages requested by a lookup. Unlimited when not set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPages *int `json:"maxPages,omitempty"`
 }
 
+const (
+	// ScopeNamespaced is the scope of namespaced resources.
+	ScopeNamespaced = "Namespaced"
+	// ScopeCluster is the scope of cluster scoped resources.
+	ScopeCluster = "Cluster"
+	// AllNamespaces is the WatchNamespaces entry that selects all namespaces.
+	AllNamespaces = "*"
+)
+
+const (
+	// ModeManual uses the verbsDescription and identifiers of the spec as they are.
+	ModeManual = "Manual"
+	// ModeScaffold infers the verbsDescription and identifiers of the resource from
+	// the paths of the OAS document when they are not set.
+	ModeScaffold = "Scaffold"
+)
+
+const (
+	// RegenerationAutomatic regenerates the CRD as soon as an upstream change is detected.
+	RegenerationAutomatic = "Automatic"
+	// RegenerationManual regenerates the CRD only once approved with AnnotationApproveRegeneration.
+	RegenerationManual = "Manual"
+
+	// AnnotationApproveRegeneration approves the regeneration of the CRD with the
+	// Manual policy when set to the digest reported in status.refresh.pendingDigest.
+	AnnotationApproveRegeneration = "swaggergen.krateo.io/approve-regeneration"
+
+	// AnnotationAllowBreakingChanges allows schema changes that existing custom
+	// resources may not validate against anymore when set to the digest reported in
+	// status.schemaChanges.digest: only the reviewed changes are applied.
+	AnnotationAllowBreakingChanges = "swaggergen.krateo.io/allow-breaking-changes"
+)
+
+const (
+	// DeletionOrphan removes the finalizers of the custom resources of the generated kind,
+	// leaving their external resources in place.
+	DeletionOrphan = "Orphan"
+	// DeletionCascade deletes the custom resources of the generated kind and waits for the
+	// dynamic controller to delete their external resources.
+	DeletionCascade = "Cascade"
+	// DeletionBlock waits for the custom resources of the generated kind to be deleted.
+	DeletionBlock = "Block"
+)
+
+// Phases of the deletion of a RestDefinition.
+const (
+	// DeletionPhaseBlocked: custom resources of the generated kind exist and the policy is Block.
+	DeletionPhaseBlocked = "Blocked"
+	// DeletionPhaseDraining: the custom resources are being deleted by the dynamic controller.
+	DeletionPhaseDraining = "Draining"
+	// DeletionPhaseOrphaning: the dynamic controller is stopped and the custom resources released.
+	DeletionPhaseOrphaning = "Orphaning"
+	// DeletionPhaseUndeploying: the dynamic controller, its RBAC and the CRDs are being removed.
+	DeletionPhaseUndeploying = "Undeploying"
+)
+
+// Types of schema changes.
+const (
+	SchemaChangeFieldAdded       = "FieldAdded"
+	SchemaChangeFieldRemoved     = "FieldRemoved"
+	SchemaChangeTypeChanged      = "TypeChanged"
+	SchemaChangeNewlyRequired    = "NewlyRequired"
+	SchemaChangeNoLongerRequired = "NoLongerRequired"
+	SchemaChangeEnumNarrowed     = "EnumNarrowed"
+	SchemaChangeEnumWidened      = "EnumWidened"
+)
+
 type GVK struct {
 	// Group: the group of the resource
 	// +optional
@@ -44,8 +188,48 @@ type Resource struct {
 	// +optional
 	VerbsDescription []VerbsDescription `json:"verbsDescription"`
 	// Identifiers: the list of fields to use as identifiers - used to populate the status of the resource
+	// Each entry is a property name (i.e. 'id'), a JSON pointer (i.e. '/metadata/uid') or a JSONPath
+	// (i.e. '$.metadata.uid') in the resource returned by the get action, optionally prefixed by the
+	// name of the status field (i.e. 'uid=$.metadata.uid'). Defaults to the last property of the path.
 	// +optional
 	Identifiers []string `json:"identifiers,omitempty"`
+	// ConnectionDetails: the fields of the response of the create action written to the
+	// connection Secret of the custom resource, referenced by its writeConnectionSecretToRef.
+	// Use it for the values returned once (i.e. API tokens, generated passwords), which are
+	// never written to the status.
+	// +optional
+	ConnectionDetails []ConnectionDetail `json:"connectionDetails,omitempty"`
+}
+
+// ConnectionDetail maps a field of the response of the create action to a key of the
+// connection Secret.
+type ConnectionDetail struct {
+	// Key: the key of the value in the Secret
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	Key string `json:"key"`
+	// Pointer: the JSON pointer to the value in the response of the create action,
+	// relative to its responsePointer (i.e. '/token')
+	Pointer string `json:"pointer"`
+}
+
+// Server selects the base URL of the requests of the dynamic controller among the
+// servers of the OAS Specification.
+type Server struct {
+	// URL: the absolute http(s) base URL of every request, replacing the servers of the OAS
+	// Specification (i.e. 'https://staging.example.com/api'). Index, description and variables are ignored.
+	// +optional
+	URL string `json:"url,omitempty"`
+	// Index: the index of the server to use. Defaults to the first server.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Index *int `json:"index,omitempty"`
+	// Description: the description of the server to use, instead of its index.
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Variables: the values of the server variables, declared by the selected server. Values
+	// must be in the enum of the variable, if any. Unset variables take their default value.
+	// +optional
+	Variables map[string]string `json:"variables,omitempty"`
 }
 
 // RestDefinitionSpec is the specification of a RestDefinition.
@@ -58,6 +242,72 @@ type RestDefinitionSpec struct {
 	// The resource to manage
 	// +optional
 	Resource Resource `json:"resource"`
+	// Server: the server of the OAS Specification the requests are sent to. The servers
+	// declared by a path or an operation replace the ones of the document, as per the
+	// OAS Specification, and the selection applies to them.
+	// Defaults to the first server, with the default value of its variables.
+	// +optional
+	Server *Server `json:"server,omitempty"`
+	// Resources: further resources to manage from the same OAS Specification. They share
+	// the dynamic controller and its role with the resource above, if any.
+	// +optional
+	Resources []Resource `json:"resources,omitempty"`
+	// Mode: how the verbsDescription of the resource is obtained [Manual, Scaffold]
+	// With Scaffold, an empty verbsDescription (and identifiers) is inferred from the OAS
+	// Specification paths using REST conventions and written back to the spec.
+	// +kubebuilder:validation:Enum=Manual;Scaffold
+	// +kubebuilder:default=Manual
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// Scope: the scope of the generated resource [Namespaced, Cluster]
+	// Cluster scoped resources are always watched in all namespaces.
+	// +kubebuilder:validation:Enum=Namespaced;Cluster
+	// +kubebuilder:default=Namespaced
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="scope is immutable"
+	// +immutable
+	// +optional
+	Scope string `json:"scope,omitempty"`
+	// WatchNamespaces: the namespaces watched by the dynamic controller of a Namespaced resource.
+	// Defaults to the namespace of the RestDefinition. Use '*' to watch all namespaces.
+	// +optional
+	WatchNamespaces []string `json:"watchNamespaces,omitempty"`
+	// RefreshInterval: how often the OAS document is re-fetched to detect upstream changes (i.e. '1h').
+	// The check runs at most once per reconcile. Disabled when not set.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+	// RegenerationPolicy: whether an upstream change of the OAS document regenerates the CRD
+	// automatically or only once approved with the 'swaggergen.krateo.io/approve-regeneration'
+	// annotation [Automatic, Manual]
+	// +kubebuilder:validation:Enum=Automatic;Manual
+	// +kubebuilder:default=Automatic
+	// +optional
+	RegenerationPolicy string `json:"regenerationPolicy,omitempty"`
+	// DeletionPolicy: what happens to the custom resources of the generated kind when the
+	// RestDefinition is deleted [Orphan, Cascade, Block]
+	// Orphan releases them leaving the external resources in place, Cascade deletes them
+	// (and their external resources) and Block waits for them to be deleted.
+	// The dynamic controller and the CRDs are removed once no custom resource is left.
+	// +kubebuilder:validation:Enum=Orphan;Cascade;Block
+	// +kubebuilder:default=Block
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// ControllerPool: the name of a dynamic controller shared with the RestDefinitions of the
+	// same namespace having the same controllerPool, instead of a dedicated one.
+	// The members of a pool must have the same resourceGroup and watched namespaces.
+	// +kubebuilder:validation:MaxLength=40
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	ControllerPool string `json:"controllerPool,omitempty"`
+}
+
+// AllResources returns the resources managed by the RestDefinition: the resource
+// (when its kind is set) followed by the resources.
+func (s *RestDefinitionSpec) AllResources() []Resource {
+	res := make([]Resource, 0, len(s.Resources)+1)
+	if len(s.Resource.Kind) > 0 {
+		res = append(res, s.Resource)
+	}
+	return append(res, s.Resources...)
 }
 
 type KindApiVersion struct {
@@ -70,21 +320,258 @@ type KindApiVersion struct {
 	Kind string `json:"kind,omitempty"`
 }
 
+// GenerationWarning is a non fatal error raised while generating the CRDs.
+type GenerationWarning struct {
+	// Class: the class of the warning (i.e. UnsupportedSecurityScheme, ParameterCollision)
+	Class string `json:"class"`
+
+	// Message: the description of the warning
+	Message string `json:"message"`
+}
+
+// ResolvedAction is an action resolved against the OAS Specification.
+type ResolvedAction struct {
+	// Kind: the kind of the resource of the action
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Action: the name of the action
+	Action string `json:"action"`
+
+	// Method: the http method of the operation
+	Method string `json:"method"`
+
+	// Path: the path of the operation
+	Path string `json:"path"`
+
+	// OperationID: the operationId of the operation, if any
+	// +optional
+	OperationID string `json:"operationId,omitempty"`
+}
+
+// GenerationStatus reports the outcome of the last CRD generation.
+type GenerationStatus struct {
+	// Digest: the digest of the generated schemas
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// Revision: the digest of the OAS document the schemas were generated from
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// CRDs: the names of the generated CRDs
+	// +optional
+	CRDs []string `json:"crds,omitempty"`
+
+	// Actions: the actions resolved against the OAS Specification
+	// +optional
+	Actions []ResolvedAction `json:"actions,omitempty"`
+
+	// Warnings: the non fatal errors raised during the generation
+	// +optional
+	Warnings []GenerationWarning `json:"warnings,omitempty"`
+}
+
+// SchemaChange describes a change of the schema of the generated CRD.
+type SchemaChange struct {
+	// Path: the path of the changed field, i.e. '.spec.name'
+	Path string `json:"path"`
+
+	// Type: the kind of change [FieldAdded, FieldRemoved, TypeChanged, NewlyRequired, NoLongerRequired, EnumNarrowed, EnumWidened]
+	Type string `json:"type"`
+
+	// Breaking: true if existing resources may not validate against the new schema
+	Breaking bool `json:"breaking"`
+
+	// Message: details about the change
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SchemaChangesStatus reports the changes of the schema of the CRD found the last time it was applied.
+type SchemaChangesStatus struct {
+	// Blocked: true if the changes have not been applied because some are breaking
+	// and the 'swaggergen.krateo.io/allow-breaking-changes' annotation is not set to Digest
+	// +optional
+	Blocked bool `json:"blocked,omitempty"`
+
+	// Digest: the digest of the schemas the changes lead to. Set the
+	// 'swaggergen.krateo.io/allow-breaking-changes' annotation to this value to apply them.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// Changes: the list of changes
+	// +optional
+	Changes []SchemaChange `json:"changes,omitempty"`
+}
+
+// Regeneration records a regeneration of the CRD.
+type Regeneration struct {
+	// Time: when the CRD was regenerated
+	Time metav1.Time `json:"time"`
+
+	// PreviousDigest: the digest of the schemas before the regeneration
+	// +optional
+	PreviousDigest string `json:"previousDigest,omitempty"`
+
+	// Digest: the digest of the schemas after the regeneration
+	Digest string `json:"digest"`
+}
+
+// RefreshStatus reports the periodic checks of the OAS document.
+type RefreshStatus struct {
+	// LastCheckTime: when the OAS document was last checked for changes
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// ETag: the entity tag of the last fetched OAS document
+	// +optional
+	ETag string `json:"etag,omitempty"`
+
+	// UpstreamDigest: the digest of the schemas generated from the last fetched OAS document
+	// +optional
+	UpstreamDigest string `json:"upstreamDigest,omitempty"`
+
+	// PendingDigest: the digest of a regeneration waiting for approval. Set the
+	// 'swaggergen.krateo.io/approve-regeneration' annotation to this value to approve it.
+	// +optional
+	PendingDigest string `json:"pendingDigest,omitempty"`
+
+	// LastRegeneration: the last regeneration of the CRD triggered by an upstream change
+	// +optional
+	LastRegeneration *Regeneration `json:"lastRegeneration,omitempty"`
+}
+
+// ArtifactsStatus references the artifacts published for the generated kinds.
+type ArtifactsStatus struct {
+	// ConfigMapRef: the ConfigMap holding the JSON schemas and the TypeScript definitions
+	// of the spec and status of the resource and of the authentication resources
+	ConfigMapRef rtv1.Reference `json:"configMapRef"`
+
+	// OperationsRef: the ConfigMap holding the operation map of the resources,
+	// mounted into the dynamic controller
+	// +optional
+	OperationsRef *rtv1.Reference `json:"operationsRef,omitempty"`
+
+	// Digest: the digest of the schemas the artifacts were generated from
+	// +optional
+	Digest string `json:"digest,omitempty"`
+}
+
+// DeletionStatus reports the progress of the deletion of a RestDefinition.
+type DeletionStatus struct {
+	// Phase: the current phase of the deletion [Blocked, Draining, Orphaning, Undeploying]
+	Phase string `json:"phase"`
+
+	// RemainingInstances: the number of custom resources of the generated kind left
+	// +optional
+	RemainingInstances int `json:"remainingInstances,omitempty"`
+
+	// Message: details about the current phase
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// InventoryItem references an object installed for a RestDefinition.
+type InventoryItem struct {
+	// APIVersion: the api version of the object
+	APIVersion string `json:"apiVersion"`
+
+	// Kind: the kind of the object
+	Kind string `json:"kind"`
+
+	// Name: the name of the object
+	Name string `json:"name"`
+
+	// Namespace: the namespace of the object, empty for cluster scoped objects
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ResourceStatus reports the state of one of the resources of a RestDefinition.
+type ResourceStatus struct {
+	// APIVersion: the api version of the resource
+	APIVersion string `json:"apiVersion"`
+
+	// Kind: the kind of the resource
+	Kind string `json:"kind"`
+
+	// CRD: the name of the generated CRD
+	// +optional
+	CRD string `json:"crd,omitempty"`
+
+	// Ready: true when the CRD is established
+	Ready bool `json:"ready"`
+
+	// Message: why the resource is not ready
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ControllerPoolStatus reports the membership of a RestDefinition in a controller pool.
+type ControllerPoolStatus struct {
+	// Name: the name of the pool
+	Name string `json:"name"`
+
+	// Controller: the name of the Deployment of the shared dynamic controller
+	// +optional
+	Controller string `json:"controller,omitempty"`
+
+	// Members: the number of RestDefinitions served by the shared dynamic controller
+	// +optional
+	Members int `json:"members,omitempty"`
+}
+
 // RestDefinitionStatus is the status of a RestDefinition.
 type RestDefinitionStatus struct {
-	rtv1.ConditionedStatus `json:",inline"`
+	// Conditions of the resource.
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
 
 	// OASPath: the path to the OAS Specification file
 	// +optional
 	OASPath string `json:"oasPath"`
 
-	// Resource: the resource to manage
+	// Resource: the resource to manage, the first one when several are managed
 	// +optional
 	Resource KindApiVersion `json:"resource"`
 
+	// Resources: the state of each managed resource
+	// +optional
+	Resources []ResourceStatus `json:"resources,omitempty"`
+
 	// Authentications: the list of authentications to use
 	// +optional
 	Authentications []KindApiVersion `json:"authentications"`
+
+	// Generation: the outcome of the last CRD generation
+	// +optional
+	Generation *GenerationStatus `json:"generation,omitempty"`
+
+	// Refresh: the periodic checks of the OAS document, see spec.refreshInterval
+	// +optional
+	Refresh *RefreshStatus `json:"refresh,omitempty"`
+
+	// SchemaChanges: the changes of the CRD schema found the last time it was applied
+	// +optional
+	SchemaChanges *SchemaChangesStatus `json:"schemaChanges,omitempty"`
+
+	// Artifacts: the JSON schemas and TypeScript definitions published for the generated kinds
+	// +optional
+	Artifacts *ArtifactsStatus `json:"artifacts,omitempty"`
+
+	// Deletion: the progress of the deletion, see spec.deletionPolicy
+	// +optional
+	Deletion *DeletionStatus `json:"deletion,omitempty"`
+
+	// Inventory: the objects installed for the RestDefinition, removed on deletion
+	// without fetching the OAS Specification
+	// +optional
+	Inventory []InventoryItem `json:"inventory,omitempty"`
+
+	// ControllerPool: the controller pool serving the resources, see spec.controllerPool
+	// +optional
+	ControllerPool *ControllerPoolStatus `json:"controllerPool,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -118,7 +605,8 @@ func (mg *RestDefinition) GetCondition(ct rtv1.ConditionType) rtv1.Condition {
 	return mg.Status.GetCondition(ct)
 }
 
-// SetConditions of this RestDefinition.
+// SetConditions of this RestDefinition. The conditions are stamped with the
+// generation of the RestDefinition they were observed at.
 func (mg *RestDefinition) SetConditions(c ...rtv1.Condition) {
-	mg.Status.SetConditions(c...)
+	mg.Status.SetConditions(mg.GetGeneration(), c...)
 }
@@ -21,9 +21,118 @@ limitations under the License.
 package v1alpha1
 
 import (
+	commonv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactsStatus) DeepCopyInto(out *ArtifactsStatus) {
+	*out = *in
+	out.ConfigMapRef = in.ConfigMapRef
+	if in.OperationsRef != nil {
+		in, out := &in.OperationsRef, &out.OperationsRef
+		*out = new(commonv1.Reference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactsStatus.
+func (in *ArtifactsStatus) DeepCopy() *ArtifactsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Async) DeepCopyInto(out *Async) {
+	*out = *in
+	if in.SucceededValues != nil {
+		in, out := &in.SucceededValues, &out.SucceededValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailedValues != nil {
+		in, out := &in.FailedValues, &out.FailedValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Async.
+func (in *Async) DeepCopy() *Async {
+	if in == nil {
+		return nil
+	}
+	out := new(Async)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.Condition.DeepCopyInto(&out.Condition)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionDetail) DeepCopyInto(out *ConnectionDetail) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetail.
+func (in *ConnectionDetail) DeepCopy() *ConnectionDetail {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionDetail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerPoolStatus) DeepCopyInto(out *ControllerPoolStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerPoolStatus.
+func (in *ControllerPoolStatus) DeepCopy() *ControllerPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionStatus) DeepCopyInto(out *DeletionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionStatus.
+func (in *DeletionStatus) DeepCopy() *DeletionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GVK) DeepCopyInto(out *GVK) {
 	*out = *in
@@ -39,6 +148,66 @@ func (in *GVK) DeepCopy() *GVK {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenerationStatus) DeepCopyInto(out *GenerationStatus) {
+	*out = *in
+	if in.CRDs != nil {
+		in, out := &in.CRDs, &out.CRDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]ResolvedAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]GenerationWarning, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenerationStatus.
+func (in *GenerationStatus) DeepCopy() *GenerationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GenerationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenerationWarning) DeepCopyInto(out *GenerationWarning) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenerationWarning.
+func (in *GenerationWarning) DeepCopy() *GenerationWarning {
+	if in == nil {
+		return nil
+	}
+	out := new(GenerationWarning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryItem) DeepCopyInto(out *InventoryItem) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryItem.
+func (in *InventoryItem) DeepCopy() *InventoryItem {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KindApiVersion) DeepCopyInto(out *KindApiVersion) {
 	*out = *in
@@ -54,19 +223,106 @@ func (in *KindApiVersion) DeepCopy() *KindApiVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pagination) DeepCopyInto(out *Pagination) {
+	*out = *in
+	if in.PageSize != nil {
+		in, out := &in.PageSize, &out.PageSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxPages != nil {
+		in, out := &in.MaxPages, &out.MaxPages
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pagination.
+func (in *Pagination) DeepCopy() *Pagination {
+	if in == nil {
+		return nil
+	}
+	out := new(Pagination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RefreshStatus) DeepCopyInto(out *RefreshStatus) {
+	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRegeneration != nil {
+		in, out := &in.LastRegeneration, &out.LastRegeneration
+		*out = new(Regeneration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RefreshStatus.
+func (in *RefreshStatus) DeepCopy() *RefreshStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RefreshStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Regeneration) DeepCopyInto(out *Regeneration) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Regeneration.
+func (in *Regeneration) DeepCopy() *Regeneration {
+	if in == nil {
+		return nil
+	}
+	out := new(Regeneration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedAction) DeepCopyInto(out *ResolvedAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedAction.
+func (in *ResolvedAction) DeepCopy() *ResolvedAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Resource) DeepCopyInto(out *Resource) {
 	*out = *in
 	if in.VerbsDescription != nil {
 		in, out := &in.VerbsDescription, &out.VerbsDescription
 		*out = make([]VerbsDescription, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Identifiers != nil {
 		in, out := &in.Identifiers, &out.Identifiers
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ConnectionDetails != nil {
+		in, out := &in.ConnectionDetails, &out.ConnectionDetails
+		*out = make([]ConnectionDetail, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resource.
@@ -79,6 +335,21 @@ func (in *Resource) DeepCopy() *Resource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceStatus) DeepCopyInto(out *ResourceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceStatus.
+func (in *ResourceStatus) DeepCopy() *ResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestDefinition) DeepCopyInto(out *RestDefinition) {
 	*out = *in
@@ -142,6 +413,28 @@ func (in *RestDefinitionList) DeepCopyObject() runtime.Object {
 func (in *RestDefinitionSpec) DeepCopyInto(out *RestDefinitionSpec) {
 	*out = *in
 	in.Resource.DeepCopyInto(&out.Resource)
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(Server)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]Resource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WatchNamespaces != nil {
+		in, out := &in.WatchNamespaces, &out.WatchNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestDefinitionSpec.
@@ -157,13 +450,59 @@ func (in *RestDefinitionSpec) DeepCopy() *RestDefinitionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestDefinitionStatus) DeepCopyInto(out *RestDefinitionStatus) {
 	*out = *in
-	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.Resource = in.Resource
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Authentications != nil {
 		in, out := &in.Authentications, &out.Authentications
 		*out = make([]KindApiVersion, len(*in))
 		copy(*out, *in)
 	}
+	if in.Generation != nil {
+		in, out := &in.Generation, &out.Generation
+		*out = new(GenerationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Refresh != nil {
+		in, out := &in.Refresh, &out.Refresh
+		*out = new(RefreshStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SchemaChanges != nil {
+		in, out := &in.SchemaChanges, &out.SchemaChanges
+		*out = new(SchemaChangesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = new(ArtifactsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = new(DeletionStatus)
+		**out = **in
+	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = make([]InventoryItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.ControllerPool != nil {
+		in, out := &in.ControllerPool, &out.ControllerPool
+		*out = new(ControllerPoolStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestDefinitionStatus.
@@ -176,9 +515,81 @@ func (in *RestDefinitionStatus) DeepCopy() *RestDefinitionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaChange) DeepCopyInto(out *SchemaChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaChange.
+func (in *SchemaChange) DeepCopy() *SchemaChange {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaChangesStatus) DeepCopyInto(out *SchemaChangesStatus) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]SchemaChange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaChangesStatus.
+func (in *SchemaChangesStatus) DeepCopy() *SchemaChangesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaChangesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Server) DeepCopyInto(out *Server) {
+	*out = *in
+	if in.Index != nil {
+		in, out := &in.Index, &out.Index
+		*out = new(int)
+		**out = **in
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Server.
+func (in *Server) DeepCopy() *Server {
+	if in == nil {
+		return nil
+	}
+	out := new(Server)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VerbsDescription) DeepCopyInto(out *VerbsDescription) {
 	*out = *in
+	if in.Pagination != nil {
+		in, out := &in.Pagination, &out.Pagination
+		*out = new(Pagination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Async != nil {
+		in, out := &in.Async, &out.Async
+		*out = new(Async)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerbsDescription.
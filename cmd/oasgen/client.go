@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/generator"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/render"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/emitter"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"gopkg.in/alecthomas/kingpin.v2"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+type clientCmd struct {
+	cmd      *kingpin.CmdClause
+	filename *string
+	oasPath  *string
+	pkg      *string
+	output   *string
+}
+
+func newClientCmd(app *kingpin.Application) *clientCmd {
+	cmd := app.Command("client", "Generate typed Go structs and clients for the kinds generated from RestDefinitions.")
+	return &clientCmd{
+		cmd: cmd,
+		filename: cmd.Flag("filename", "File containing the RestDefinitions, or - for stdin.").Short('f').
+			Required().
+			String(),
+		oasPath: cmd.Flag("oas", "Use this OAS document (URL or local file) instead of spec.oasPath.").
+			String(),
+		pkg: cmd.Flag("package", "Name of the generated Go package.").Short('p').
+			Default(render.ResourceVersion).
+			String(),
+		output: cmd.Flag("output", "Directory of the generated files.").Short('o').
+			Default(".").
+			String(),
+	}
+}
+
+func (c *clientCmd) run(w io.Writer) error {
+	defs, err := readRestDefinitions(*c.filename)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*c.output, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", *c.output, err)
+	}
+
+	ctx := context.Background()
+	for i := range defs {
+		cr := &defs[i]
+		if len(*c.oasPath) > 0 {
+			cr.Spec.OASPath = *c.oasPath
+		}
+
+		doc, err := oas.Load(cr.Spec.OASPath)
+		if err != nil {
+			return fmt.Errorf("%s: loading OAS: %w", cr.Name, err)
+		}
+		if errs := validation.ValidateRestDefinition(doc, cr); len(errs) > 0 {
+			return fmt.Errorf("%s: %w", cr.Name, errs.ToAggregate())
+		}
+
+		// Only the CRDs are used: the arguments of the dynamic controller do not matter
+		res, err := render.Render(ctx, doc, cr, true)
+		if err != nil {
+			return fmt.Errorf("%s: %w", cr.Name, err)
+		}
+		for _, warn := range res.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s: [%s] %v\n", cr.Name, generator.WarningClass(warn), warn)
+		}
+
+		for _, obj := range res.Objects {
+			crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+			if !ok {
+				continue
+			}
+			kind, err := emitter.FromCRD(crd, render.ResourceVersion)
+			if err != nil {
+				return fmt.Errorf("%s: %w", cr.Name, err)
+			}
+			files, err := emitter.EmitGo(kind, *c.pkg)
+			if err != nil {
+				return fmt.Errorf("%s: emitting %s: %w", cr.Name, kind.GVK.Kind, err)
+			}
+			for _, f := range files {
+				path := filepath.Join(*c.output, f.Name)
+				if err := os.WriteFile(path, f.Content, 0o644); err != nil {
+					return fmt.Errorf("writing %s: %w", path, err)
+				}
+				fmt.Fprintln(w, path)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/lint"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type lintCmd struct {
+	cmd    *kingpin.CmdClause
+	spec   *string
+	output *string
+}
+
+func newLintCmd(app *kingpin.Application) *lintCmd {
+	cmd := app.Command("lint", "Report which operations of an OAS document can be described by a RestDefinition.")
+	return &lintCmd{
+		cmd: cmd,
+		spec: cmd.Arg("spec", "OAS document (URL or local file).").
+			Required().
+			String(),
+		output: cmd.Flag("output", "Output format.").Short('o').
+			Default("table").
+			Enum("table", "json"),
+	}
+}
+
+func (c *lintCmd) run(w io.Writer) error {
+	doc, err := oas.Load(*c.spec)
+	if err != nil {
+		return fmt.Errorf("loading OAS: %w", err)
+	}
+
+	report := lint.Lint(doc)
+	if *c.output == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return printLintTable(w, report)
+}
+
+func printLintTable(w io.Writer, report lint.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "METHOD\tPATH\tOPERATION ID\tRESOURCE\tACTION")
+	for _, op := range report.Operations {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", op.Method, op.Path, dash(op.OperationID), dash(op.Resource), dash(op.Action))
+	}
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "RESOURCE\tCOLLECTION\tITEM\tACTIONS\tIDENTIFIERS\tCOMPATIBLE")
+	for _, res := range report.Resources {
+		actions := make([]string, 0, len(res.VerbsDescription))
+		for _, v := range res.VerbsDescription {
+			actions = append(actions, v.Action)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\n", res.Kind, res.CollectionPath, dash(res.ItemPath),
+			dash(strings.Join(actions, ",")), dash(strings.Join(res.Identifiers, ",")), res.Compatible)
+	}
+
+	if len(report.SecuritySchemes) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "SECURITY SCHEME\tTYPE\tSCHEME\tSUPPORTED")
+		for _, sec := range report.SecuritySchemes {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%t\n", sec.Name, sec.Type, dash(sec.Scheme), sec.Supported)
+		}
+	}
+
+	issues := len(report.Issues)
+	for _, res := range report.Resources {
+		issues += len(res.Issues)
+	}
+	if issues > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "SEVERITY\tRESOURCE\tCLASS\tMESSAGE")
+		for _, is := range report.Issues {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", is.Severity, "-", is.Class, is.Message)
+		}
+		for _, res := range report.Resources {
+			for _, is := range res.Issues {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", is.Severity, res.Kind, is.Class, is.Message)
+			}
+		}
+	}
+
+	return tw.Flush()
+}
+
+func dash(s string) string {
+	if len(s) == 0 {
+		return "-"
+	}
+	return s
+}
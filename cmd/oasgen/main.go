@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func main() {
+	app := kingpin.New("oasgen", "Krateo OAS Gen Provider command line tools.")
+
+	render := newRenderCmd(app)
+	lint := newLintCmd(app)
+	scaffold := newScaffoldCmd(app)
+	client := newClientCmd(app)
+
+	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	case render.cmd.FullCommand():
+		if err := render.run(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	case lint.cmd.FullCommand():
+		if err := lint.run(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	case scaffold.cmd.FullCommand():
+		if err := scaffold.run(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	case client.cmd.FullCommand():
+		if err := client.run(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+)
+
+// writeManifest writes obj as a YAML document ready to be applied: the status
+// and the creation timestamp are dropped.
+func writeManifest(w io.Writer, obj any) error {
+	dat, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	m := map[string]any{}
+	if err := json.Unmarshal(dat, &m); err != nil {
+		return err
+	}
+	delete(m, "status")
+	if meta, ok := m["metadata"].(map[string]any); ok {
+		delete(meta, "creationTimestamp")
+	}
+
+	dat, err = yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "---\n%s", dat)
+	return err
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/generator"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/render"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"gopkg.in/alecthomas/kingpin.v2"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+type renderCmd struct {
+	cmd          *kingpin.CmdClause
+	filename     *string
+	oasPath      *string
+	extendedArgs *bool
+}
+
+func newRenderCmd(app *kingpin.Application) *renderCmd {
+	cmd := app.Command("render", "Render the CRDs, RBAC and Deployment of RestDefinitions without a cluster.")
+	return &renderCmd{
+		cmd: cmd,
+		filename: cmd.Flag("filename", "File containing the RestDefinitions, or - for stdin.").Short('f').
+			Required().
+			String(),
+		oasPath: cmd.Flag("oas", "Use this OAS document (URL or local file) instead of spec.oasPath.").
+			String(),
+		extendedArgs: cmd.Flag("extended-args", "Pass one -resource argument per resource and the -operations argument to the dynamic controller.").
+			Bool(),
+	}
+}
+
+func (c *renderCmd) run(w io.Writer) error {
+	defs, err := readRestDefinitions(*c.filename)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for i := range defs {
+		cr := &defs[i]
+		if len(*c.oasPath) > 0 {
+			cr.Spec.OASPath = *c.oasPath
+		}
+
+		doc, err := oas.Load(cr.Spec.OASPath)
+		if err != nil {
+			return fmt.Errorf("%s: loading OAS: %w", cr.Name, err)
+		}
+		errs := validation.ValidateRestDefinition(doc, cr)
+		errs = append(errs, validation.ValidateControllerArgs(&cr.Spec, cr.Namespace, *c.extendedArgs)...)
+		if len(errs) > 0 {
+			return fmt.Errorf("%s: %w", cr.Name, errs.ToAggregate())
+		}
+
+		res, err := render.Render(ctx, doc, cr, *c.extendedArgs)
+		if err != nil {
+			return fmt.Errorf("%s: %w", cr.Name, err)
+		}
+		for _, warn := range res.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s: [%s] %v\n", cr.Name, generator.WarningClass(warn), warn)
+		}
+
+		for _, obj := range res.Objects {
+			if err := writeManifest(w, obj); err != nil {
+				return fmt.Errorf("%s: marshalling %s %s: %w", cr.Name,
+					obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// readRestDefinitions decodes the RestDefinitions of a (multi document) YAML or
+// JSON file. Documents of other kinds are skipped. The namespace defaults to "default".
+func readRestDefinitions(filename string) ([]definitionv1alpha1.RestDefinition, error) {
+	var (
+		dat []byte
+		err error
+	)
+	if filename == "-" {
+		dat, err = io.ReadAll(os.Stdin)
+	} else {
+		dat, err = os.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+
+	res := []definitionv1alpha1.RestDefinition{}
+	dec := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(dat), 4096)
+	for {
+		cr := definitionv1alpha1.RestDefinition{}
+		err := dec.Decode(&cr)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", filename, err)
+		}
+		if cr.Kind != definitionv1alpha1.RestDefinitionKind {
+			continue
+		}
+		if len(cr.Namespace) == 0 {
+			cr.Namespace = "default"
+		}
+		res = append(res, cr)
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no RestDefinition found in %s", filename)
+	}
+	return res, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/scaffold"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type scaffoldCmd struct {
+	cmd       *kingpin.CmdClause
+	spec      *string
+	oasPath   *string
+	group     *string
+	namespace *string
+	kinds     *[]string
+}
+
+func newScaffoldCmd(app *kingpin.Application) *scaffoldCmd {
+	cmd := app.Command("scaffold", "Generate RestDefinitions for the resources inferred from an OAS document.")
+	return &scaffoldCmd{
+		cmd: cmd,
+		spec: cmd.Arg("spec", "OAS document (URL or local file).").
+			Required().
+			String(),
+		oasPath: cmd.Flag("oas-path", "spec.oasPath of the RestDefinitions. Defaults to the OAS document argument.").
+			String(),
+		group: cmd.Flag("group", "spec.resourceGroup of the RestDefinitions.").Short('g').
+			Required().
+			String(),
+		namespace: cmd.Flag("namespace", "Namespace of the RestDefinitions.").Short('n').
+			Default("default").
+			String(),
+		kinds: cmd.Flag("kind", "Kind of the resource to scaffold (repeatable). Defaults to all the compatible resources.").Short('k').
+			Strings(),
+	}
+}
+
+func (c *scaffoldCmd) run(w io.Writer) error {
+	doc, err := oas.Load(*c.spec)
+	if err != nil {
+		return fmt.Errorf("loading OAS: %w", err)
+	}
+
+	oasPath := *c.oasPath
+	if len(oasPath) == 0 {
+		oasPath = *c.spec
+	}
+
+	defs, errs := scaffold.RestDefinitions(doc, scaffold.Options{
+		OASPath:       oasPath,
+		ResourceGroup: *c.group,
+		Namespace:     *c.namespace,
+		Kinds:         *c.kinds,
+	})
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	if len(defs) == 0 {
+		return fmt.Errorf("no compatible resource found")
+	}
+
+	for i := range defs {
+		if err := writeManifest(w, &defs[i]); err != nil {
+			return fmt.Errorf("marshalling %s: %w", defs[i].Name, err)
+		}
+	}
+	return nil
+}
@@ -5,9 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"os"
-	"path"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gobuffalo/flect"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
@@ -20,33 +21,44 @@ import (
 	"github.com/krateoplatformops/provider-runtime/pkg/ratelimiter"
 	"github.com/pb33f/libopenapi"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/krateoplatformops/provider-runtime/pkg/reconciler"
 	"github.com/krateoplatformops/provider-runtime/pkg/resource"
 
 	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/generator"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/render"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/scaffold"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/crds"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
-	"github.com/krateoplatformops/oasgen-provider/internal/tools/filegetter"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/generation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/naming"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/rbactools"
-
-	"github.com/krateoplatformops/crdgen"
-	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/text"
 )
 
 const (
 	errNotRestDefinition = "managed resource is not a RestDefinition"
-	resourceVersion      = "v1alpha1"
+	resourceVersion      = render.ResourceVersion
 )
 
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+func Setup(mgr ctrl.Manager, o controller.Options, extendedArgs bool) error {
 	name := reconciler.ControllerName(definitionv1alpha1.RestDefinitionGroupKind)
 
 	log := o.Logger.WithValues("controller", name)
@@ -57,24 +69,78 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		resource.ManagedKind(definitionv1alpha1.RestDefinitionGroupVersionKind),
 		reconciler.WithExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
+			reader:   mgr.GetAPIReader(),
 			log:      log,
 			recorder: recorder,
+			cache:    oas.NewCache(),
+
+			extendedArgs: extendedArgs,
 		}),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
 
+	owned := builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		_, owned := deployment.OwnerOf(obj)
+		_, pooled := deployment.PoolOf(obj)
+		return owned || pooled
+	}))
+	toRestDefinition := handler.EnqueueRequestsFromMapFunc(restDefinitionsFor(mgr.GetClient()))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&definitionv1alpha1.RestDefinition{}).
+		Watches(&appsv1.Deployment{}, toRestDefinition, owned).
+		Watches(&rbacv1.Role{}, toRestDefinition, owned).
+		Watches(&rbacv1.ClusterRole{}, toRestDefinition, owned).
+		Watches(&apiextensionsv1.CustomResourceDefinition{}, toRestDefinition, owned).
 		Complete(ratelimiter.New(name, r, o.GlobalRateLimiter))
 }
 
+// restDefinitionsFor maps an object generated for a RestDefinition back to it,
+// using the labels set by deployment.SetOwnerLabels, and an object of a shared
+// dynamic controller to the members of its pool.
+func restDefinitionsFor(kube client.Reader) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		if pool, ok := deployment.PoolOf(obj); ok {
+			return poolRequests(ctx, kube, pool)
+		}
+
+		nn, ok := deployment.OwnerOf(obj)
+		if !ok {
+			return nil
+		}
+		if len(nn.Name) < naming.MaxLength {
+			return []reconcile.Request{{NamespacedName: nn}}
+		}
+
+		// The name label may have been truncated: look for the matching RestDefinitions.
+		list := definitionv1alpha1.RestDefinitionList{}
+		if err := kube.List(ctx, &list, client.InNamespace(nn.Namespace)); err != nil {
+			return nil
+		}
+		res := []reconcile.Request{}
+		for _, el := range list.Items {
+			if naming.SafeName(el.Name) == nn.Name {
+				res = append(res, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: el.Namespace, Name: el.Name}})
+			}
+		}
+		return res
+	}
+}
+
 type connector struct {
-	kube     client.Client
+	kube client.Client
+	// reader reads the objects the provider does not watch (i.e. the pods of the
+	// dynamic controllers) straight from the API server, without starting an informer.
+	reader   client.Reader
 	log      logging.Logger
 	recorder record.EventRecorder
+	cache    *oas.Cache
+	// extendedArgs is true when the dynamic controller image accepts one
+	// -resource argument per resource and the -operations argument.
+	extendedArgs bool
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (reconciler.ExternalClient, error) {
@@ -82,61 +148,81 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (reconcile
 	if !ok {
 		return nil, errors.New(errNotRestDefinition)
 	}
-	var err error
-	swaggerPath := cr.Spec.OASPath
-	basePath := "/tmp/swaggergen-provider"
-	err = os.MkdirAll(basePath, os.ModePerm)
-	defer os.RemoveAll(basePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create directory: %w", err)
-	}
 
-	err = filegetter.GetFile(path.Join(basePath, path.Base(swaggerPath)), swaggerPath, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to download file: %w", err)
-	}
-
-	contents, _ := os.ReadFile(path.Join(basePath, path.Base(swaggerPath)))
-
-	d, err := libopenapi.NewDocument(contents)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+	// The teardown relies only on the status of the RestDefinition: the OAS
+	// document is not fetched, so that a moved or unreachable document does not
+	// prevent the deletion.
+	if meta.WasDeleted(cr) {
+		return &external{
+			kube:         c.kube,
+			reader:       c.reader,
+			log:          c.log,
+			rec:          c.recorder,
+			extendedArgs: c.extendedArgs,
+		}, nil
 	}
 
-	doc, modelErrors := d.BuildV3Model()
-	if len(modelErrors) > 0 {
-		return nil, fmt.Errorf("failed to build model: %w", errors.Join(modelErrors...))
+	var maxAge time.Duration
+	if cr.Spec.RefreshInterval != nil {
+		maxAge = cr.Spec.RefreshInterval.Duration
 	}
-	if doc == nil {
-		return nil, fmt.Errorf("failed to build model")
-	}
-
-	// Resolve model references
-	resolvingErrors := doc.Index.GetResolver().Resolve()
-	errs := []error{}
-	for i := range resolvingErrors {
-		c.log.Debug("Resolving error", "error", resolvingErrors[i].Error())
-		errs = append(errs, resolvingErrors[i].ErrorRef)
+	doc, rev, err := c.cache.Get(cr.Spec.OASPath, maxAge)
+	if err != nil {
+		cr.SetConditions(definitionv1alpha1.StageFalse(definitionv1alpha1.TypeSpecFetched,
+			definitionv1alpha1.ReasonFetchFailed, err.Error()))
+
+		var resolveErr *oas.ResolveError
+		if errors.As(err, &resolveErr) {
+			for _, er := range resolveErr.Errors {
+				c.recorder.Event(cr, corev1.EventTypeWarning, "ReferenceResolutionFailed", er.Error())
+			}
+		}
+		return nil, err
 	}
-	if len(resolvingErrors) > 0 {
-		return nil, fmt.Errorf("failed to resolve model references: %w", errors.Join(errs...))
+	cr.SetConditions(definitionv1alpha1.StageTrue(definitionv1alpha1.TypeSpecFetched, definitionv1alpha1.ReasonFetched))
+
+	errs := validation.ValidateRestDefinition(doc, cr)
+	errs = append(errs, validation.ValidateControllerArgs(&cr.Spec, cr.Namespace, c.extendedArgs)...)
+	if len(errs) > 0 {
+		cr.SetConditions(definitionv1alpha1.StageFalse(definitionv1alpha1.TypeSpecValid,
+			definitionv1alpha1.ReasonInvalid, errs.ToAggregate().Error()))
+	} else {
+		cr.SetConditions(definitionv1alpha1.StageTrue(definitionv1alpha1.TypeSpecValid, definitionv1alpha1.ReasonValid))
 	}
 
 	return &external{
-		kube: c.kube,
-		log:  c.log,
-		doc:  doc,
-		rec:  c.recorder,
+		kube:     c.kube,
+		reader:   c.reader,
+		log:      c.log,
+		doc:      doc,
+		revision: rev,
+		rec:      c.recorder,
+		invalid:  errs.ToAggregate(),
+
+		extendedArgs: c.extendedArgs,
 	}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	kube client.Client
-	log  logging.Logger
-	doc  *libopenapi.DocumentModel[v3.Document]
-	rec  record.EventRecorder
+	kube   client.Client
+	reader client.Reader
+	log    logging.Logger
+	doc    *libopenapi.DocumentModel[v3.Document]
+	// revision identifies the version of doc.
+	revision oas.Revision
+	rec      record.EventRecorder
+	// regenerate is set by Observe when the CRD must be regenerated by Update.
+	regenerate bool
+	// publish is set by Observe when the artifacts must be published by Update.
+	publish bool
+	// invalid holds the validation errors of the spec, if any: nothing is generated
+	// nor deployed from an invalid spec.
+	invalid error
+	// extendedArgs is true when the dynamic controller image accepts one
+	// -resource argument per resource and the -operations argument.
+	extendedArgs bool
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler.ExternalObservation, error) {
@@ -145,40 +231,156 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 		return reconciler.ExternalObservation{}, errors.New(errNotRestDefinition)
 	}
 
-	gvk := schema.GroupVersionKind{
-		Group:   cr.Spec.ResourceGroup,
-		Version: resourceVersion,
-		Kind:    cr.Spec.Resource.Kind,
+	if cr.Spec.Mode == definitionv1alpha1.ModeScaffold && needsScaffold(cr) && !meta.WasDeleted(cr) {
+		if err := e.scaffold(cr); err != nil {
+			return reconciler.ExternalObservation{}, err
+		}
+		// The spec is persisted first, the resources are created by the next reconcile.
+		return reconciler.ExternalObservation{
+			ResourceExists:          true,
+			ResourceUpToDate:        true,
+			ResourceLateInitialized: true,
+		}, nil
 	}
 
-	gvr := deployment.ToGroupVersionResource(gvk)
-	log.Printf("[DBG] Observing (gvk: %s, gvr: %s)\n", gvk.String(), gvr.String())
+	if meta.WasDeleted(cr) {
+		return e.observeDeletion(ctx, cr)
+	}
 
-	crdOk, err := deployment.LookupCRD(ctx, e.kube, gvr)
-	if err != nil {
-		return reconciler.ExternalObservation{}, err
+	if e.invalid != nil {
+		return reconciler.ExternalObservation{}, fmt.Errorf("invalid spec: %w", e.invalid)
 	}
 
-	if !crdOk {
-		log.Printf("[DBG] CRD does not exists yet (gvr: %q)\n", gvr.String())
+	removed := removedResources(cr)
+	var missing, notEstablished *schema.GroupVersionResource
+	crdCond := definitionv1alpha1.StageTrue(definitionv1alpha1.TypeCRDEstablished, definitionv1alpha1.ReasonEstablished)
+	statuses := []definitionv1alpha1.ResourceStatus{}
+	for _, gvk := range render.ResourceGVKs(cr) {
+		gvr := deployment.ToGroupVersionResource(gvk)
+		log.Printf("[DBG] Observing (gvk: %s, gvr: %s)\n", gvk.String(), gvr.String())
+
+		crd, err := deployment.GetCRD(ctx, e.kube, gvr)
+		if err != nil {
+			return reconciler.ExternalObservation{}, err
+		}
+
+		status := definitionv1alpha1.ResourceStatus{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+		}
+		switch {
+		case crd == nil || !deployment.CRDHasVersion(crd, gvr.Version):
+			log.Printf("[DBG] CRD does not exists yet (gvr: %q)\n", gvr.String())
+			status.Message = fmt.Sprintf("CRD for '%s' does not exists yet", gvr.String())
+			if missing == nil {
+				missing = &gvr
+			}
+		case !deployment.CRDEstablished(crd):
+			log.Printf("[DBG] CRD not established yet (gvr: %q)\n", gvr.String())
+			status.CRD = crd.Name
+			cond := crdEstablishedCondition(crd)
+			status.Message = cond.Message
+			if notEstablished == nil {
+				notEstablished = &gvr
+				crdCond = cond
+			}
+		default:
+			status.CRD = crd.Name
+			status.Ready = true
+		}
+		statuses = append(statuses, status)
+	}
+	cr.Status.Resources = append(statuses, removed...)
 
+	if missing != nil {
+		cr.SetConditions(definitionv1alpha1.StageFalse(definitionv1alpha1.TypeCRDEstablished,
+			definitionv1alpha1.ReasonNotFound, fmt.Sprintf("CRD for '%s' does not exists yet", missing.String())))
 		cr.SetConditions(rtv1.Unavailable().
-			WithMessage(fmt.Sprintf("CRD for '%s' does not exists yet", gvr.String())))
+			WithMessage(fmt.Sprintf("CRD for '%s' does not exists yet", missing.String())))
 		return reconciler.ExternalObservation{
 			ResourceExists:   false,
 			ResourceUpToDate: true,
 		}, nil
 	}
 
-	log.Printf("[DBG] Searching for Dynamic Controller (gvr: %q)\n", gvr.String())
+	cr.SetConditions(crdCond)
+	if notEstablished != nil {
+		cr.SetConditions(rtv1.Unavailable().
+			WithMessage(fmt.Sprintf("CRD for '%s' not established yet: %s", notEstablished.String(), crdCond.Message)))
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: true,
+		}, nil
+	}
+
+	if len(removed) > 0 {
+		if meta.IsVerbose(cr) {
+			e.log.Debug("Resources removed from the spec", "name", cr.Name, "namespace", cr.Namespace, "count", len(removed))
+		}
 
-	obj, err := deployment.CreateDeployment(gvr, types.NamespacedName{
-		Namespace: cr.Namespace,
-		Name:      cr.Name,
-	})
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: false,
+		}, nil
+	}
+
+	regenerate, err := e.checkUpstream(cr)
+	if err != nil {
+		return reconciler.ExternalObservation{}, fmt.Errorf("checking OAS for changes: %w", err)
+	}
+	if regenerate {
+		e.regenerate = true
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: false,
+		}, nil
+	}
+
+	// The role of the dynamic controller depends on the authentication resources,
+	// which the provider reads through its own role.
+	providerRole := render.ProviderRole(cr, authenticationGVKs(cr))
+	providerRoleOk, providerRoleUpToDate, err := rbactools.LookupClusterRole(ctx, e.kube, &providerRole)
+	if err != nil {
+		return reconciler.ExternalObservation{}, err
+	}
+	if !providerRoleOk || !providerRoleUpToDate {
+		if meta.IsVerbose(cr) {
+			e.log.Debug("Provider role is not up to date", "name", providerRole.Name)
+		}
+
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: false,
+		}, nil
+	}
+
+	gvrs := deployment.ResourceGVRs(&cr.Spec, resourceVersion)
+	log.Printf("[DBG] Searching for Dynamic Controller (gvrs: %v)\n", gvrs)
+
+	obj, rbacOpts, conflict, err := e.controller(ctx, cr)
 	if err != nil {
 		return reconciler.ExternalObservation{}, err
 	}
+	if len(conflict) > 0 {
+		cr.SetConditions(definitionv1alpha1.StageFalse(definitionv1alpha1.TypeControllerReady,
+			definitionv1alpha1.ReasonPoolConflict, conflict))
+		cr.SetConditions(rtv1.Unavailable().WithMessage(conflict))
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: true,
+		}, nil
+	}
+	if !poolUpToDate(cr) {
+		if meta.IsVerbose(cr) {
+			e.log.Debug("Controller pool changed", "name", cr.Name, "namespace", cr.Namespace, "pool", cr.Spec.ControllerPool)
+		}
+
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: false,
+		}, nil
+	}
+	desired := obj.DeepCopy()
 
 	deployOk, deployReady, err := deployment.LookupDeployment(ctx, e.kube, &obj)
 	if err != nil {
@@ -191,9 +393,11 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 	if !deployOk {
 		if meta.IsVerbose(cr) {
 			e.log.Debug("Dynamic Controller not deployed yet",
-				"name", obj.Name, "namespace", obj.Namespace, "gvr", gvr.String())
+				"name", obj.Name, "namespace", obj.Namespace, "gvrs", gvrs)
 		}
 
+		cr.SetConditions(definitionv1alpha1.StageFalse(definitionv1alpha1.TypeControllerReady,
+			definitionv1alpha1.ReasonNotDeployed, fmt.Sprintf("Dynamic Controller '%s' not deployed yet", obj.Name)))
 		cr.SetConditions(rtv1.Unavailable().
 			WithMessage(fmt.Sprintf("Dynamic Controller '%s' not deployed yet", obj.Name)))
 
@@ -206,12 +410,35 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 	if meta.IsVerbose(cr) {
 		e.log.Debug("Dynamic Controller already deployed",
 			"name", obj.Name, "namespace", obj.Namespace,
-			"gvr", gvr.String())
+			"gvrs", gvrs)
+	}
+
+	if !deployment.DeploymentUpToDate(&obj, desired) {
+		if meta.IsVerbose(cr) {
+			e.log.Debug("Dynamic Controller deployment is not up to date",
+				"name", obj.Name, "namespace", obj.Namespace)
+		}
+
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: false,
+		}, nil
 	}
 
 	if !deployReady {
+		cond, err := e.controllerNotReadyCondition(ctx, &obj)
+		if err != nil {
+			return reconciler.ExternalObservation{}, err
+		}
+		// The warning is emitted once per failure, not on every poll while it lasts.
+		previous := cr.GetCondition(definitionv1alpha1.TypeControllerReady)
+		transitioned := previous.Status != cond.Status || previous.Reason != cond.Reason
+		cr.SetConditions(cond)
 		cr.SetConditions(rtv1.Unavailable().
-			WithMessage(fmt.Sprintf("Dynamic Controller '%s' not ready yet", obj.Name)))
+			WithMessage(fmt.Sprintf("Dynamic Controller '%s' not ready yet: %s", obj.Name, cond.Message)))
+		if transitioned && cond.Reason != definitionv1alpha1.ReasonProgressing {
+			e.rec.Event(cr, corev1.EventTypeWarning, string(cond.Reason), cond.Message)
+		}
 
 		return reconciler.ExternalObservation{
 			ResourceExists:   true,
@@ -219,6 +446,49 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 		}, nil
 	}
 
+	cr.SetConditions(definitionv1alpha1.StageTrue(definitionv1alpha1.TypeControllerReady, definitionv1alpha1.ReasonDeployed))
+
+	roleOk, roleUpToDate, err := deployment.LookupRBAC(ctx, rbacOpts)
+	if err != nil {
+		return reconciler.ExternalObservation{}, err
+	}
+	if !roleOk {
+		cr.SetConditions(definitionv1alpha1.StageFalse(definitionv1alpha1.TypeRBACReady,
+			definitionv1alpha1.ReasonRBACMissing, "Dynamic Controller role not found"))
+	} else if !roleUpToDate {
+		cr.SetConditions(definitionv1alpha1.StageFalse(definitionv1alpha1.TypeRBACReady,
+			definitionv1alpha1.ReasonRBACOutdated, "Dynamic Controller role is not up to date"))
+	}
+	if !roleOk || !roleUpToDate {
+		if meta.IsVerbose(cr) {
+			e.log.Debug("Dynamic Controller role is not up to date",
+				"name", cr.Name, "namespace", cr.Namespace)
+		}
+
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: false,
+		}, nil
+	}
+
+	cr.SetConditions(definitionv1alpha1.StageTrue(definitionv1alpha1.TypeRBACReady, definitionv1alpha1.ReasonRBACApplied))
+
+	published, err := e.artifactsUpToDate(ctx, cr)
+	if err != nil {
+		return reconciler.ExternalObservation{}, err
+	}
+	if !published {
+		if meta.IsVerbose(cr) {
+			e.log.Debug("Artifacts are not up to date", "name", cr.Name, "namespace", cr.Namespace)
+		}
+
+		e.publish = true
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: false,
+		}, nil
+	}
+
 	cr.SetConditions(rtv1.Available())
 	return reconciler.ExternalObservation{
 		ResourceExists:   true,
@@ -239,66 +509,46 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 
 	e.log.Debug("Creating RestDefinition", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
 
-	gen, err, errors := generator.GenerateByteSchemas(e.doc, cr.Spec.Resource, cr.Spec.Resource.Identifiers)
-	if err != nil {
-		return fmt.Errorf("generating byte schemas: %w", err)
-	}
-	if meta.IsVerbose(cr) {
-		for _, er := range errors {
-			e.log.Debug("Generating Byte Schemas", "Error:", er)
-		}
+	if retired, err := e.retireFirst(ctx, cr); err != nil || !retired {
+		return err
 	}
 
-	role, err := rbactools.InitRole(types.NamespacedName{
-		Namespace: cr.GetNamespace(),
-		Name:      cr.GetName(),
-	})
+	resourceCRDs, schemas, generationStatus, err := e.generateCRDs(ctx, cr)
 	if err != nil {
-		return fmt.Errorf("initializing role: %w", err)
-	}
-
-	gvk := schema.GroupVersionKind{
-		Group:   cr.Spec.ResourceGroup,
-		Version: resourceVersion,
-		Kind:    text.CapitaliseFirstLetter(cr.Spec.Resource.Kind),
+		return err
 	}
 
-	resource := crdgen.Generate(ctx, crdgen.Options{
-		Managed:                true,
-		WorkDir:                fmt.Sprintf("gen-crds/%s", cr.Spec.Resource.Kind),
-		GVK:                    gvk,
-		Categories:             []string{strings.ToLower(cr.Spec.Resource.Kind)},
-		SpecJsonSchemaGetter:   gen.OASSpecJsonSchemaGetter(),
-		StatusJsonSchemaGetter: gen.OASStatusJsonSchemaGetter(),
-	})
-	if resource.Err != nil {
-		return fmt.Errorf("generating CRD: %w", resource.Err)
-	}
+	cr.Status.Resources = removedResources(cr)
+	for i, crd := range resourceCRDs {
+		err = e.applyResourceCRD(ctx, cr, crd, generationStatus.Digest)
+		if err != nil {
+			return fmt.Errorf("installing CRD: %w", err)
+		}
+		generationStatus.CRDs = append(generationStatus.CRDs, crd.Name)
 
-	crd, err := crds.UnmarshalCRD(resource.Manifest)
-	if err != nil {
-		return fmt.Errorf("unmarshalling CRD: %w", err)
+		gvk := render.ResourceGVK(cr, schemas[i].Resource)
+		cr.Status.Resources = append(cr.Status.Resources, definitionv1alpha1.ResourceStatus{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			CRD:        crd.Name,
+		})
 	}
 
-	err = crds.InstallCRD(ctx, e.kube, crd)
-	if err != nil {
-		return fmt.Errorf("installing CRD: %w", err)
+	first := render.ResourceGVKs(cr)[0]
+	cr.Status.Resource = definitionv1alpha1.KindApiVersion{
+		Kind:       first.Kind,
+		APIVersion: first.GroupVersion().String(),
 	}
-	rbactools.PopulateRole(gvk, &role)
-
+	cr.Status.Authentications = nil
 	for secSchemaPair := e.doc.Model.Components.SecuritySchemes.First(); secSchemaPair != nil; secSchemaPair = secSchemaPair.Next() {
 		authSchemaName, err := generation.GenerateAuthSchemaName(secSchemaPair.Value())
 		if err != nil {
 			e.log.Debug("Generating Auth Schema Name", "Error:", err)
 			continue
 		}
-		gvk := schema.GroupVersionKind{
-			Group:   cr.Spec.ResourceGroup,
-			Version: resourceVersion,
-			Kind:    text.CapitaliseFirstLetter(authSchemaName),
-		}
+		gvk := render.AuthGVK(cr, authSchemaName)
 
-		crdOk, err := deployment.LookupCRD(ctx, e.kube, schema.GroupVersionResource{
+		existing, err := deployment.GetCRD(ctx, e.kube, schema.GroupVersionResource{
 			Group:    cr.Spec.ResourceGroup,
 			Version:  resourceVersion,
 			Resource: flect.Pluralize(strings.ToLower(authSchemaName)),
@@ -306,67 +556,71 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 		if err != nil {
 			return fmt.Errorf("looking up CRD: %w", err)
 		}
-		if crdOk {
+		if existing != nil {
 			e.log.Debug("CRD already exists", "Kind:", authSchemaName)
+			// Only a CRD installed by a previous attempt for cr is recorded: a CRD
+			// shared with other RestDefinitions must not be removed with cr.
+			if deployment.HasOwnerLabels(existing, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}) {
+				generationStatus.CRDs = append(generationStatus.CRDs, existing.Name)
+			}
 			cr.Status.Authentications = append(cr.Status.Authentications, definitionv1alpha1.KindApiVersion{
 				Kind:       gvk.Kind,
 				APIVersion: gvk.GroupVersion().String(),
 			})
-			rbactools.PopulateRole(gvk, &role)
 			continue
 		}
 
-		resource = crdgen.Generate(ctx, crdgen.Options{
-			Managed:                false,
-			WorkDir:                fmt.Sprintf("gen-crds/%s", authSchemaName),
-			GVK:                    gvk,
-			Categories:             []string{strings.ToLower(cr.Spec.Resource.Kind)},
-			SpecJsonSchemaGetter:   gen.OASAuthJsonSchemaGetter(authSchemaName),
-			StatusJsonSchemaGetter: generator.StaticJsonSchemaGetter(),
-		})
-
-		if resource.Err != nil {
-			return fmt.Errorf("generating CRD: %w", resource.Err)
-		}
-
-		crd, err := crds.UnmarshalCRD(resource.Manifest)
+		crd, err := render.AuthCRD(ctx, schemas[0].Generator, cr, authSchemaName)
 		if err != nil {
-			return fmt.Errorf("unmarshalling CRD: %w", err)
+			return err
 		}
 
 		err = crds.InstallCRD(ctx, e.kube, crd)
 		if err != nil {
 			return fmt.Errorf("installing CRD: %w", err)
 		}
+		generationStatus.CRDs = append(generationStatus.CRDs, crd.Name)
 
 		cr.Status.Authentications = append(cr.Status.Authentications, definitionv1alpha1.KindApiVersion{
 			Kind:       gvk.Kind,
 			APIVersion: gvk.GroupVersion().String(),
 		})
+	}
 
-		rbactools.PopulateRole(gvk, &role)
+	if err := e.applyProviderRole(ctx, cr); err != nil {
+		return err
 	}
 
-	err = deployment.Deploy(ctx, deployment.DeployOptions{
-		KubeClient: e.kube,
-		NamespacedName: types.NamespacedName{
-			Namespace: cr.Namespace,
-			Name:      cr.Name,
-		},
-		Spec:            &cr.Spec,
-		ResourceVersion: resourceVersion,
-		Role:            role,
-	})
+	err = e.publishArtifacts(ctx, cr, schemas)
 	if err != nil {
-		return fmt.Errorf("deploying controller: %w", err)
+		return err
 	}
 
-	cr.SetConditions(rtv1.Creating())
-	cr.Status.Resource = definitionv1alpha1.KindApiVersion{
-		Kind:       gvk.Kind,
-		APIVersion: gvk.GroupVersion().String(),
+	if len(cr.Spec.ControllerPool) > 0 {
+		conflict, err := e.applyPool(ctx, cr)
+		if err != nil {
+			return fmt.Errorf("joining controller pool: %w", err)
+		}
+		if len(conflict) > 0 {
+			cr.SetConditions(definitionv1alpha1.StageFalse(definitionv1alpha1.TypeControllerReady,
+				definitionv1alpha1.ReasonPoolConflict, conflict))
+		}
+	} else if err := e.deploy(ctx, cr); err != nil {
+		return err
 	}
+	if err := e.uninstallLegacy(ctx, cr); err != nil {
+		return err
+	}
+
+	cr.SetConditions(rtv1.Creating())
 	cr.Status.OASPath = cr.Spec.OASPath
+	cr.Status.Generation = generationStatus
+	if _, err := e.retireLast(ctx, cr); err != nil {
+		return err
+	}
+	if err := e.recordInventory(cr); err != nil {
+		return err
+	}
 
 	err = e.kube.Status().Update(ctx, cr)
 
@@ -377,49 +631,1043 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) error {
-	return nil
-}
-
-func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	cr, ok := mg.(*definitionv1alpha1.RestDefinition)
 	if !ok {
 		return errors.New(errNotRestDefinition)
 	}
 
-	if !meta.IsActionAllowed(cr, meta.ActionDelete) {
-		e.log.Debug("External resource should not be deleted by provider, skip deleting.")
+	if !meta.IsActionAllowed(cr, meta.ActionUpdate) {
+		e.log.Debug("External resource should not be updated by provider, skip updating.")
 		return nil
 	}
 
-	e.log.Debug("Deleting RestDefinition", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
+	if retired, err := e.retireFirst(ctx, cr); err != nil || !retired {
+		return err
+	}
+	if err := e.update(ctx, cr); err != nil {
+		return err
+	}
+	_, err := e.retireLast(ctx, cr)
+	return err
+}
 
-	opts := deployment.UndeployOptions{
+// update applies the desired state of the CRDs, artifacts, dynamic controller and
+// RBAC of cr.
+func (e *external) update(ctx context.Context, cr *definitionv1alpha1.RestDefinition) error {
+	if err := e.applyProviderRole(ctx, cr); err != nil {
+		return err
+	}
+	if err := e.uninstallLegacy(ctx, cr); err != nil {
+		return err
+	}
+
+	if e.regenerate {
+		if err := e.regenerateCRD(ctx, cr); err != nil {
+			return fmt.Errorf("regenerating CRD: %w", err)
+		}
+	} else if e.publish {
+		// Observe requests the publication only when e.doc is the revision of the
+		// last generation, so that the artifacts describe the installed CRDs.
+		schemas, _, err := render.GenerateSchemas(e.doc, cr)
+		if err != nil {
+			return err
+		}
+		if err := e.publishArtifacts(ctx, cr, schemas); err != nil {
+			return err
+		}
+	}
+
+	if len(cr.Spec.ControllerPool) > 0 {
+		conflict, err := e.applyPool(ctx, cr)
+		if err != nil {
+			return err
+		}
+		if len(conflict) > 0 {
+			cr.SetConditions(definitionv1alpha1.StageFalse(definitionv1alpha1.TypeControllerReady,
+				definitionv1alpha1.ReasonPoolConflict, conflict))
+			return nil
+		}
+		cr.SetConditions(definitionv1alpha1.StageTrue(definitionv1alpha1.TypeRBACReady, definitionv1alpha1.ReasonRBACApplied))
+		return e.recordInventory(cr)
+	}
+	if cr.Status.ControllerPool != nil {
+		// The dedicated controller is installed before leaving the pool, so that
+		// the resources of cr are always served.
+		if err := e.deploy(ctx, cr); err != nil {
+			return err
+		}
+		if err := e.leavePool(ctx, cr, cr.Status.ControllerPool.Name); err != nil {
+			return err
+		}
+		cr.Status.ControllerPool = nil
+	}
+
+	dep, err := e.desiredDeployment(cr)
+	if err != nil {
+		return fmt.Errorf("computing deployment: %w", err)
+	}
+	depChanged, err := deployment.ApplyDeployment(ctx, e.kube, &dep)
+	if err != nil {
+		return fmt.Errorf("updating deployment: %w", err)
+	}
+	if depChanged {
+		e.log.Debug("Restored Dynamic Controller deployment", "name", dep.Name, "namespace", dep.Namespace)
+		e.rec.Eventf(cr, corev1.EventTypeNormal, "DeploymentUpdated",
+			"Deployment of Dynamic Controller '%s/%s' restored", dep.Namespace, dep.Name)
+	}
+
+	rbacOpts, err := e.rbacOptions(ctx, cr)
+	if err != nil {
+		return fmt.Errorf("computing role: %w", err)
+	}
+
+	changed, err := deployment.ApplyRBAC(ctx, rbacOpts)
+	if err != nil {
+		return fmt.Errorf("updating role: %w", err)
+	}
+	cr.SetConditions(definitionv1alpha1.StageTrue(definitionv1alpha1.TypeRBACReady, definitionv1alpha1.ReasonRBACApplied))
+	if changed {
+		e.log.Debug("Updated Dynamic Controller role", "name", cr.Name, "namespace", cr.Namespace)
+		e.rec.Eventf(cr, corev1.EventTypeNormal, "RoleUpdated",
+			"Role of Dynamic Controller '%s/%s' updated", cr.Namespace, cr.Name)
+	}
+
+	return e.recordInventory(cr)
+}
+
+// uninstallLegacy removes the dynamic controller of cr installed under the names
+// used before naming.ControllerName, once its replacement is in place.
+func (e *external) uninstallLegacy(ctx context.Context, cr *definitionv1alpha1.RestDefinition) error {
+	err := deployment.UninstallLegacy(ctx, e.kube, e.reader, types.NamespacedName{
+		Namespace: cr.Namespace,
+		Name:      cr.Name,
+	}, deployment.ResourceGVRs(&cr.Spec, resourceVersion), e.log.Debug)
+	if err != nil {
+		return fmt.Errorf("uninstalling legacy controller: %w", err)
+	}
+	return nil
+}
+
+// deploy installs the dedicated dynamic controller of cr, its ServiceAccount and its RBAC.
+func (e *external) deploy(ctx context.Context, cr *definitionv1alpha1.RestDefinition) error {
+	role, err := e.desiredRole(cr)
+	if err != nil {
+		return fmt.Errorf("computing role: %w", err)
+	}
+	secrets, connectionSecrets, err := e.desiredSecrets(ctx, cr)
+	if err != nil {
+		return fmt.Errorf("computing role: %w", err)
+	}
+
+	err = deployment.Deploy(ctx, deployment.DeployOptions{
 		KubeClient: e.kube,
 		NamespacedName: types.NamespacedName{
 			Namespace: cr.Namespace,
 			Name:      cr.Name,
 		},
-		GVR: schema.GroupVersionResource{
-			Group:    cr.Spec.ResourceGroup,
-			Version:  resourceVersion,
-			Resource: flect.Pluralize(strings.ToLower(cr.Spec.Resource.Kind)),
-		},
-		Log:             e.log.Debug,
-		SecuritySchemes: e.doc.Model.Components.SecuritySchemes,
+		Spec:              &cr.Spec,
+		ResourceVersion:   resourceVersion,
+		Role:              role,
+		Secrets:           secrets,
+		ConnectionSecrets: connectionSecrets,
+		ExtendedArgs:      e.extendedArgs,
+	})
+	if err != nil {
+		return fmt.Errorf("deploying controller: %w", err)
 	}
-	if meta.IsVerbose(cr) {
-		opts.Log = e.log.Debug
+	return nil
+}
+
+// controller returns the desired Deployment and RBAC of the dynamic controller
+// serving the resources of cr: its dedicated one, or the shared one of its pool.
+// Returns the reason why the pool refuses cr, if any.
+func (e *external) controller(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (appsv1.Deployment, deployment.RBACOptions, string, error) {
+	if len(cr.Spec.ControllerPool) == 0 {
+		dep, err := e.desiredDeployment(cr)
+		if err != nil {
+			return appsv1.Deployment{}, deployment.RBACOptions{}, "", err
+		}
+		rbacOpts, err := e.rbacOptions(ctx, cr)
+		return dep, rbacOpts, "", err
 	}
 
-	err := deployment.Undeploy(ctx, opts)
+	pool, err := e.desiredPool(ctx, cr.Namespace, cr.Spec.ControllerPool)
 	if err != nil {
-		return fmt.Errorf("uninstalling controller: %w", err)
+		return appsv1.Deployment{}, deployment.RBACOptions{}, "", err
+	}
+	if msg, ok := pool.Conflicts[types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}]; ok {
+		return appsv1.Deployment{}, deployment.RBACOptions{}, msg, nil
 	}
+	if poolUpToDate(cr) {
+		cr.Status.ControllerPool.Members = len(pool.Members)
+	}
+	dep, err := pool.Deployment()
+	return dep, pool.RBACOptions(e.kube, nil), "", err
+}
 
-	err = e.kube.Status().Update(ctx, cr)
+// generateCRDs generates the CRD of each resource of cr from the OAS document and
+// reports the outcome in the SchemaGenerated condition. The returned schemas are
+// in the order of the CRDs, the first ones also provide the schemas of the
+// authentication CRDs.
+func (e *external) generateCRDs(ctx context.Context, cr *definitionv1alpha1.RestDefinition) ([]*apiextensionsv1.CustomResourceDefinition, []render.Schemas, *definitionv1alpha1.GenerationStatus, error) {
+	schemas, warnings, err := render.GenerateSchemas(e.doc, cr)
+	if err != nil {
+		cr.SetConditions(definitionv1alpha1.StageFalse(definitionv1alpha1.TypeSchemaGenerated,
+			definitionv1alpha1.ReasonGenerationFailed, err.Error()))
+		return nil, nil, nil, err
+	}
+	for _, er := range warnings {
+		e.log.Info("Generating Byte Schemas", "Class:", generator.WarningClass(er), "Warning:", er)
+	}
+	generationStatus := &definitionv1alpha1.GenerationStatus{
+		Digest:   render.Digest(schemas),
+		Revision: e.revision.Digest,
+		Warnings: generationWarnings(warnings),
+	}
+	for _, s := range schemas {
+		for _, action := range generator.ResolveActions(e.doc, s.Resource) {
+			action.Kind = s.Resource.Kind
+			generationStatus.Actions = append(generationStatus.Actions, action)
+		}
+	}
+	e.recordWarnings(cr, generationStatus.Warnings)
+	if len(generationStatus.Warnings) > 0 {
+		cond := definitionv1alpha1.StageTrue(definitionv1alpha1.TypeSchemaGenerated, definitionv1alpha1.ReasonGeneratedWithWarnings)
+		cr.SetConditions(cond.WithMessage(fmt.Sprintf("%d warning(s), see status.generation.warnings", len(generationStatus.Warnings))))
+	} else {
+		cr.SetConditions(definitionv1alpha1.StageTrue(definitionv1alpha1.TypeSchemaGenerated, definitionv1alpha1.ReasonGenerated))
+	}
 
-	e.log.Debug("Deleting RestDefinition", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
-	e.rec.Eventf(cr, corev1.EventTypeNormal, "RestDefinitionDeleting",
-		"RestDefinition '%s/%s' deleting", cr.Spec.Resource.Kind, cr.Spec.ResourceGroup)
-	return err
+	res := make([]*apiextensionsv1.CustomResourceDefinition, 0, len(schemas))
+	for _, s := range schemas {
+		crd, err := render.ResourceCRD(ctx, s.Generator, cr, s.Resource)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		res = append(res, crd)
+	}
+
+	return res, schemas, generationStatus, nil
+}
+
+// needsScaffold returns true if a resource of cr has no verbsDescription.
+func needsScaffold(cr *definitionv1alpha1.RestDefinition) bool {
+	for _, r := range cr.Spec.AllResources() {
+		if len(r.VerbsDescription) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// scaffold sets the verbsDescription, and the identifiers when not set, of the
+// resources of cr without verbsDescription to the ones inferred from the paths
+// of the OAS document.
+func (e *external) scaffold(cr *definitionv1alpha1.RestDefinition) error {
+	targets := []*definitionv1alpha1.Resource{}
+	if len(cr.Spec.Resource.Kind) > 0 {
+		targets = append(targets, &cr.Spec.Resource)
+	}
+	for i := range cr.Spec.Resources {
+		targets = append(targets, &cr.Spec.Resources[i])
+	}
+
+	for _, target := range targets {
+		if len(target.VerbsDescription) > 0 {
+			continue
+		}
+		res, err := scaffold.Resource(e.doc, target.Kind)
+		if err != nil {
+			cr.SetConditions(definitionv1alpha1.StageFalse(definitionv1alpha1.TypeSpecValid,
+				definitionv1alpha1.ReasonInvalid, err.Error()))
+			return fmt.Errorf("scaffolding resource: %w", err)
+		}
+
+		target.VerbsDescription = res.VerbsDescription
+		if len(target.Identifiers) == 0 {
+			target.Identifiers = res.Identifiers
+		}
+
+		actions := make([]string, 0, len(res.VerbsDescription))
+		for _, v := range res.VerbsDescription {
+			actions = append(actions, fmt.Sprintf("%s (%s %s)", v.Action, v.Method, v.Path))
+		}
+		e.rec.Eventf(cr, corev1.EventTypeNormal, "Scaffolded",
+			"Inferred verbsDescription of %s: %s", target.Kind, strings.Join(actions, ", "))
+	}
+	return nil
+}
+
+// checkUpstream compares, at most once per spec.refreshInterval, the digest of the
+// schemas generated from the current OAS document with the digest of the CRD.
+// Returns true when the CRD must be regenerated: on change with the Automatic
+// policy, or once the change is approved with the Manual policy.
+func (e *external) checkUpstream(cr *definitionv1alpha1.RestDefinition) (bool, error) {
+	if cr.Spec.RefreshInterval == nil {
+		return false, nil
+	}
+	if cr.Status.Refresh == nil {
+		cr.Status.Refresh = &definitionv1alpha1.RefreshStatus{}
+	}
+	refresh := cr.Status.Refresh
+
+	now := metav1.Now()
+	if refresh.LastCheckTime == nil || now.Sub(refresh.LastCheckTime.Time) >= cr.Spec.RefreshInterval.Duration {
+		schemas, _, err := render.GenerateSchemas(e.doc, cr)
+		if err != nil {
+			return false, err
+		}
+		refresh.LastCheckTime = &now
+		refresh.ETag = e.revision.ETag
+		refresh.UpstreamDigest = render.Digest(schemas)
+	}
+
+	current := ""
+	if cr.Status.Generation != nil {
+		current = cr.Status.Generation.Digest
+	}
+	if len(refresh.UpstreamDigest) == 0 || refresh.UpstreamDigest == current {
+		refresh.PendingDigest = ""
+		return false, nil
+	}
+
+	if cr.Spec.RegenerationPolicy == definitionv1alpha1.RegenerationManual &&
+		cr.GetAnnotations()[definitionv1alpha1.AnnotationApproveRegeneration] != refresh.UpstreamDigest {
+		if refresh.PendingDigest != refresh.UpstreamDigest {
+			e.rec.Eventf(cr, corev1.EventTypeNormal, "RegenerationPending",
+				"OAS changed upstream (%s -> %s), set the '%s' annotation to '%s' to regenerate the CRD",
+				current, refresh.UpstreamDigest, definitionv1alpha1.AnnotationApproveRegeneration, refresh.UpstreamDigest)
+		}
+		refresh.PendingDigest = refresh.UpstreamDigest
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// regenerateCRD updates in place the CRDs of the resources of cr with the schemas
+// generated from the current OAS document.
+func (e *external) regenerateCRD(ctx context.Context, cr *definitionv1alpha1.RestDefinition) error {
+	resourceCRDs, schemas, generationStatus, err := e.generateCRDs(ctx, cr)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(resourceCRDs))
+	for _, crd := range resourceCRDs {
+		err = e.applyResourceCRD(ctx, cr, crd, generationStatus.Digest)
+		if err != nil {
+			return fmt.Errorf("applying CRD: %w", err)
+		}
+		names = append(names, crd.Name)
+	}
+	err = e.publishArtifacts(ctx, cr, schemas)
+	if err != nil {
+		return err
+	}
+
+	previous := ""
+	if cr.Status.Generation != nil {
+		previous = cr.Status.Generation.Digest
+		generationStatus.CRDs = cr.Status.Generation.CRDs
+	}
+	for _, name := range names {
+		if !slices.Contains(generationStatus.CRDs, name) {
+			generationStatus.CRDs = append(generationStatus.CRDs, name)
+		}
+	}
+	cr.Status.Generation = generationStatus
+
+	if cr.Status.Refresh == nil {
+		cr.Status.Refresh = &definitionv1alpha1.RefreshStatus{}
+	}
+	cr.Status.Refresh.PendingDigest = ""
+	cr.Status.Refresh.LastRegeneration = &definitionv1alpha1.Regeneration{
+		Time:           metav1.Now(),
+		PreviousDigest: previous,
+		Digest:         generationStatus.Digest,
+	}
+
+	crdNames := strings.Join(names, ", ")
+	e.log.Info("CRD regenerated", "name", crdNames, "previousDigest", previous, "digest", generationStatus.Digest)
+	e.rec.Eventf(cr, corev1.EventTypeNormal, "CRDRegenerated",
+		"CRD '%s' regenerated (%s -> %s)", crdNames, previous, generationStatus.Digest)
+	return nil
+}
+
+// applyResourceCRD installs the CRD of the resource of cr or updates it in place.
+// The schema of an installed CRD is compared with the desired one: the changes are
+// reported in status and breaking changes are refused, unless allowed by the
+// AnnotationAllowBreakingChanges annotation set to digest, the digest of the
+// schemas of crd.
+func (e *external) applyResourceCRD(ctx context.Context, cr *definitionv1alpha1.RestDefinition, crd *apiextensionsv1.CustomResourceDefinition, digest string) error {
+	installed := apiextensionsv1.CustomResourceDefinition{}
+	err := e.kube.Get(ctx, client.ObjectKeyFromObject(crd), &installed)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		changes := crds.DiffCRD(&installed, crd, resourceVersion)
+		breaking := crds.HasBreakingChanges(changes)
+		allowed := cr.GetAnnotations()[definitionv1alpha1.AnnotationAllowBreakingChanges] == digest
+
+		if len(changes) > 0 || (cr.Status.SchemaChanges != nil && cr.Status.SchemaChanges.Blocked) {
+			cr.Status.SchemaChanges = &definitionv1alpha1.SchemaChangesStatus{
+				Blocked: breaking && !allowed,
+				Digest:  digest,
+				Changes: changes,
+			}
+		}
+
+		if breaking && !allowed {
+			msg := fmt.Sprintf("CRD '%s' has breaking schema changes (see status.schemaChanges), set the '%s' annotation to '%s' to apply them",
+				crd.Name, definitionv1alpha1.AnnotationAllowBreakingChanges, digest)
+			cr.SetConditions(definitionv1alpha1.StageFalse(definitionv1alpha1.TypeSchemaGenerated,
+				definitionv1alpha1.ReasonBreakingChanges, msg))
+			e.rec.Event(cr, corev1.EventTypeWarning, "BreakingSchemaChanges", msg)
+			return errors.New(msg)
+		}
+		if len(changes) > 0 {
+			e.rec.Eventf(cr, corev1.EventTypeNormal, "SchemaChanged",
+				"CRD '%s' schema changed: %d change(s), %d breaking", crd.Name, len(changes), countBreaking(changes))
+		}
+	}
+
+	return crds.ApplyCRD(ctx, e.kube, crd)
+}
+
+// publishArtifacts applies the ConfigMap holding the JSON schemas and the TypeScript
+// definitions generated for cr and the one holding its operation map, and references
+// them in status.
+func (e *external) publishArtifacts(ctx context.Context, cr *definitionv1alpha1.RestDefinition, schemas []render.Schemas) error {
+	cm, err := render.Artifacts(cr, schemas)
+	if err != nil {
+		return fmt.Errorf("rendering artifacts: %w", err)
+	}
+	changed, err := deployment.ApplyConfigMap(ctx, e.kube, cm)
+	if err != nil {
+		return fmt.Errorf("publishing artifacts: %w", err)
+	}
+
+	if changed {
+		e.log.Debug("Published artifacts", "name", cm.Name, "namespace", cm.Namespace)
+		e.rec.Eventf(cr, corev1.EventTypeNormal, "ArtifactsPublished",
+			"Artifacts published in ConfigMap '%s/%s'", cm.Namespace, cm.Name)
+	}
+
+	ops, err := render.Operations(e.doc, cr)
+	if err != nil {
+		return fmt.Errorf("rendering operation map: %w", err)
+	}
+	changed, err = deployment.ApplyConfigMap(ctx, e.kube, ops)
+	if err != nil {
+		return fmt.Errorf("publishing operation map: %w", err)
+	}
+	if changed {
+		e.log.Debug("Published operation map", "name", ops.Name, "namespace", ops.Namespace)
+		e.rec.Eventf(cr, corev1.EventTypeNormal, "OperationsPublished",
+			"Operation map published in ConfigMap '%s/%s'", ops.Namespace, ops.Name)
+	}
+
+	cr.Status.Artifacts = &definitionv1alpha1.ArtifactsStatus{
+		ConfigMapRef:  rtv1.Reference{Name: cm.Name, Namespace: cm.Namespace},
+		OperationsRef: &rtv1.Reference{Name: ops.Name, Namespace: ops.Namespace},
+		Digest:        render.Digest(schemas),
+	}
+	return nil
+}
+
+// artifactsUpToDate returns false if the artifacts or the operation map of cr must
+// be published again. They are rendered from the OAS document, so they can only be
+// restored while the current document is the revision of the last CRD generation:
+// otherwise they are left as published with the installed CRDs, until the CRDs
+// are regenerated as allowed by the regeneration policy of cr.
+func (e *external) artifactsUpToDate(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (bool, error) {
+	if !e.atGeneration(cr) {
+		if meta.IsVerbose(cr) {
+			e.log.Debug("OAS document differs from the generated revision, artifacts left as published",
+				"name", cr.Name, "namespace", cr.Namespace)
+		}
+		return true, nil
+	}
+	if cr.Status.Artifacts == nil || cr.Status.Artifacts.Digest != cr.Status.Generation.Digest {
+		return false, nil
+	}
+
+	artifactsOk, err := deployment.LookupConfigMap(ctx, e.kube, deployment.ArtifactsNamespacedName(types.NamespacedName{
+		Namespace: cr.Namespace,
+		Name:      cr.Name,
+	}))
+	if err != nil || !artifactsOk {
+		return false, err
+	}
+
+	desired, err := render.Operations(e.doc, cr)
+	if err != nil {
+		return false, fmt.Errorf("rendering operation map: %w", err)
+	}
+	live := corev1.ConfigMap{}
+	err = e.kube.Get(ctx, client.ObjectKeyFromObject(desired), &live)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return maps.Equal(live.Data, desired.Data), nil
+}
+
+// atGeneration returns true if the current OAS document is the revision the CRDs
+// of cr were last generated from.
+func (e *external) atGeneration(cr *definitionv1alpha1.RestDefinition) bool {
+	return cr.Status.Generation != nil && len(cr.Status.Generation.Revision) > 0 &&
+		cr.Status.Generation.Revision == e.revision.Digest
+}
+
+func countBreaking(changes []definitionv1alpha1.SchemaChange) int {
+	n := 0
+	for _, c := range changes {
+		if c.Breaking {
+			n++
+		}
+	}
+	return n
+}
+
+// desiredDeployment renders the deployment of the dynamic controller shared by
+// the resources of cr.
+func (e *external) desiredDeployment(cr *definitionv1alpha1.RestDefinition) (appsv1.Deployment, error) {
+	return deployment.CreateDeployment(deployment.ResourceGVRs(&cr.Spec, resourceVersion), types.NamespacedName{
+		Namespace: cr.Namespace,
+		Name:      cr.Name,
+	}, deployment.WatchNamespaces(&cr.Spec, cr.Namespace), e.extendedArgs)
+}
+
+func (e *external) rbacOptions(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (deployment.RBACOptions, error) {
+	role, err := e.desiredRole(cr)
+	if err != nil {
+		return deployment.RBACOptions{}, err
+	}
+	secrets, connectionSecrets, err := e.desiredSecrets(ctx, cr)
+	if err != nil {
+		return deployment.RBACOptions{}, err
+	}
+
+	return deployment.RBACOptions{
+		KubeClient: e.kube,
+		NamespacedName: types.NamespacedName{
+			Namespace: cr.Namespace,
+			Name:      cr.Name,
+		},
+		Rules:             role.Rules,
+		WatchNamespaces:   deployment.WatchNamespaces(&cr.Spec, cr.Namespace),
+		Secrets:           secrets,
+		ConnectionSecrets: connectionSecrets,
+	}, nil
+}
+
+// controllerNotReadyCondition describes why the rollout of the dynamic controller
+// is not done. Crashing containers and exceeded progress deadlines are reported
+// with their own reasons.
+func (e *external) controllerNotReadyCondition(ctx context.Context, obj *appsv1.Deployment) (rtv1.Condition, error) {
+	rollout := deployment.RolloutStatus(obj)
+	if !rollout.Failed {
+		crashLoop, err := deployment.LookupCrashLoop(ctx, e.reader, obj)
+		if err != nil {
+			return rtv1.Condition{}, fmt.Errorf("looking up dynamic controller pods: %w", err)
+		}
+		if crashLoop != nil {
+			rollout = *crashLoop
+		}
+	}
+
+	reason := definitionv1alpha1.ReasonProgressing
+	switch rollout.Reason {
+	case deployment.ReasonProgressDeadlineExceeded:
+		reason = definitionv1alpha1.ReasonProgressDeadlineExceeded
+	case deployment.ReasonCrashLoopBackOff:
+		reason = definitionv1alpha1.ReasonCrashLoopBackOff
+	}
+
+	return definitionv1alpha1.StageFalse(definitionv1alpha1.TypeControllerReady, reason, rollout.Message), nil
+}
+
+// crdEstablishedCondition maps the NamesAccepted and Established conditions of
+// the generated CRD to the CRDEstablished condition.
+func crdEstablishedCondition(crd *apiextensionsv1.CustomResourceDefinition) rtv1.Condition {
+	names := deployment.CRDCondition(crd, apiextensionsv1.NamesAccepted)
+	if names.Status != apiextensionsv1.ConditionTrue {
+		return definitionv1alpha1.StageFalse(definitionv1alpha1.TypeCRDEstablished,
+			definitionv1alpha1.ReasonNamesNotAccepted, conditionMessage(names.Reason, names.Message))
+	}
+
+	established := deployment.CRDCondition(crd, apiextensionsv1.Established)
+	if established.Status != apiextensionsv1.ConditionTrue {
+		return definitionv1alpha1.StageFalse(definitionv1alpha1.TypeCRDEstablished,
+			definitionv1alpha1.ReasonNotEstablished, conditionMessage(established.Reason, established.Message))
+	}
+
+	return definitionv1alpha1.StageTrue(definitionv1alpha1.TypeCRDEstablished, definitionv1alpha1.ReasonEstablished)
+}
+
+func conditionMessage(reason, message string) string {
+	if len(reason) == 0 {
+		return message
+	}
+	if len(message) == 0 {
+		return reason
+	}
+	return fmt.Sprintf("%s: %s", reason, message)
+}
+
+// generationWarnings converts the non fatal errors of the generator to status warnings,
+// dropping duplicates.
+func generationWarnings(errs []error) []definitionv1alpha1.GenerationWarning {
+	res := []definitionv1alpha1.GenerationWarning{}
+	seen := map[definitionv1alpha1.GenerationWarning]struct{}{}
+	for _, err := range errs {
+		w := definitionv1alpha1.GenerationWarning{
+			Class:   generator.WarningClass(err),
+			Message: err.Error(),
+		}
+		if _, ok := seen[w]; ok {
+			continue
+		}
+		seen[w] = struct{}{}
+		res = append(res, w)
+	}
+	return res
+}
+
+// recordWarnings emits one event per warning class.
+func (e *external) recordWarnings(cr *definitionv1alpha1.RestDefinition, warnings []definitionv1alpha1.GenerationWarning) {
+	classes := []string{}
+	messages := map[string][]string{}
+	for _, w := range warnings {
+		if _, ok := messages[w.Class]; !ok {
+			classes = append(classes, w.Class)
+		}
+		messages[w.Class] = append(messages[w.Class], w.Message)
+	}
+
+	for _, class := range classes {
+		e.rec.Eventf(cr, corev1.EventTypeWarning, class,
+			"%d generation warning(s): %s", len(messages[class]), strings.Join(messages[class], "; "))
+	}
+}
+
+// desiredRole computes the least privilege role of the dynamic controller from the
+// kinds recorded in the status. Access to the referenced secrets is granted apart,
+// by namespace (see desiredSecrets).
+func (e *external) desiredRole(cr *definitionv1alpha1.RestDefinition) (rbacv1.Role, error) {
+	return render.Role(cr, authenticationGVKs(cr))
+}
+
+// desiredSecrets looks up, by namespace, the secrets referenced by the authentication
+// resources of cr and the connection secrets created by its dynamic controller, in the
+// namespaces it watches. The authentication resources and the secrets are read through
+// the API reader, since the provider neither caches nor watches them: changes are
+// picked up at the next poll of cr.
+func (e *external) desiredSecrets(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (secrets, connectionSecrets map[string][]string, err error) {
+	watchNamespaces := deployment.WatchNamespaces(&cr.Spec, cr.GetNamespace())
+	if watchNamespaces == nil {
+		// All namespaces
+		watchNamespaces = []string{""}
+	}
+	connectionGVKs := []schema.GroupVersionKind{}
+	for _, res := range cr.Spec.AllResources() {
+		if len(res.ConnectionDetails) > 0 {
+			connectionGVKs = append(connectionGVKs, render.ResourceGVK(cr, res))
+		}
+	}
+
+	secrets, connectionSecrets = map[string][]string{}, map[string][]string{}
+	for _, ns := range watchNamespaces {
+		names, err := rbactools.LookupSecretNames(ctx, e.reader, ns, authenticationGVKs(cr))
+		if err != nil {
+			return nil, nil, fmt.Errorf("looking up referenced secrets: %w", err)
+		}
+		maps.Copy(secrets, names)
+
+		if len(connectionGVKs) == 0 {
+			continue
+		}
+		names, err = rbactools.LookupConnectionSecretNames(ctx, e.reader, ns, connectionGVKs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("looking up connection secrets: %w", err)
+		}
+		maps.Copy(connectionSecrets, names)
+	}
+	return secrets, connectionSecrets, nil
+}
+
+// authenticationGVKs returns the kinds of the authentication resources recorded in the status of cr.
+func authenticationGVKs(cr *definitionv1alpha1.RestDefinition) []schema.GroupVersionKind {
+	res := make([]schema.GroupVersionKind, 0, len(cr.Status.Authentications))
+	for _, auth := range cr.Status.Authentications {
+		res = append(res, schema.FromAPIVersionAndKind(auth.APIVersion, auth.Kind))
+	}
+	return res
+}
+
+// applyProviderRole grants the provider access to the kinds generated for cr, through
+// a ClusterRole aggregated into its own role.
+func (e *external) applyProviderRole(ctx context.Context, cr *definitionv1alpha1.RestDefinition) error {
+	role := render.ProviderRole(cr, authenticationGVKs(cr))
+	changed, err := rbactools.ApplyClusterRole(ctx, e.kube, &role)
+	if err != nil {
+		return fmt.Errorf("applying provider role: %w", err)
+	}
+	if changed {
+		e.log.Debug("Updated provider role", "name", role.Name)
+	}
+	return nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*definitionv1alpha1.RestDefinition)
+	if !ok {
+		return errors.New(errNotRestDefinition)
+	}
+
+	if !meta.IsActionAllowed(cr, meta.ActionDelete) {
+		e.log.Debug("External resource should not be deleted by provider, skip deleting.")
+		return nil
+	}
+
+	e.log.Debug("Deleting RestDefinition", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
+
+	drained, err := e.drainInstances(ctx, cr)
+	if err != nil {
+		return fmt.Errorf("draining custom resources: %w", err)
+	}
+	if !drained {
+		return e.kube.Status().Update(ctx, cr)
+	}
+	cr.Status.Deletion = &definitionv1alpha1.DeletionStatus{
+		Phase:   definitionv1alpha1.DeletionPhaseUndeploying,
+		Message: "removing the dynamic controller, its RBAC and the CRDs",
+	}
+	if err := e.leavePools(ctx, cr); err != nil {
+		return err
+	}
+
+	shared, err := e.sharedCRDs(ctx, cr)
+	if err != nil {
+		return fmt.Errorf("looking up shared CRDs: %w", err)
+	}
+	authentications := []definitionv1alpha1.KindApiVersion{}
+	for _, auth := range cr.Status.Authentications {
+		name := deployment.AuthGroupResource(auth).String()
+		if cr.Status.Generation != nil && slices.Contains(cr.Status.Generation.CRDs, name) && !shared[name] {
+			authentications = append(authentications, auth)
+		}
+	}
+	inventory := []definitionv1alpha1.InventoryItem{}
+	for _, item := range cr.Status.Inventory {
+		if item.Kind != "CustomResourceDefinition" || !shared[item.Name] {
+			inventory = append(inventory, item)
+		}
+	}
+
+	// The kinds removed from the spec and not retired yet are uninstalled too.
+	gvrs := []schema.GroupVersionResource{}
+	for _, gvk := range render.RecordedGVKs(cr) {
+		gvrs = append(gvrs, deployment.ToGroupVersionResource(gvk))
+	}
+
+	opts := deployment.UndeployOptions{
+		KubeClient: e.kube,
+		Reader:     e.reader,
+		NamespacedName: types.NamespacedName{
+			Namespace: cr.Namespace,
+			Name:      cr.Name,
+		},
+		GVRs:            gvrs,
+		Log:             e.log.Debug,
+		Authentications: authentications,
+		WatchNamespaces: deployment.WatchNamespaces(&cr.Spec, cr.Namespace),
+		Inventory:       inventory,
+	}
+	if meta.IsVerbose(cr) {
+		opts.Log = e.log.Debug
+	}
+
+	err = deployment.Undeploy(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("uninstalling controller: %w", err)
+	}
+
+	err = e.kube.Status().Update(ctx, cr)
+
+	e.log.Debug("Deleting RestDefinition", "Kind:", cr.Spec.Resource.Kind, "Group:", cr.Spec.ResourceGroup)
+	e.rec.Eventf(cr, corev1.EventTypeNormal, "RestDefinitionDeleting",
+		"RestDefinition '%s/%s' deleting", cr.Spec.Resource.Kind, cr.Spec.ResourceGroup)
+	return err
+}
+
+// sharedCRDs returns the names of the authentication CRDs listed in the
+// status.authentications of RestDefinitions other than cr, which are kept when cr is deleted.
+func (e *external) sharedCRDs(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (map[string]bool, error) {
+	list := definitionv1alpha1.RestDefinitionList{}
+	if err := e.kube.List(ctx, &list); err != nil {
+		return nil, err
+	}
+	res := map[string]bool{}
+	for _, el := range list.Items {
+		if el.Namespace == cr.Namespace && el.Name == cr.Name {
+			continue
+		}
+		for _, auth := range el.Status.Authentications {
+			res[deployment.AuthGroupResource(auth).String()] = true
+		}
+	}
+	return res, nil
+}
+
+// recordInventory records in status the objects installed for cr: the CRDs of
+// the last generation, the role of the provider on them, the artifacts and operations
+// ConfigMaps and the dedicated dynamic controller with its RBAC. Items no longer desired
+// stay recorded until the deletion of cr, although stale RBAC is pruned by ApplyRBAC.
+func (e *external) recordInventory(cr *definitionv1alpha1.RestDefinition) error {
+	nn := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+
+	objs := []client.Object{}
+	if cr.Status.Generation != nil {
+		for _, name := range cr.Status.Generation.CRDs {
+			objs = append(objs, &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+			})
+		}
+	}
+	providerRole := render.ProviderRole(cr, authenticationGVKs(cr))
+	objs = append(objs, &providerRole)
+	artifacts := deployment.ArtifactsNamespacedName(nn)
+	operations := deployment.OperationsNamespacedName(nn)
+	objs = append(objs, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: artifacts.Name, Namespace: artifacts.Namespace},
+	}, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: operations.Name, Namespace: operations.Namespace},
+	})
+	// The objects of a shared dynamic controller belong to its pool.
+	if len(cr.Spec.ControllerPool) == 0 {
+		objs = append(objs, deployment.DesiredRBAC(deployment.RBACOptions{
+			NamespacedName:  nn,
+			WatchNamespaces: deployment.WatchNamespaces(&cr.Spec, cr.Namespace),
+		})...)
+		dep, err := e.desiredDeployment(cr)
+		if err != nil {
+			return fmt.Errorf("computing deployment: %w", err)
+		}
+		objs = append(objs, &dep)
+	}
+
+	inventory, err := deployment.Inventory(e.kube.Scheme(), objs...)
+	if err != nil {
+		return fmt.Errorf("computing inventory: %w", err)
+	}
+	for _, item := range cr.Status.Inventory {
+		if !slices.Contains(inventory, item) {
+			inventory = append(inventory, item)
+		}
+	}
+	cr.Status.Inventory = inventory
+	return nil
+}
+
+// observeDeletion reports the resources of a deleted RestDefinition as existing
+// until both its CRDs and the Deployment of its dynamic controller are gone, so
+// that Delete runs until the teardown is complete.
+func (e *external) observeDeletion(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (reconciler.ExternalObservation, error) {
+	crdOk := false
+	for _, gvk := range render.RecordedGVKs(cr) {
+		crd, err := deployment.GetCRD(ctx, e.kube, deployment.ToGroupVersionResource(gvk))
+		if err != nil {
+			return reconciler.ExternalObservation{}, err
+		}
+		crdOk = crdOk || crd != nil
+	}
+
+	obj, err := e.desiredDeployment(cr)
+	if err != nil {
+		return reconciler.ExternalObservation{}, err
+	}
+	deployOk, _, err := deployment.LookupDeployment(ctx, e.kube, &obj)
+	if err != nil {
+		return reconciler.ExternalObservation{}, err
+	}
+
+	return reconciler.ExternalObservation{
+		ResourceExists:   crdOk || deployOk,
+		ResourceUpToDate: true,
+	}, nil
+}
+
+// removedResources returns the resources recorded in the status of cr whose kind
+// has been removed from its spec.
+func removedResources(cr *definitionv1alpha1.RestDefinition) []definitionv1alpha1.ResourceStatus {
+	desired := render.ResourceGVKs(cr)
+	res := []definitionv1alpha1.ResourceStatus{}
+	for _, el := range cr.Status.Resources {
+		if !slices.Contains(desired, schema.FromAPIVersionAndKind(el.APIVersion, el.Kind)) {
+			res = append(res, el)
+		}
+	}
+	return res
+}
+
+// retireFirst retires the kinds removed from the spec of cr before the dynamic
+// controller stops serving them, so that with the Cascade policy it deletes the
+// external resources of their custom resources. With the Orphan policy the kinds
+// are retired last (see retireLast), once the dynamic controller no longer serves them.
+// Returns false while custom resources of the removed kinds are left.
+func (e *external) retireFirst(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (bool, error) {
+	if cr.Spec.DeletionPolicy == definitionv1alpha1.DeletionOrphan {
+		return true, nil
+	}
+	return e.retireResources(ctx, cr)
+}
+
+// retireLast retires the kinds removed from the spec of cr with the Orphan policy.
+func (e *external) retireLast(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (bool, error) {
+	if cr.Spec.DeletionPolicy != definitionv1alpha1.DeletionOrphan {
+		return true, nil
+	}
+	return e.retireResources(ctx, cr)
+}
+
+// retireResources handles the custom resources of the kinds removed from the spec
+// of cr according to its deletion policy, then uninstalls their CRDs and removes
+// them from status. Returns false while custom resources are left.
+func (e *external) retireResources(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (bool, error) {
+	removed := removedResources(cr)
+	if len(removed) == 0 {
+		return true, nil
+	}
+
+	items := []unstructured.Unstructured{}
+	kinds := []string{}
+	for _, el := range removed {
+		gvk := schema.FromAPIVersionAndKind(el.APIVersion, el.Kind)
+		res, err := deployment.ListInstances(ctx, e.kube, gvk)
+		if err != nil {
+			return false, fmt.Errorf("listing %s resources: %w", gvk.Kind, err)
+		}
+		if len(res) > 0 {
+			items = append(items, res...)
+			kinds = append(kinds, gvk.Kind)
+		}
+	}
+
+	if len(items) > 0 {
+		kind := strings.Join(kinds, ", ")
+		msg := ""
+		switch cr.Spec.DeletionPolicy {
+		case definitionv1alpha1.DeletionCascade:
+			if err := deployment.DeleteInstances(ctx, e.kube, items); err != nil {
+				return false, fmt.Errorf("deleting %s resources: %w", kind, err)
+			}
+			msg = fmt.Sprintf("removed from the spec, waiting for the dynamic controller to delete %d %s resource(s)", len(items), kind)
+		case definitionv1alpha1.DeletionOrphan:
+			if err := deployment.OrphanInstances(ctx, e.kube, items); err != nil {
+				return false, fmt.Errorf("releasing %s resources: %w", kind, err)
+			}
+		default:
+			msg = fmt.Sprintf("removed from the spec, %d %s resource(s) left, delete them or set spec.deletionPolicy to Cascade or Orphan", len(items), kind)
+			e.rec.Event(cr, corev1.EventTypeWarning, "ResourceRemovalBlocked", msg)
+		}
+		if len(msg) > 0 {
+			for i := range cr.Status.Resources {
+				el := &cr.Status.Resources[i]
+				if slices.ContainsFunc(removed, func(r definitionv1alpha1.ResourceStatus) bool {
+					return r.Kind == el.Kind && r.APIVersion == el.APIVersion
+				}) {
+					el.Message = msg
+				}
+			}
+			e.log.Debug("Draining removed resources", "kinds", kind, "remaining", len(items))
+			return false, nil
+		}
+	}
+
+	for _, el := range removed {
+		gvr := deployment.ToGroupVersionResource(schema.FromAPIVersionAndKind(el.APIVersion, el.Kind))
+		if err := deployment.UninstallCRD(ctx, e.kube, gvr.GroupResource()); err != nil {
+			return false, fmt.Errorf("uninstalling CRD: %w", err)
+		}
+		cr.Status.Resources = slices.DeleteFunc(cr.Status.Resources, func(r definitionv1alpha1.ResourceStatus) bool {
+			return r.Kind == el.Kind && r.APIVersion == el.APIVersion
+		})
+		if cr.Status.Generation != nil {
+			cr.Status.Generation.CRDs = slices.DeleteFunc(cr.Status.Generation.CRDs, func(name string) bool {
+				return name == gvr.GroupResource().String()
+			})
+		}
+		e.log.Debug("Removed resource uninstalled", "kind", el.Kind, "crd", gvr.GroupResource().String())
+		e.rec.Eventf(cr, corev1.EventTypeNormal, "ResourceRemoved",
+			"Kind '%s' removed from the spec, CRD '%s' uninstalled", el.Kind, gvr.GroupResource().String())
+	}
+	return true, nil
+}
+
+// drainInstances handles the custom resources of the generated kinds according to
+// the deletion policy of cr and reports the progress in status.
+// Returns true when none is left and the dynamic controller can be removed.
+func (e *external) drainInstances(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (bool, error) {
+	items := []unstructured.Unstructured{}
+	kinds := []string{}
+	for _, gvk := range render.RecordedGVKs(cr) {
+		res, err := deployment.ListInstances(ctx, e.kube, gvk)
+		if err != nil {
+			return false, fmt.Errorf("listing %s resources: %w", gvk.Kind, err)
+		}
+		if len(res) > 0 {
+			items = append(items, res...)
+			kinds = append(kinds, gvk.Kind)
+		}
+	}
+	if len(items) == 0 {
+		return true, nil
+	}
+	kind := strings.Join(kinds, ", ")
+
+	status := &definitionv1alpha1.DeletionStatus{RemainingInstances: len(items)}
+	switch cr.Spec.DeletionPolicy {
+	case definitionv1alpha1.DeletionCascade:
+		if err := deployment.DeleteInstances(ctx, e.kube, items); err != nil {
+			return false, fmt.Errorf("deleting %s resources: %w", kind, err)
+		}
+		status.Phase = definitionv1alpha1.DeletionPhaseDraining
+		status.Message = fmt.Sprintf("waiting for the dynamic controller to delete %d %s resource(s)", len(items), kind)
+	case definitionv1alpha1.DeletionOrphan:
+		// The dynamic controller is stopped first, otherwise it would delete the
+		// external resources of the released custom resources. A shared dynamic
+		// controller just stops serving the resources of cr.
+		if err := e.leavePools(ctx, cr); err != nil {
+			return false, err
+		}
+		err := deployment.UninstallDeployment(ctx, deployment.UninstallOptions{
+			KubeClient: e.kube,
+			NamespacedName: deployment.ControllerNamespacedName(types.NamespacedName{
+				Namespace: cr.Namespace,
+				Name:      cr.Name,
+			}),
+			Log: e.log.Debug,
+		})
+		if err != nil {
+			return false, fmt.Errorf("uninstalling deployment: %w", err)
+		}
+		if err := deployment.OrphanInstances(ctx, e.kube, items); err != nil {
+			return false, fmt.Errorf("releasing %s resources: %w", kind, err)
+		}
+		status.Phase = definitionv1alpha1.DeletionPhaseOrphaning
+		status.Message = fmt.Sprintf("releasing %d %s resource(s), their external resources are left in place", len(items), kind)
+	default:
+		status.Phase = definitionv1alpha1.DeletionPhaseBlocked
+		status.Message = fmt.Sprintf("%d %s resource(s) left, delete them or set spec.deletionPolicy to Cascade or Orphan", len(items), kind)
+		e.rec.Event(cr, corev1.EventTypeWarning, "DeletionBlocked", status.Message)
+	}
+
+	e.log.Debug("Draining custom resources", "phase", status.Phase, "remaining", status.RemainingInstances)
+	cr.Status.Deletion = status
+	return false, nil
 }
@@ -0,0 +1,238 @@
+package definition
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/render"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func petDefinition(oasPath string) *definitionv1alpha1.RestDefinition {
+	return &definitionv1alpha1.RestDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "def-pet", Namespace: "default"},
+		Spec: definitionv1alpha1.RestDefinitionSpec{
+			OASPath:       oasPath,
+			ResourceGroup: "petstore.swagger.io",
+			Resource: definitionv1alpha1.Resource{
+				Kind: "Pet",
+				VerbsDescription: []definitionv1alpha1.VerbsDescription{
+					{Action: "create", Method: "POST", Path: "/pet"},
+					{Action: "get", Method: "GET", Path: "/pet/{petId}"},
+				},
+			},
+		},
+	}
+}
+
+func connect(t *testing.T, c *connector, cr *definitionv1alpha1.RestDefinition) *external {
+	t.Helper()
+	ext, err := c.Connect(context.TODO(), cr)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	return ext.(*external)
+}
+
+// TestArtifactsFollowGeneration checks that the artifacts and the operation map
+// are only published from the revision of the OAS document of the last generation.
+func TestArtifactsFollowGeneration(t *testing.T) {
+	ctx := context.TODO()
+
+	contents, err := os.ReadFile("../restdefinition/generator/tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	served := contents
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(served)
+	}))
+	defer srv.Close()
+
+	kube := fake.NewClientBuilder().Build()
+	c := &connector{
+		kube:     kube,
+		reader:   kube,
+		log:      logging.NewNopLogger(),
+		recorder: record.NewFakeRecorder(100),
+		cache:    oas.NewCache(),
+	}
+	cr := petDefinition(srv.URL + "/petstore.yaml")
+
+	e := connect(t, c, cr)
+	schemas, _, err := render.GenerateSchemas(e.doc, cr)
+	if err != nil {
+		t.Fatalf("failed to generate schemas: %v", err)
+	}
+	cr.Status.Generation = &definitionv1alpha1.GenerationStatus{
+		Digest:   render.Digest(schemas),
+		Revision: e.revision.Digest,
+	}
+	if err := e.publishArtifacts(ctx, cr, schemas); err != nil {
+		t.Fatalf("failed to publish artifacts: %v", err)
+	}
+	if ok, err := e.artifactsUpToDate(ctx, cr); err != nil || !ok {
+		t.Fatalf("expected published artifacts to be up to date, got %v, %v", ok, err)
+	}
+
+	ops := corev1.ConfigMap{}
+	opsKey := client.ObjectKey{Namespace: cr.Status.Artifacts.OperationsRef.Namespace, Name: cr.Status.Artifacts.OperationsRef.Name}
+	if err := kube.Get(ctx, opsKey, &ops); err != nil {
+		t.Fatalf("expected the operation map to be published: %v", err)
+	}
+	published := ops.Data[deployment.OperationsKey]
+
+	// The document changes upstream and the CRD is not regenerated (no refreshInterval):
+	// nothing is published from the new revision, even to restore a deleted ConfigMap.
+	served = bytes.Replace(contents, []byte("url: /api/v3"), []byte("url: /api/v4"), 1)
+	if err := kube.Delete(ctx, &ops); err != nil {
+		t.Fatalf("failed to delete the operation map: %v", err)
+	}
+	e = connect(t, c, cr)
+	if e.atGeneration(cr) {
+		t.Fatalf("expected a new revision of the document")
+	}
+	if ok, err := e.artifactsUpToDate(ctx, cr); err != nil || !ok {
+		t.Errorf("expected no publication from a revision other than the generated one, got %v, %v", ok, err)
+	}
+
+	// Back to the generated revision: the operation map is restored as generated.
+	served = contents
+	e = connect(t, c, cr)
+	if ok, err := e.artifactsUpToDate(ctx, cr); err != nil || ok {
+		t.Fatalf("expected the deleted operation map to be restored, got %v, %v", ok, err)
+	}
+	e.publish = true
+	if err := e.update(ctx, cr); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if ok, err := e.artifactsUpToDate(ctx, cr); err != nil || !ok {
+		t.Errorf("expected artifacts to be up to date after the update, got %v, %v", ok, err)
+	}
+	if cr.Status.Artifacts.Digest != cr.Status.Generation.Digest {
+		t.Errorf("expected artifacts digest %s, got %s", cr.Status.Generation.Digest, cr.Status.Artifacts.Digest)
+	}
+	ops = corev1.ConfigMap{}
+	if err := kube.Get(ctx, opsKey, &ops); err != nil {
+		t.Fatalf("expected the operation map to be restored: %v", err)
+	}
+	if ops.Data[deployment.OperationsKey] != published {
+		t.Errorf("expected the operation map of the generated revision, got %s", ops.Data[deployment.OperationsKey])
+	}
+	if _, ok := deployment.OwnerOf(&ops); !ok {
+		t.Errorf("expected owner labels on %s", types.NamespacedName{Namespace: ops.Namespace, Name: ops.Name})
+	}
+}
+
+func crdWithSpec(props map[string]apiextensionsv1.JSONSchemaProps) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "pets.petstore.swagger.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name: resourceVersion,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"spec": {Type: "object", Properties: props},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// TestBreakingChangesApproval checks that breaking schema changes are only applied
+// when approved with the digest of the schemas they lead to.
+func TestBreakingChangesApproval(t *testing.T) {
+	ctx := context.TODO()
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	if err := apiextensionsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	installed := crdWithSpec(map[string]apiextensionsv1.JSONSchemaProps{
+		"name":     {Type: "string"},
+		"nickname": {Type: "string"},
+		"age":      {Type: "integer"},
+	})
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(installed).Build()
+	e := &external{kube: kube, reader: kube, log: logging.NewNopLogger(), rec: record.NewFakeRecorder(100)}
+	cr := petDefinition("")
+
+	apply := func(crd *apiextensionsv1.CustomResourceDefinition, digest string) error {
+		return e.applyResourceCRD(ctx, cr, crd, digest)
+	}
+	properties := func() map[string]apiextensionsv1.JSONSchemaProps {
+		res := apiextensionsv1.CustomResourceDefinition{}
+		if err := kube.Get(ctx, client.ObjectKeyFromObject(installed), &res); err != nil {
+			t.Fatalf("failed to get CRD: %v", err)
+		}
+		return res.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties
+	}
+
+	// The nickname field is removed.
+	first := crdWithSpec(map[string]apiextensionsv1.JSONSchemaProps{
+		"name": {Type: "string"},
+		"age":  {Type: "integer"},
+	})
+	if err := apply(first, "first"); err == nil {
+		t.Fatalf("expected breaking changes to be refused")
+	}
+	if cr.Status.SchemaChanges == nil || !cr.Status.SchemaChanges.Blocked || cr.Status.SchemaChanges.Digest != "first" {
+		t.Fatalf("expected blocked changes leading to digest 'first', got %+v", cr.Status.SchemaChanges)
+	}
+
+	cr.SetAnnotations(map[string]string{definitionv1alpha1.AnnotationAllowBreakingChanges: "true"})
+	if err := apply(first, "first"); err == nil {
+		t.Errorf("expected breaking changes not to be approved by 'true'")
+	}
+
+	cr.SetAnnotations(map[string]string{definitionv1alpha1.AnnotationAllowBreakingChanges: "first"})
+	if err := apply(first, "first"); err != nil {
+		t.Fatalf("expected approved breaking changes to be applied, got %v", err)
+	}
+	if cr.Status.SchemaChanges.Blocked {
+		t.Errorf("expected approved changes not to be blocked")
+	}
+	if _, ok := properties()["nickname"]; ok {
+		t.Errorf("expected the approved schema to be applied")
+	}
+
+	// A later breaking change, the type of age, is not covered by the approval.
+	second := crdWithSpec(map[string]apiextensionsv1.JSONSchemaProps{
+		"name": {Type: "string"},
+		"age":  {Type: "string"},
+	})
+	if err := apply(second, "second"); err == nil {
+		t.Fatalf("expected later breaking changes to be refused")
+	}
+	if !cr.Status.SchemaChanges.Blocked || cr.Status.SchemaChanges.Digest != "second" {
+		t.Errorf("expected blocked changes leading to digest 'second', got %+v", cr.Status.SchemaChanges)
+	}
+	if properties()["age"].Type != "integer" {
+		t.Errorf("expected the refused schema not to be applied")
+	}
+}
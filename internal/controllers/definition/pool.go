@@ -0,0 +1,212 @@
+package definition
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/rbactools"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// poolMembers returns the RestDefinitions of namespace joining the controller pool
+// name, oldest first. RestDefinitions being deleted have left the pool.
+func poolMembers(ctx context.Context, kube client.Reader, namespace, name string) ([]definitionv1alpha1.RestDefinition, error) {
+	list := definitionv1alpha1.RestDefinitionList{}
+	if err := kube.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("listing RestDefinitions: %w", err)
+	}
+
+	res := []definitionv1alpha1.RestDefinition{}
+	for _, el := range list.Items {
+		if el.Spec.ControllerPool != name || el.GetDeletionTimestamp() != nil {
+			continue
+		}
+		res = append(res, el)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		ti, tj := res[i].GetCreationTimestamp(), res[j].GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res, nil
+}
+
+// desiredPool computes the shared dynamic controller of the controller pool name
+// from the resources and the roles of its members.
+func (e *external) desiredPool(ctx context.Context, namespace, name string) (deployment.Pool, error) {
+	items, err := poolMembers(ctx, e.kube, namespace, name)
+	if err != nil {
+		return deployment.Pool{}, err
+	}
+
+	members := make([]deployment.PoolMember, 0, len(items))
+	for i := range items {
+		el := &items[i]
+		role, err := e.desiredRole(el)
+		if err != nil {
+			return deployment.Pool{}, fmt.Errorf("computing role of %s: %w", el.Name, err)
+		}
+		secrets, connectionSecrets, err := e.desiredSecrets(ctx, el)
+		if err != nil {
+			return deployment.Pool{}, fmt.Errorf("computing role of %s: %w", el.Name, err)
+		}
+		members = append(members, deployment.PoolMember{
+			NamespacedName:    types.NamespacedName{Namespace: el.Namespace, Name: el.Name},
+			GVRs:              deployment.ResourceGVRs(&el.Spec, resourceVersion),
+			Rules:             role.Rules,
+			WatchNamespaces:   deployment.WatchNamespaces(&el.Spec, el.Namespace),
+			Secrets:           secrets,
+			ConnectionSecrets: connectionSecrets,
+		})
+	}
+
+	pool := deployment.NewPool(types.NamespacedName{Namespace: namespace, Name: name}, members)
+	pool.ExtendedArgs = e.extendedArgs
+	return pool, nil
+}
+
+// applyPool joins cr to the controller pool of its spec, leaving its previous
+// pool or removing its dedicated controller, and installs the shared controller.
+// Returns the reason why the pool refuses cr, if any.
+func (e *external) applyPool(ctx context.Context, cr *definitionv1alpha1.RestDefinition) (string, error) {
+	nn := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+
+	pool, err := e.desiredPool(ctx, cr.Namespace, cr.Spec.ControllerPool)
+	if err != nil {
+		return "", err
+	}
+	if msg, ok := pool.Conflicts[nn]; ok {
+		return msg, nil
+	}
+
+	switch previous := cr.Status.ControllerPool; {
+	case previous == nil:
+		if err := e.uninstallDedicated(ctx, cr); err != nil {
+			return "", err
+		}
+	case previous.Name != cr.Spec.ControllerPool:
+		if err := e.leavePool(ctx, cr, previous.Name); err != nil {
+			return "", err
+		}
+	}
+
+	changed, err := deployment.ApplyPool(ctx, e.kube, pool, e.log.Debug)
+	if err != nil {
+		return "", fmt.Errorf("applying controller pool: %w", err)
+	}
+	if changed {
+		e.log.Debug("Updated shared Dynamic Controller", "pool", pool.NamespacedName.String(), "members", len(pool.Members))
+		e.rec.Eventf(cr, corev1.EventTypeNormal, "ControllerPoolUpdated",
+			"Shared Dynamic Controller '%s' serves %d resource(s) of %d RestDefinition(s)",
+			pool.ControllerNamespacedName().Name, len(pool.GVRs), len(pool.Members))
+	}
+	setPoolStatus(cr, pool)
+	return "", nil
+}
+
+// leavePool updates the shared controller of the controller pool name without the
+// resources of cr, or removes it when cr was its last member.
+func (e *external) leavePool(ctx context.Context, cr *definitionv1alpha1.RestDefinition, name string) error {
+	pool, err := e.desiredPool(ctx, cr.Namespace, name)
+	if err != nil {
+		return err
+	}
+
+	if len(pool.Members) == 0 {
+		pool.WatchNamespaces = deployment.WatchNamespaces(&cr.Spec, cr.Namespace)
+		if err := deployment.UninstallPool(ctx, e.kube, pool, e.log.Debug); err != nil {
+			return fmt.Errorf("uninstalling controller pool: %w", err)
+		}
+	} else if _, err := deployment.ApplyPool(ctx, e.kube, pool, e.log.Debug); err != nil {
+		return fmt.Errorf("applying controller pool: %w", err)
+	}
+
+	e.rec.Eventf(cr, corev1.EventTypeNormal, "ControllerPoolLeft", "Left controller pool '%s'", name)
+	return nil
+}
+
+// leavePools removes cr, being deleted, from the controller pool of its spec and
+// from the one recorded in its status.
+func (e *external) leavePools(ctx context.Context, cr *definitionv1alpha1.RestDefinition) error {
+	names := []string{}
+	if len(cr.Spec.ControllerPool) > 0 {
+		names = append(names, cr.Spec.ControllerPool)
+	}
+	if p := cr.Status.ControllerPool; p != nil && p.Name != cr.Spec.ControllerPool {
+		names = append(names, p.Name)
+	}
+	for _, name := range names {
+		if err := e.leavePool(ctx, cr, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uninstallDedicated removes the dedicated dynamic controller of cr and its RBAC.
+func (e *external) uninstallDedicated(ctx context.Context, cr *definitionv1alpha1.RestDefinition) error {
+	nn := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	controller := deployment.ControllerNamespacedName(nn)
+
+	err := deployment.UninstallDeployment(ctx, deployment.UninstallOptions{
+		KubeClient:     e.kube,
+		NamespacedName: controller,
+		Log:            e.log.Debug,
+	})
+	if err != nil {
+		return fmt.Errorf("uninstalling deployment: %w", err)
+	}
+	err = deployment.UninstallRBAC(ctx, deployment.RBACOptions{
+		KubeClient:      e.kube,
+		NamespacedName:  nn,
+		WatchNamespaces: deployment.WatchNamespaces(&cr.Spec, cr.Namespace),
+		Log:             e.log.Debug,
+	})
+	if err != nil {
+		return fmt.Errorf("uninstalling RBAC: %w", err)
+	}
+	return rbactools.UninstallServiceAccount(ctx, rbactools.UninstallOptions{
+		KubeClient:     e.kube,
+		NamespacedName: controller,
+		Log:            e.log.Debug,
+	})
+}
+
+func setPoolStatus(cr *definitionv1alpha1.RestDefinition, pool deployment.Pool) {
+	cr.Status.ControllerPool = &definitionv1alpha1.ControllerPoolStatus{
+		Name:       pool.NamespacedName.Name,
+		Controller: pool.ControllerNamespacedName().Name,
+		Members:    len(pool.Members),
+	}
+}
+
+// poolUpToDate returns true if cr has joined the controller pool of its spec, or
+// has no pool.
+func poolUpToDate(cr *definitionv1alpha1.RestDefinition) bool {
+	if len(cr.Spec.ControllerPool) == 0 {
+		return cr.Status.ControllerPool == nil
+	}
+	return cr.Status.ControllerPool != nil && cr.Status.ControllerPool.Name == cr.Spec.ControllerPool
+}
+
+// poolRequests maps an object of the shared controller of a pool to the members
+// of the pool.
+func poolRequests(ctx context.Context, kube client.Reader, pool types.NamespacedName) []reconcile.Request {
+	items, err := poolMembers(ctx, kube, pool.Namespace, pool.Name)
+	if err != nil {
+		return nil
+	}
+	res := make([]reconcile.Request, 0, len(items))
+	for _, el := range items {
+		res = append(res, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: el.Namespace, Name: el.Name}})
+	}
+	return res
+}
@@ -0,0 +1,31 @@
+package generator
+
+import (
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ResolveActions returns the actions of the resource that match an operation of the OAS document.
+func ResolveActions(doc *libopenapi.DocumentModel[v3.Document], resource definitionv1alpha1.Resource) []definitionv1alpha1.ResolvedAction {
+	res := make([]definitionv1alpha1.ResolvedAction, 0, len(resource.VerbsDescription))
+	for i, verb := range resource.VerbsDescription {
+		op, err := validation.LookupOperation(doc, verb, field.NewPath("verbsDescription").Index(i))
+		if err != nil {
+			continue
+		}
+
+		res = append(res, definitionv1alpha1.ResolvedAction{
+			Action:      verb.Action,
+			Method:      strings.ToUpper(verb.Method),
+			Path:        verb.Path,
+			OperationID: op.OperationId,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,382 @@
+package generator_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/generator"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+)
+
+var petResource = definitionv1alpha1.Resource{
+	Kind: "Pet",
+	VerbsDescription: []definitionv1alpha1.VerbsDescription{
+		{Action: "create", Path: "/pet", Method: "POST"},
+		{Action: "get", Path: "/pet/{petId}", Method: "GET"},
+		{Action: "update", Path: "/pet", Method: "PUT"},
+		{Action: "delete", Path: "/pet/{petId}", Method: "DELETE"},
+	},
+}
+
+func TestWarningClass(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "plain error", err: errors.New("boom"), want: generator.WarningGeneric},
+		{name: "warning", err: &generator.Warning{Class: generator.WarningParameterCollision, Err: errors.New("boom")}, want: generator.WarningParameterCollision},
+		{name: "wrapped warning", err: fmt.Errorf("wrap: %w", &generator.Warning{Class: generator.WarningUnsupportedSecurityScheme, Err: errors.New("boom")}), want: generator.WarningUnsupportedSecurityScheme},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := generator.WarningClass(tc.err); got != tc.want {
+				t.Errorf("WarningClass() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateByteSchemasWarnings(t *testing.T) {
+	contents, err := content.ReadFile("tests/oas/petstore_auth.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	doc, err := oas.Parse(contents)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	_, fatalErr, warnings := generator.GenerateByteSchemas(doc, petResource, []string{"id"})
+	if fatalErr != nil {
+		t.Fatalf("fatal error: %v", fatalErr)
+	}
+
+	found := false
+	for _, w := range warnings {
+		if generator.WarningClass(w) == generator.WarningUnsupportedSecurityScheme {
+			found = true
+			if !strings.HasPrefix(w.Error(), "security scheme ") {
+				t.Errorf("expected warning to name the skipped scheme, got %q", w.Error())
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected a %s warning, got %v", generator.WarningUnsupportedSecurityScheme, warnings)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	contents, err := content.ReadFile("tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	doc, err := oas.Parse(contents)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	gen1, err, _ := generator.GenerateByteSchemas(doc, petResource, []string{"id"})
+	if err != nil {
+		t.Fatalf("fatal error: %v", err)
+	}
+	gen2, err, _ := generator.GenerateByteSchemas(doc, petResource, []string{"id"})
+	if err != nil {
+		t.Fatalf("fatal error: %v", err)
+	}
+
+	if !strings.HasPrefix(gen1.Digest(), "sha256:") {
+		t.Errorf("unexpected digest format: %s", gen1.Digest())
+	}
+	if gen1.Digest() != gen2.Digest() {
+		t.Errorf("expected stable digest, got %s and %s", gen1.Digest(), gen2.Digest())
+	}
+
+	gen3, err, _ := generator.GenerateByteSchemas(doc, petResource, []string{"id", "name"})
+	if err != nil {
+		t.Fatalf("fatal error: %v", err)
+	}
+	if gen1.Digest() == gen3.Digest() {
+		t.Errorf("expected digest to change when the identifiers change")
+	}
+}
+
+func TestResolveActions(t *testing.T) {
+	contents, err := content.ReadFile("tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	doc, err := oas.Parse(contents)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	actions := generator.ResolveActions(doc, petResource)
+	if len(actions) != len(petResource.VerbsDescription) {
+		t.Fatalf("expected %d actions, got %d", len(petResource.VerbsDescription), len(actions))
+	}
+	if actions[0].Action != "create" || actions[0].Method != "POST" || actions[0].OperationID != "addPet" {
+		t.Errorf("unexpected resolved action: %+v", actions[0])
+	}
+}
+
+func TestPendingOperation(t *testing.T) {
+	contents, err := content.ReadFile("tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	doc, err := oas.Parse(contents)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	gen, err, _ := generator.GenerateByteSchemas(doc, petResource, []string{"id"})
+	if err != nil {
+		t.Fatalf("fatal error: %v", err)
+	}
+	if strings.Contains(string(gen.StatusSchema()), generator.PendingOperationField) {
+		t.Errorf("expected no pending operation without async actions, got %s", gen.StatusSchema())
+	}
+
+	res := petResource
+	res.VerbsDescription = slices.Clone(petResource.VerbsDescription)
+	res.VerbsDescription[0].Async = &definitionv1alpha1.Async{StatusHeader: "Location", StatePointer: "/status", SucceededValues: []string{"done"}}
+	gen, err, _ = generator.GenerateByteSchemas(doc, res, []string{"id"})
+	if err != nil {
+		t.Fatalf("fatal error: %v", err)
+	}
+	status := map[string]any{}
+	if err := json.Unmarshal(gen.StatusSchema(), &status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	props, _ := status["properties"].(map[string]any)
+	pending, _ := props[generator.PendingOperationField].(map[string]any)
+	fields, _ := pending["properties"].(map[string]any)
+	for _, name := range []string{"action", "statusURL", "state", "startedAt"} {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("expected %s in the pending operation, got %s", name, gen.StatusSchema())
+		}
+	}
+	if _, ok := props["id"]; !ok {
+		t.Errorf("expected the identifiers to be kept, got %s", gen.StatusSchema())
+	}
+}
+
+func TestIdentifierTypes(t *testing.T) {
+	contents, err := content.ReadFile("tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	doc, err := oas.Parse(contents)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	gen, err, _ := generator.GenerateByteSchemas(doc, petResource, []string{"id", "category=/category/name"})
+	if err != nil {
+		t.Fatalf("fatal error: %v", err)
+	}
+	status := map[string]any{}
+	if err := json.Unmarshal(gen.StatusSchema(), &status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	props, _ := status["properties"].(map[string]any)
+	for name, typ := range map[string]string{"id": "integer", "category": "string"} {
+		prop, _ := props[name].(map[string]any)
+		if prop["type"] != typ {
+			t.Errorf("expected %s to be a %s, got %s", name, typ, gen.StatusSchema())
+		}
+	}
+
+	_, err, _ = generator.GenerateByteSchemas(doc, petResource, []string{"$.owner.id"})
+	if err == nil || !strings.Contains(err.Error(), "identifier not found in the response of GET /pet/{petId}") {
+		t.Errorf("expected an unknown identifier, got %v", err)
+	}
+}
+
+const thingsOAS = `openapi: 3.1.0
+info:
+  title: things
+  version: 1.0.0
+paths:
+  /things:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Thing'
+      responses:
+        "201":
+          description: created
+  /things/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Thing'
+components:
+  schemas:
+    Thing:
+      type: object
+      properties:
+        id:
+          type: [integer, "null"]
+          format: int64
+        code:
+          type: [string, integer]
+        name:
+          type: string
+`
+
+func TestIdentifierScalarTypes(t *testing.T) {
+	doc, err := oas.Parse([]byte(thingsOAS))
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+	res := definitionv1alpha1.Resource{
+		Kind: "Thing",
+		VerbsDescription: []definitionv1alpha1.VerbsDescription{
+			{Action: "create", Method: "POST", Path: "/things"},
+			{Action: "get", Method: "GET", Path: "/things/{id}"},
+		},
+	}
+
+	gen, err, _ := generator.GenerateByteSchemas(doc, res, []string{"id", "code"})
+	if err != nil {
+		t.Fatalf("fatal error: %v", err)
+	}
+	status := map[string]any{}
+	if err := json.Unmarshal(gen.StatusSchema(), &status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	props, _ := status["properties"].(map[string]any)
+	for name, typ := range map[string]string{"id": "integer", "code": "string"} {
+		prop, _ := props[name].(map[string]any)
+		if prop["type"] != typ {
+			t.Errorf("expected %s to be a %s, got %s", name, typ, gen.StatusSchema())
+		}
+	}
+	if prop, _ := props["id"].(map[string]any); prop["format"] != "int64" {
+		t.Errorf("expected the format of id to be kept, got %s", gen.StatusSchema())
+	}
+}
+
+const tokensOAS = `openapi: 3.0.3
+info:
+  title: tokens
+  version: 1.0.0
+paths:
+  /tokens:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Token'
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Token'
+  /tokens/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Token'
+components:
+  schemas:
+    Token:
+      type: object
+      properties:
+        id:
+          type: string
+        name:
+          type: string
+        token:
+          type: string
+          format: password
+`
+
+func TestConnectionDetails(t *testing.T) {
+	doc, err := oas.Parse([]byte(tokensOAS))
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	res := definitionv1alpha1.Resource{
+		Kind: "Token",
+		VerbsDescription: []definitionv1alpha1.VerbsDescription{
+			{Action: "create", Method: "POST", Path: "/tokens"},
+			{Action: "get", Method: "GET", Path: "/tokens/{id}"},
+		},
+	}
+	gen, err, _ := generator.GenerateByteSchemas(doc, res, []string{"id"})
+	if err != nil {
+		t.Fatalf("fatal error: %v", err)
+	}
+	if strings.Contains(string(gen.SpecSchema()), generator.ConnectionSecretField) {
+		t.Errorf("expected no connection Secret reference without connection details, got %s", gen.SpecSchema())
+	}
+
+	res.ConnectionDetails = []definitionv1alpha1.ConnectionDetail{{Key: "token", Pointer: "/token"}}
+	gen, err, warnings := generator.GenerateByteSchemas(doc, res, []string{"id", "token"})
+	if err != nil {
+		t.Fatalf("fatal error: %v", err)
+	}
+	spec := map[string]any{}
+	if err := json.Unmarshal(gen.SpecSchema(), &spec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	props, _ := spec["properties"].(map[string]any)
+	ref, _ := props[generator.ConnectionSecretField].(map[string]any)
+	if fields, _ := ref["properties"].(map[string]any); fields["name"] == nil || fields["namespace"] != nil {
+		t.Errorf("expected the connection Secret reference, by name only, got %s", gen.SpecSchema())
+	}
+
+	status := map[string]any{}
+	if err := json.Unmarshal(gen.StatusSchema(), &status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	props, _ = status["properties"].(map[string]any)
+	if _, ok := props["token"]; ok {
+		t.Errorf("expected the password to be excluded from the status, got %s", gen.StatusSchema())
+	}
+	if _, ok := props["id"]; !ok {
+		t.Errorf("expected the identifiers to be kept, got %s", gen.StatusSchema())
+	}
+	found := false
+	for _, w := range warnings {
+		found = found || generator.WarningClass(w) == generator.WarningSensitiveField
+	}
+	if !found {
+		t.Errorf("expected a %s warning, got %v", generator.WarningSensitiveField, warnings)
+	}
+}
@@ -1,18 +1,24 @@
 package generator
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
 
 	"github.com/krateoplatformops/crdgen"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/generation"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/text"
 	"github.com/pb33f/libopenapi"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // var g *OASSchemaGenerator
@@ -31,17 +37,26 @@ func GenerateByteSchemas(doc *libopenapi.DocumentModel[v3.Document], resource de
 	for secSchemaPair := doc.Model.Components.SecuritySchemes.First(); secSchemaPair != nil; secSchemaPair = secSchemaPair.Next() {
 		authSchemaName, err := generation.GenerateAuthSchemaName(secSchemaPair.Value())
 		if err != nil {
-			errors = append(errors, err)
+			errors = append(errors, newWarningf(WarningUnsupportedSecurityScheme,
+				"security scheme %s skipped: %w", secSchemaPair.Key(), err))
 			continue
 		}
 
 		secByteSchema[authSchemaName], err = generation.GenerateAuthSchemaFromSecuritySchema(secSchemaPair.Value())
 		if err != nil {
-			errors = append(errors, err)
+			errors = append(errors, newWarningf(WarningUnsupportedSecurityScheme,
+				"security scheme %s skipped: %w", secSchemaPair.Key(), err))
 			continue
 		}
 	}
 
+	// Identifiers are typed after the resource returned by the get action
+	resource.Identifiers = identifiers
+	ids, idErrs := validation.ResolveIdentifiers(doc, resource, field.NewPath("identifiers"))
+	if len(idErrs) > 0 {
+		return nil, fmt.Errorf("resolving identifiers: %w", idErrs.ToAggregate()), errors
+	}
+
 	specByteSchema := make(map[string][]byte)
 	for _, verb := range resource.VerbsDescription {
 		if strings.EqualFold(verb.Action, "create") {
@@ -143,7 +158,8 @@ func GenerateByteSchemas(doc *libopenapi.DocumentModel[v3.Document], resource de
 			for op := ops.First(); op != nil; op = op.Next() {
 				for _, param := range op.Value().Parameters {
 					if _, ok := schema.Properties.Get(param.Name); ok {
-						errors = append(errors, fmt.Errorf("parameter %s already exists in schema", param.Name))
+						errors = append(errors, newWarningf(WarningParameterCollision,
+							"parameter %s of %s %s already exists in schema", param.Name, strings.ToUpper(op.Key()), verb.Path))
 						continue
 					}
 
@@ -165,13 +181,19 @@ func GenerateByteSchemas(doc *libopenapi.DocumentModel[v3.Document], resource de
 			return nil, fmt.Errorf("schema is nil for %s", verb.Path), errors
 		}
 		// Add the identifiers to the properties map
-		for _, identifier := range identifiers {
-			_, ok := schema.Properties.Get(identifier)
+		for _, id := range ids {
+			_, ok := schema.Properties.Get(id.Field)
 			if !ok {
-				schema.Properties.Set(identifier, base.CreateSchemaProxy(&base.Schema{
-					Description: fmt.Sprintf("IDENTIFIER: %s", identifier),
-					Type:        []string{"string"},
-				}))
+				schema.Properties.Set(id.Field, identifierSchema(id, fmt.Sprintf("IDENTIFIER: %s", id.Field)))
+			}
+		}
+		// Connection details are written to a Secret referenced by the spec
+		if len(resource.ConnectionDetails) > 0 {
+			if _, ok := schema.Properties.Get(ConnectionSecretField); ok {
+				errors = append(errors, newWarningf(WarningParameterCollision,
+					"property %s of the request body hides the connection Secret reference", ConnectionSecretField))
+			} else {
+				schema.Properties.Set(ConnectionSecretField, connectionSecretSchema())
 			}
 		}
 
@@ -189,10 +211,24 @@ func GenerateByteSchemas(doc *libopenapi.DocumentModel[v3.Document], resource de
 	propMap := orderedmap.New[string, *base.SchemaProxy]()
 
 	// Add the identifiers to the properties map
-	for _, identifier := range identifiers {
-		propMap.Set(identifier, base.CreateSchemaProxy(&base.Schema{
-			Type: []string{"string"},
-		}))
+	// Passwords and write only values are never written to the status
+	for _, id := range ids {
+		if validation.IsSensitive(id.Schema) {
+			errors = append(errors, newWarningf(WarningSensitiveField,
+				"identifier %s is a password or a write only value, excluded from the status", id.Field))
+			continue
+		}
+		propMap.Set(id.Field, identifierSchema(id, ""))
+	}
+
+	// Actions answered with '202 Accepted' report the operation in progress
+	if hasAsync(resource) {
+		if _, ok := propMap.Get(PendingOperationField); ok {
+			errors = append(errors, newWarningf(WarningParameterCollision,
+				"identifier %s hides the pending operation of asynchronous actions", PendingOperationField))
+		} else {
+			propMap.Set(PendingOperationField, pendingOperationSchema())
+		}
 	}
 
 	// Create a schema proxy with the properties map
@@ -220,6 +256,73 @@ func GenerateByteSchemas(doc *libopenapi.DocumentModel[v3.Document], resource de
 	return g, nil, errors
 }
 
+// identifierSchema returns the schema of the field holding the identifier id: the
+// scalar type of its value in the response, ignoring 'null', or string when the
+// type is unknown, not a scalar or one of several.
+func identifierSchema(id validation.ResolvedIdentifier, description string) *base.SchemaProxy {
+	res := &base.Schema{Type: []string{"string"}, Description: description}
+	if id.Schema == nil {
+		return base.CreateSchemaProxy(res)
+	}
+	types := slices.DeleteFunc(slices.Clone(id.Schema.Type), func(t string) bool { return t == "null" })
+	if len(types) == 1 && slices.Contains([]string{"string", "integer", "number", "boolean"}, types[0]) {
+		res.Type = types
+		res.Format = id.Schema.Format
+	}
+	return base.CreateSchemaProxy(res)
+}
+
+// ConnectionSecretField is the spec field referencing the Secret the connection
+// details are written to.
+const ConnectionSecretField = "writeConnectionSecretToRef"
+
+func connectionSecretSchema() *base.SchemaProxy {
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("name", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"string"},
+		Description: "the name of the Secret",
+	}))
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"object"},
+		Description: "WriteConnectionSecretToRef references the Secret, in the namespace of the resource, the connection details of the resource are written to. The Secret is owned by the resource.",
+		Properties:  props,
+		Required:    []string{"name"},
+	})
+}
+
+// PendingOperationField is the status field reporting the long-running operation
+// in progress of the asynchronous actions.
+const PendingOperationField = "pendingOperation"
+
+func hasAsync(resource definitionv1alpha1.Resource) bool {
+	for _, verb := range resource.VerbsDescription {
+		if verb.Async != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func pendingOperationSchema() *base.SchemaProxy {
+	str := func(description string) *base.SchemaProxy {
+		return base.CreateSchemaProxy(&base.Schema{Type: []string{"string"}, Description: description})
+	}
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("action", str("the action waiting for the operation to complete"))
+	props.Set("statusURL", str("the URL of the status endpoint of the operation"))
+	props.Set("state", str("the last state reported by the status endpoint"))
+	props.Set("startedAt", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"string"},
+		Format:      "date-time",
+		Description: "when the operation was accepted",
+	}))
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"object"},
+		Description: "the long-running operation in progress, if any",
+		Properties:  props,
+	})
+}
+
 // func PopulateFromAllOf() is a method that populates the schema with the properties from the allOf field.
 // the recursive function to populate the schema with the properties from the allOf field.
 func populateFromAllOf(schema *base.Schema) {
@@ -256,6 +359,43 @@ func populateFromAllOf(schema *base.Schema) {
 	}
 }
 
+// Digest returns the sha256 digest of the generated spec, status and auth schemas.
+func (g *OASSchemaGenerator) Digest() string {
+	h := sha256.New()
+	h.Write(g.specByteSchema)
+	h.Write([]byte{0})
+	h.Write(g.statusByteSchema)
+
+	keys := make([]string, 0, len(g.secByteSchema))
+	for k := range g.secByteSchema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		h.Write([]byte{0})
+		h.Write([]byte(k))
+		h.Write(g.secByteSchema[k])
+	}
+
+	return fmt.Sprintf("sha256:%s", hex.EncodeToString(h.Sum(nil)))
+}
+
+// SpecSchema returns the JSON schema of the spec of the resource.
+func (g *OASSchemaGenerator) SpecSchema() []byte {
+	return g.specByteSchema
+}
+
+// StatusSchema returns the JSON schema of the status of the resource.
+func (g *OASSchemaGenerator) StatusSchema() []byte {
+	return g.statusByteSchema
+}
+
+// AuthSchemas returns the JSON schema of the spec of each authentication resource,
+// by authentication schema name.
+func (g *OASSchemaGenerator) AuthSchemas() map[string][]byte {
+	return g.secByteSchema
+}
+
 func (g *OASSchemaGenerator) OASSpecJsonSchemaGetter() crdgen.JsonSchemaGetter {
 	return &oasSpecJsonSchemaGetter{
 		g: g,
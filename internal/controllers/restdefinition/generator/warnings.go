@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Classes of the non fatal errors returned by GenerateByteSchemas.
+const (
+	WarningUnsupportedSecurityScheme = "UnsupportedSecurityScheme"
+	WarningParameterCollision        = "ParameterCollision"
+	WarningSensitiveField            = "SensitiveField"
+	WarningGeneric                   = "GenerationWarning"
+)
+
+// Warning is a non fatal error raised while generating the schemas.
+type Warning struct {
+	Class string
+	Err   error
+}
+
+func (w *Warning) Error() string {
+	return w.Err.Error()
+}
+
+func (w *Warning) Unwrap() error {
+	return w.Err
+}
+
+func newWarning(class string, err error) error {
+	return &Warning{Class: class, Err: err}
+}
+
+func newWarningf(class string, format string, a ...any) error {
+	return newWarning(class, fmt.Errorf(format, a...))
+}
+
+// WarningClass returns the class of a non fatal error returned by GenerateByteSchemas.
+func WarningClass(err error) string {
+	var w *Warning
+	if errors.As(err, &w) {
+		return w.Class
+	}
+	return WarningGeneric
+}
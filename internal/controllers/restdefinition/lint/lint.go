@@ -0,0 +1,400 @@
+// Package lint reports which operations of an OAS document can be described by
+// a RestDefinition, grouping them into candidate resources.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/generator"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generation"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// Severities of an Issue. A resource with errors cannot be described by a RestDefinition.
+const (
+	SeverityError   = "Error"
+	SeverityWarning = "Warning"
+)
+
+// Classes of the issues reported by Lint.
+const (
+	IssueMissingAction       = "MissingAction"
+	IssueMissingJSONBody     = "MissingJSONRequestBody"
+	IssueNoStableIdentifier  = "NoStableIdentifier"
+	IssueNonStructuralSchema = "NonStructuralSchema"
+	// IssueUnsupportedSecurityScheme is the class of the warning raised by the generator.
+	IssueUnsupportedSecurityScheme = generator.WarningUnsupportedSecurityScheme
+)
+
+// maxSchemaDepth bounds the walk of recursive schemas.
+const maxSchemaDepth = 32
+
+// Issue is a problem found in the OAS document.
+type Issue struct {
+	Severity string `json:"severity"`
+	Class    string `json:"class"`
+	Message  string `json:"message"`
+}
+
+// Operation is a path and method of the OAS document.
+type Operation struct {
+	Path        string `json:"path"`
+	Method      string `json:"method"`
+	OperationID string `json:"operationId,omitempty"`
+	// Resource is the kind of the candidate resource the operation belongs to.
+	Resource string `json:"resource,omitempty"`
+	// Action is the RestDefinition action inferred for the operation, if any.
+	Action string `json:"action,omitempty"`
+}
+
+// Resource is a candidate resource: a collection path and its item path.
+type Resource struct {
+	Kind           string `json:"kind"`
+	CollectionPath string `json:"collectionPath"`
+	ItemPath       string `json:"itemPath,omitempty"`
+	// VerbsDescription of the operations mapped to an action.
+	VerbsDescription []definitionv1alpha1.VerbsDescription `json:"verbsDescription"`
+	// Identifiers are the response properties that identify an instance.
+	Identifiers []string `json:"identifiers,omitempty"`
+	Compatible  bool     `json:"compatible"`
+	Issues      []Issue  `json:"issues,omitempty"`
+}
+
+// SecurityScheme of the OAS document.
+type SecurityScheme struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Scheme    string `json:"scheme,omitempty"`
+	Supported bool   `json:"supported"`
+}
+
+// Report is the outcome of Lint.
+type Report struct {
+	Operations      []Operation      `json:"operations"`
+	Resources       []Resource       `json:"resources"`
+	SecuritySchemes []SecurityScheme `json:"securitySchemes"`
+	// Issues that do not concern a single resource.
+	Issues []Issue `json:"issues,omitempty"`
+}
+
+// Lint lists the operations of doc, groups them into candidate resources and
+// checks that each resource can be described by a RestDefinition.
+func Lint(doc *libopenapi.DocumentModel[v3.Document]) Report {
+	report := Report{
+		Operations:      []Operation{},
+		Resources:       []Resource{},
+		SecuritySchemes: []SecurityScheme{},
+	}
+
+	if doc.Model.Components != nil {
+		for el := doc.Model.Components.SecuritySchemes.First(); el != nil; el = el.Next() {
+			sec := SecurityScheme{Name: el.Key(), Type: el.Value().Type, Scheme: el.Value().Scheme}
+			_, err := generation.GenerateAuthSchemaName(el.Value())
+			sec.Supported = err == nil
+			if !sec.Supported {
+				report.Issues = append(report.Issues, Issue{
+					Severity: SeverityWarning,
+					Class:    IssueUnsupportedSecurityScheme,
+					Message:  fmt.Sprintf("security scheme %s (%s) is skipped: no authentication resource is generated", el.Key(), describeScheme(sec)),
+				})
+			}
+			report.SecuritySchemes = append(report.SecuritySchemes, sec)
+		}
+	}
+
+	if doc.Model.Paths == nil {
+		return report
+	}
+
+	groups := map[string]*group{}
+	order := []string{}
+	for el := doc.Model.Paths.PathItems.First(); el != nil; el = el.Next() {
+		collection, item := splitPath(el.Key())
+		g, ok := groups[collection]
+		if !ok {
+			g = &group{collectionPath: collection, kind: kindOf(collection), ops: map[string]*v3.Operation{}}
+			groups[collection] = g
+			order = append(order, collection)
+		}
+		if len(item) > 0 && len(g.itemPath) == 0 {
+			g.itemPath = item
+		}
+
+		ops := el.Value().GetOperations()
+		if ops == nil {
+			continue
+		}
+		for op := ops.First(); op != nil; op = op.Next() {
+			method := strings.ToUpper(op.Key())
+			action := g.assign(el.Key(), item, method, op.Value())
+			report.Operations = append(report.Operations, Operation{
+				Path:        el.Key(),
+				Method:      method,
+				OperationID: op.Value().OperationId,
+				Resource:    g.kind,
+				Action:      action,
+			})
+		}
+	}
+
+	for _, collection := range order {
+		if res, ok := groups[collection].lint(); ok {
+			report.Resources = append(report.Resources, res)
+		}
+	}
+
+	return report
+}
+
+// group collects the operations of a candidate resource.
+type group struct {
+	kind           string
+	collectionPath string
+	itemPath       string
+	verbs          []definitionv1alpha1.VerbsDescription
+	// ops by action.
+	ops map[string]*v3.Operation
+}
+
+// assign maps the operation to an action of the resource and returns it. The
+// operation is not mapped when its action is already taken.
+func (g *group) assign(path, item, method string, op *v3.Operation) string {
+	action := inferAction(len(item) > 0, method)
+	if len(action) == 0 {
+		return ""
+	}
+	if _, ok := g.ops[action]; ok {
+		return ""
+	}
+	g.ops[action] = op
+	g.verbs = append(g.verbs, definitionv1alpha1.VerbsDescription{
+		Action: action,
+		Method: method,
+		Path:   path,
+	})
+	return action
+}
+
+func (g *group) lint() (Resource, bool) {
+	if len(g.kind) == 0 || len(g.verbs) == 0 {
+		return Resource{}, false
+	}
+
+	res := Resource{
+		Kind:             g.kind,
+		CollectionPath:   g.collectionPath,
+		ItemPath:         g.itemPath,
+		VerbsDescription: g.verbs,
+		Issues:           []Issue{},
+	}
+	addIssue := func(severity, class, format string, a ...any) {
+		res.Issues = append(res.Issues, Issue{Severity: severity, Class: class, Message: fmt.Sprintf(format, a...)})
+	}
+
+	for _, action := range []string{"create", "get"} {
+		if _, ok := g.ops[action]; !ok {
+			addIssue(SeverityError, IssueMissingAction, "no operation for the %s action", action)
+		}
+	}
+	if _, ok := g.ops["delete"]; !ok {
+		addIssue(SeverityWarning, IssueMissingAction, "no operation for the delete action: resources cannot be deleted")
+	}
+
+	for _, action := range []string{"create", "update"} {
+		op, ok := g.ops[action]
+		if !ok {
+			continue
+		}
+		body := jsonRequestBody(op)
+		if body == nil {
+			addIssue(SeverityError, IssueMissingJSONBody, "the %s operation has no application/json request body", action)
+			continue
+		}
+		for _, msg := range nonStructural(body) {
+			addIssue(SeverityWarning, IssueNonStructuralSchema, "%s request body: %s", action, msg)
+		}
+	}
+
+	if op, ok := g.ops["get"]; ok {
+		res.Identifiers = identifiers(op, g.itemPath)
+		if len(res.Identifiers) == 0 {
+			addIssue(SeverityError, IssueNoStableIdentifier,
+				"the get response has no property matching an id field or the item path parameter")
+		}
+	}
+
+	res.Compatible = true
+	for _, is := range res.Issues {
+		if is.Severity == SeverityError {
+			res.Compatible = false
+		}
+	}
+	return res, true
+}
+
+// inferAction returns the RestDefinition action usually served by method on a
+// collection (or item) path.
+func inferAction(item bool, method string) string {
+	switch {
+	case !item && method == "POST":
+		return "create"
+	case !item && method == "GET":
+		return "findby"
+	case item && method == "GET":
+		return "get"
+	case method == "PUT" || method == "PATCH":
+		return "update"
+	case item && method == "DELETE":
+		return "delete"
+	}
+	return ""
+}
+
+// splitPath returns the collection path of path and, when path ends with a
+// path parameter, the item path.
+func splitPath(path string) (collection string, item string) {
+	trimmed := strings.TrimSuffix(path, "/")
+	idx := strings.LastIndex(trimmed, "/")
+	if idx < 0 {
+		return path, ""
+	}
+	last := trimmed[idx+1:]
+	if strings.HasPrefix(last, "{") && strings.HasSuffix(last, "}") {
+		collection = trimmed[:idx]
+		if len(collection) == 0 {
+			collection = "/"
+		}
+		return collection, path
+	}
+	return path, ""
+}
+
+// kindOf returns the kind suggested by the last static segment of the collection path.
+func kindOf(collection string) string {
+	segments := strings.Split(strings.Trim(collection, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		seg := segments[i]
+		if len(seg) == 0 || strings.HasPrefix(seg, "{") {
+			continue
+		}
+		return flect.Pascalize(flect.Singularize(seg))
+	}
+	return ""
+}
+
+func pathParameter(itemPath string) string {
+	idx := strings.LastIndex(strings.TrimSuffix(itemPath, "/"), "{")
+	if idx < 0 {
+		return ""
+	}
+	return strings.Trim(itemPath[idx:], "{}/")
+}
+
+func jsonRequestBody(op *v3.Operation) *base.SchemaProxy {
+	if op.RequestBody == nil || op.RequestBody.Content == nil {
+		return nil
+	}
+	media := op.RequestBody.Content.Value("application/json")
+	if media == nil {
+		return nil
+	}
+	return media.Schema
+}
+
+// identifiers returns the properties of the get response that are named id or
+// match the item path parameter (e.g. petId matches petId and id).
+func identifiers(op *v3.Operation, itemPath string) []string {
+	props, ok := validation.ResponseProperties(op)
+	if !ok {
+		return nil
+	}
+
+	candidates := map[string]bool{"id": true, "uid": true}
+	if param := strings.ToLower(pathParameter(itemPath)); len(param) > 0 {
+		candidates[param] = true
+		for _, suffix := range []string{"id", "_id", "-id"} {
+			if strings.HasSuffix(param, suffix) && len(param) > len(suffix) {
+				candidates[strings.TrimSuffix(param, suffix)] = true
+			}
+		}
+	}
+
+	res := []string{}
+	for name := range props {
+		if candidates[strings.ToLower(name)] {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// nonStructural returns the constructs of the schema that cannot be expressed in
+// a structural CRD schema.
+func nonStructural(proxy *base.SchemaProxy) []string {
+	res := []string{}
+	visited := map[*base.SchemaProxy]bool{}
+
+	var walk func(path string, proxy *base.SchemaProxy, depth int)
+	walk = func(path string, proxy *base.SchemaProxy, depth int) {
+		if proxy == nil || visited[proxy] || depth > maxSchemaDepth {
+			return
+		}
+		visited[proxy] = true
+
+		schema, err := proxy.BuildSchema()
+		if err != nil || schema == nil {
+			return
+		}
+
+		at := path
+		if len(at) == 0 {
+			at = "."
+		}
+		if len(schema.OneOf) > 0 {
+			res = append(res, fmt.Sprintf("%s uses oneOf", at))
+		}
+		if len(schema.AnyOf) > 0 {
+			res = append(res, fmt.Sprintf("%s uses anyOf", at))
+		}
+		if schema.Not != nil {
+			res = append(res, fmt.Sprintf("%s uses not", at))
+		}
+		if len(schema.Type) == 0 && schema.Properties == nil && len(schema.AllOf) == 0 &&
+			len(schema.OneOf) == 0 && len(schema.AnyOf) == 0 {
+			res = append(res, fmt.Sprintf("%s has no type", at))
+		}
+		if len(schema.Type) > 1 {
+			res = append(res, fmt.Sprintf("%s has multiple types (%s)", at, strings.Join(schema.Type, ", ")))
+		}
+
+		if schema.Properties != nil {
+			for el := schema.Properties.First(); el != nil; el = el.Next() {
+				walk(path+"."+el.Key(), el.Value(), depth+1)
+			}
+		}
+		for _, sub := range schema.AllOf {
+			walk(path, sub, depth+1)
+		}
+		if schema.Items != nil && schema.Items.IsA() {
+			walk(path+"[]", schema.Items.A, depth+1)
+		}
+	}
+
+	walk("", proxy, 0)
+	return res
+}
+
+func describeScheme(sec SecurityScheme) string {
+	if len(sec.Scheme) > 0 {
+		return sec.Type + "/" + sec.Scheme
+	}
+	return sec.Type
+}
@@ -0,0 +1,156 @@
+package lint_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/lint"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+)
+
+func TestLint(t *testing.T) {
+	contents, err := os.ReadFile("../generator/tests/oas/petstore_auth.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	doc, err := oas.Parse(contents)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	report := lint.Lint(doc)
+
+	resources := map[string]lint.Resource{}
+	for _, res := range report.Resources {
+		resources[res.Kind] = res
+	}
+
+	pet, ok := resources["Pet"]
+	if !ok {
+		t.Fatalf("expected a Pet resource, got %+v", report.Resources)
+	}
+	if !pet.Compatible || pet.CollectionPath != "/pet" || pet.ItemPath != "/pet/{petId}" {
+		t.Errorf("unexpected Pet resource: %+v", pet)
+	}
+	actions := map[string]string{}
+	for _, v := range pet.VerbsDescription {
+		actions[v.Action] = v.Method + " " + v.Path
+	}
+	expected := map[string]string{
+		"create": "POST /pet",
+		"update": "PUT /pet",
+		"get":    "GET /pet/{petId}",
+		"delete": "DELETE /pet/{petId}",
+	}
+	for action, op := range expected {
+		if actions[action] != op {
+			t.Errorf("expected %s to be %s, got %q", action, op, actions[action])
+		}
+	}
+	if len(pet.Identifiers) != 1 || pet.Identifiers[0] != "id" {
+		t.Errorf("expected id identifier, got %v", pet.Identifiers)
+	}
+
+	upload, ok := resources["UploadImage"]
+	if !ok {
+		t.Fatalf("expected an UploadImage resource")
+	}
+	if upload.Compatible {
+		t.Errorf("expected UploadImage not to be compatible")
+	}
+	classes := map[string]bool{}
+	for _, is := range upload.Issues {
+		classes[is.Class] = true
+	}
+	if !classes[lint.IssueMissingJSONBody] || !classes[lint.IssueMissingAction] {
+		t.Errorf("unexpected issues: %+v", upload.Issues)
+	}
+
+	unsupported := 0
+	for _, sec := range report.SecuritySchemes {
+		if !sec.Supported {
+			unsupported++
+		}
+	}
+	issues := 0
+	for _, is := range report.Issues {
+		if is.Class == lint.IssueUnsupportedSecurityScheme {
+			issues++
+		}
+	}
+	if unsupported == 0 || issues != unsupported {
+		t.Errorf("expected an issue per unsupported security scheme, got %d schemes and %d issues", unsupported, issues)
+	}
+
+	operations := 0
+	for el := doc.Model.Paths.PathItems.First(); el != nil; el = el.Next() {
+		operations += el.Value().GetOperations().Len()
+	}
+	if len(report.Operations) != operations {
+		t.Errorf("expected %d operations, got %d", operations, len(report.Operations))
+	}
+}
+
+func TestLintNonStructuralSchema(t *testing.T) {
+	doc, err := oas.Parse([]byte(`
+openapi: 3.0.0
+info: {title: test, version: "1"}
+paths:
+  /items:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name: {type: string}
+                value:
+                  oneOf:
+                  - {type: string}
+                  - {type: integer}
+                extra: {}
+      responses:
+        "201": {description: created}
+  /items/{itemId}:
+    get:
+      parameters:
+      - {name: itemId, in: path, required: true, schema: {type: string}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  itemId: {type: string}
+`))
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	report := lint.Lint(doc)
+	if len(report.Resources) != 1 {
+		t.Fatalf("expected a single resource, got %+v", report.Resources)
+	}
+	res := report.Resources[0]
+	if res.Kind != "Item" || len(res.Identifiers) != 1 || res.Identifiers[0] != "itemId" {
+		t.Errorf("unexpected resource: %+v", res)
+	}
+
+	messages := map[string]bool{}
+	for _, is := range res.Issues {
+		if is.Class == lint.IssueNonStructuralSchema {
+			messages[is.Message] = true
+		}
+	}
+	for _, msg := range []string{"create request body: .value uses oneOf", "create request body: .extra has no type"} {
+		if !messages[msg] {
+			t.Errorf("expected issue %q, got %v", msg, res.Issues)
+		}
+	}
+	if !res.Compatible {
+		t.Errorf("expected non structural schemas to be warnings only, got %+v", res.Issues)
+	}
+}
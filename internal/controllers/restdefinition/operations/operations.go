@@ -0,0 +1,321 @@
+// Package operations builds the operation map of a RestDefinition: for each
+// action of its resources, the HTTP request the dynamic controller has to send,
+// resolved against the OAS document.
+package operations
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/text"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+// Version of the format of the operation map. It changes on breaking changes only.
+const Version = "operations.swaggergen.krateo.io/v1"
+
+// Locations of the request fields.
+const (
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+	InCookie = "cookie"
+	InBody   = "body"
+)
+
+// Map is the operation map of a RestDefinition.
+type Map struct {
+	Version   string     `json:"version"`
+	Resources []Resource `json:"resources"`
+}
+
+// Resource lists the operations of a generated kind.
+type Resource struct {
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Kind     string `json:"kind"`
+	Resource string `json:"resource"`
+	// Identifiers are the status fields identifying the external resource.
+	Identifiers []Identifier `json:"identifiers,omitempty"`
+	// ConnectionDetails are the values of the response of the create action written
+	// to the connection Secret.
+	ConnectionDetails []ConnectionDetail `json:"connectionDetails,omitempty"`
+	Operations        []Operation        `json:"operations"`
+}
+
+// ConnectionDetail tells where a key of the connection Secret is read from in the
+// response of the create action.
+type ConnectionDetail struct {
+	Key string `json:"key"`
+	// Pointer is the JSON pointer to the value, relative to the response pointer of the create action.
+	Pointer string `json:"pointer"`
+}
+
+// Identifier tells where an identifier is read from in the resource returned by the get action.
+type Identifier struct {
+	// Field is the name of the status field.
+	Field string `json:"field"`
+	// Pointer is the JSON pointer to the value, relative to the response pointer of the get action.
+	Pointer string `json:"pointer"`
+	// Type is the JSON type of the value.
+	Type string `json:"type"`
+}
+
+// Operation is the HTTP request sent for an action.
+type Operation struct {
+	Action      string `json:"action"`
+	Method      string `json:"method"`
+	OperationID string `json:"operationId,omitempty"`
+	// Server is the URL the path is relative to.
+	Server string `json:"server,omitempty"`
+	// Path is the path template, i.e. '/pet/{petId}'.
+	Path string `json:"path"`
+	// ResponsePointer is the JSON pointer to the resource in the response body, empty
+	// for the body itself.
+	ResponsePointer string `json:"responsePointer,omitempty"`
+	// ContentType of the request body, if any.
+	ContentType string `json:"contentType,omitempty"`
+	// Fields of the spec sent with the request.
+	Fields []Field `json:"fields,omitempty"`
+	// SuccessCodes are the documented 2xx status codes of the response.
+	SuccessCodes []int `json:"successCodes,omitempty"`
+	// Pagination of the results of a findby action, if any.
+	Pagination *Pagination `json:"pagination,omitempty"`
+	// Async tells how to poll the completion of an operation answered with '202 Accepted'.
+	Async *Async `json:"async,omitempty"`
+}
+
+// Async describes how to poll a long-running operation.
+type Async struct {
+	// StatusPath is the path template of the status endpoint, polled with GET, relative to Server.
+	StatusPath string `json:"statusPath,omitempty"`
+	// StatusHeader is the header of the 202 response holding the URL of the status endpoint.
+	StatusHeader string `json:"statusHeader,omitempty"`
+	// StatePointer is the JSON pointer to the state in the response of the status endpoint.
+	StatePointer    string   `json:"statePointer"`
+	SucceededValues []string `json:"succeededValues"`
+	FailedValues    []string `json:"failedValues,omitempty"`
+}
+
+// Pagination tells how to request the pages of the results of an operation.
+type Pagination struct {
+	// Strategy is PageNumber, Cursor or LinkHeader.
+	Strategy      string `json:"strategy"`
+	PageParameter string `json:"pageParameter,omitempty"`
+	SizeParameter string `json:"sizeParameter,omitempty"`
+	PageSize      int    `json:"pageSize,omitempty"`
+	// ItemsPointer is the JSON pointer to the items in the response body, empty for the body itself.
+	ItemsPointer      string `json:"itemsPointer,omitempty"`
+	NextCursorPointer string `json:"nextCursorPointer,omitempty"`
+	// MaxPages is the maximum number of pages requested, 0 for unlimited.
+	MaxPages int `json:"maxPages,omitempty"`
+}
+
+// Field maps a field of the spec (or of the status, for identifiers) to a part of the request.
+type Field struct {
+	// Field is the name of the field in the spec.
+	Field string `json:"field"`
+	// In is where the field goes: path, query, header, cookie or body.
+	In string `json:"in"`
+	// Name of the parameter or of the body property. Empty for the field holding
+	// the whole body of array bodies.
+	Name     string `json:"name,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	// Identifier is true when the value is an identifier, read from the status.
+	Identifier bool `json:"identifier,omitempty"`
+}
+
+// Build returns the operation map of the resources of spec, at version. Actions
+// not matching an operation of doc are reported as errors.
+func Build(doc *libopenapi.DocumentModel[v3.Document], spec *definitionv1alpha1.RestDefinitionSpec, version string) (*Map, error) {
+	doc = pristine(doc)
+	res := &Map{Version: Version, Resources: []Resource{}}
+	for _, r := range spec.AllResources() {
+		gvk := schema.GroupVersionKind{Group: spec.ResourceGroup, Version: version, Kind: text.CapitaliseFirstLetter(r.Kind)}
+		ids, errs := validation.ResolveIdentifiers(doc, r, field.NewPath("resource"))
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("resource %s: %w", r.Kind, errs.ToAggregate())
+		}
+		el := Resource{
+			Group:      gvk.Group,
+			Version:    gvk.Version,
+			Kind:       gvk.Kind,
+			Resource:   deployment.ToGroupVersionResource(gvk).Resource,
+			Operations: []Operation{},
+		}
+		fields := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if validation.IsSensitive(id.Schema) {
+				// Not in the status
+				continue
+			}
+			typ := "string"
+			if id.Schema != nil && len(id.Schema.Type) > 0 {
+				typ = id.Schema.Type[0]
+			}
+			el.Identifiers = append(el.Identifiers, Identifier{Field: id.Field, Pointer: id.Pointer, Type: typ})
+			fields = append(fields, id.Field)
+		}
+		for _, detail := range r.ConnectionDetails {
+			el.ConnectionDetails = append(el.ConnectionDetails, ConnectionDetail{Key: detail.Key, Pointer: detail.Pointer})
+		}
+		for i, verb := range r.VerbsDescription {
+			op, err := operation(doc, verb, fields, spec.Server, field.NewPath("verbsDescription").Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("resource %s: %w", r.Kind, err)
+			}
+			el.Operations = append(el.Operations, op)
+		}
+		res.Resources = append(res.Resources, el)
+	}
+	return res, nil
+}
+
+// pristine returns the model of doc rebuilt from its low level document: the
+// schema generator edits the schemas of the high level model in place (i.e. adding
+// the parameters to the request body), while the operations are mapped against
+// the document as written.
+func pristine(doc *libopenapi.DocumentModel[v3.Document]) *libopenapi.DocumentModel[v3.Document] {
+	low := doc.Model.GoLow()
+	if low == nil {
+		return doc
+	}
+	return &libopenapi.DocumentModel[v3.Document]{Model: *v3.NewDocument(low), Index: doc.Index}
+}
+
+func operation(doc *libopenapi.DocumentModel[v3.Document], verb definitionv1alpha1.VerbsDescription, identifiers []string, sel *definitionv1alpha1.Server, verbPath *field.Path) (Operation, error) {
+	op, ferr := validation.LookupOperation(doc, verb, verbPath)
+	if ferr != nil {
+		return Operation{}, ferr
+	}
+	item := doc.Model.Paths.PathItems.Value(verb.Path)
+	server, ferr := validation.ServerURL(doc, verb, op, sel, field.NewPath("server"))
+	if ferr != nil {
+		return Operation{}, ferr
+	}
+
+	res := Operation{
+		Action:          verb.Action,
+		Method:          strings.ToUpper(verb.Method),
+		OperationID:     op.OperationId,
+		Server:          server,
+		Path:            verb.Path,
+		ResponsePointer: verb.ResponsePointer,
+		SuccessCodes:    successCodes(op),
+	}
+
+	if p := verb.Pagination; p != nil {
+		res.Pagination = &Pagination{
+			Strategy:          p.Strategy,
+			PageParameter:     p.PageParameter,
+			SizeParameter:     p.SizeParameter,
+			PageSize:          ptr.Deref(p.PageSize, 0),
+			ItemsPointer:      p.ItemsPointer,
+			NextCursorPointer: p.NextCursorPointer,
+			MaxPages:          ptr.Deref(p.MaxPages, 0),
+		}
+	}
+
+	if a := verb.Async; a != nil {
+		res.Async = &Async{
+			StatusPath:      a.StatusPath,
+			StatusHeader:    a.StatusHeader,
+			StatePointer:    a.StatePointer,
+			SucceededValues: a.SucceededValues,
+			FailedValues:    a.FailedValues,
+		}
+	}
+
+	// Operation parameters override the path item ones with the same name and location.
+	params := map[string]*v3.Parameter{}
+	keys := []string{}
+	for _, p := range append(slices.Clone(item.Parameters), op.Parameters...) {
+		if p == nil {
+			continue
+		}
+		key := p.In + "/" + p.Name
+		if _, ok := params[key]; !ok {
+			keys = append(keys, key)
+		}
+		params[key] = p
+	}
+	for _, key := range keys {
+		p := params[key]
+		res.Fields = append(res.Fields, Field{
+			Field:      p.Name,
+			In:         p.In,
+			Name:       p.Name,
+			Required:   p.Required != nil && *p.Required,
+			Identifier: slices.Contains(identifiers, p.Name),
+		})
+	}
+
+	if op.RequestBody != nil && op.RequestBody.Content != nil && op.RequestBody.Content.Len() > 0 {
+		res.ContentType = op.RequestBody.Content.First().Key()
+		if op.RequestBody.Content.Value("application/json") != nil {
+			res.ContentType = "application/json"
+		}
+		res.Fields = append(res.Fields, bodyFields(op, res.ContentType, identifiers)...)
+	}
+
+	return res, nil
+}
+
+// bodyFields maps the top level properties of the request body to the spec fields of
+// the same name. An array body is held by the items field.
+func bodyFields(op *v3.Operation, contentType string, identifiers []string) []Field {
+	media := op.RequestBody.Content.Value(contentType)
+	if media == nil || media.Schema == nil {
+		return nil
+	}
+	body, err := media.Schema.BuildSchema()
+	if err != nil || body == nil {
+		return nil
+	}
+	required := op.RequestBody.Required != nil && *op.RequestBody.Required
+
+	if slices.Contains(body.Type, "array") {
+		return []Field{{Field: "items", In: InBody, Required: required}}
+	}
+
+	res := []Field{}
+	if body.Properties == nil {
+		return res
+	}
+	for prop := body.Properties.First(); prop != nil; prop = prop.Next() {
+		res = append(res, Field{
+			Field:      prop.Key(),
+			In:         InBody,
+			Name:       prop.Key(),
+			Required:   slices.Contains(body.Required, prop.Key()),
+			Identifier: slices.Contains(identifiers, prop.Key()),
+		})
+	}
+	return res
+}
+
+func successCodes(op *v3.Operation) []int {
+	res := []int{}
+	if op.Responses == nil || op.Responses.Codes == nil {
+		return res
+	}
+	for el := op.Responses.Codes.First(); el != nil; el = el.Next() {
+		code, err := strconv.Atoi(el.Key())
+		if err != nil || code < 200 || code > 299 {
+			continue
+		}
+		res = append(res, code)
+	}
+	sort.Ints(res)
+	return res
+}
@@ -0,0 +1,137 @@
+package operations_test
+
+import (
+	"slices"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/generator"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/operations"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+)
+
+func TestBuild(t *testing.T) {
+	doc, err := oas.Load("../generator/tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to load document: %v", err)
+	}
+
+	spec := definitionv1alpha1.RestDefinitionSpec{
+		ResourceGroup: "petstore.krateo.io",
+		Resource: definitionv1alpha1.Resource{
+			Kind:        "Pet",
+			Identifiers: []string{"id"},
+			VerbsDescription: []definitionv1alpha1.VerbsDescription{
+				{Action: "create", Method: "POST", Path: "/pet"},
+				{Action: "get", Method: "GET", Path: "/pet/{petId}"},
+				{Action: "delete", Method: "DELETE", Path: "/pet/{petId}"},
+			},
+			ConnectionDetails: []definitionv1alpha1.ConnectionDetail{{Key: "name", Pointer: "/name"}},
+		},
+	}
+
+	res, err := operations.Build(doc, &spec, "v1alpha1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Version != operations.Version || len(res.Resources) != 1 {
+		t.Fatalf("unexpected operation map %+v", res)
+	}
+	pet := res.Resources[0]
+	if pet.Kind != "Pet" || pet.Resource != "pets" || len(pet.Operations) != 3 {
+		t.Fatalf("unexpected resource %+v", pet)
+	}
+	if !slices.Equal(pet.Identifiers, []operations.Identifier{{Field: "id", Pointer: "/id", Type: "integer"}}) {
+		t.Errorf("unexpected identifiers %+v", pet.Identifiers)
+	}
+	if !slices.Equal(pet.ConnectionDetails, []operations.ConnectionDetail{{Key: "name", Pointer: "/name"}}) {
+		t.Errorf("unexpected connection details %+v", pet.ConnectionDetails)
+	}
+
+	create := pet.Operations[0]
+	if create.Method != "POST" || create.OperationID != "addPet" || create.Server != "/api/v3" {
+		t.Errorf("unexpected create operation %+v", create)
+	}
+	if create.ContentType != "application/json" || !slices.Equal(create.SuccessCodes, []int{200}) {
+		t.Errorf("unexpected create request %+v", create)
+	}
+	if !slices.Contains(create.Fields, operations.Field{Field: "name", In: operations.InBody, Name: "name", Required: true}) {
+		t.Errorf("expected the name body property, got %+v", create.Fields)
+	}
+	if !slices.Contains(create.Fields, operations.Field{Field: "id", In: operations.InBody, Name: "id", Identifier: true}) {
+		t.Errorf("expected the id identifier, got %+v", create.Fields)
+	}
+
+	del := pet.Operations[2]
+	if len(del.ContentType) > 0 || len(del.SuccessCodes) > 0 {
+		t.Errorf("unexpected delete operation %+v", del)
+	}
+	expected := []operations.Field{
+		{Field: "api_key", In: operations.InHeader, Name: "api_key"},
+		{Field: "petId", In: operations.InPath, Name: "petId", Required: true},
+	}
+	if !slices.Equal(del.Fields, expected) {
+		t.Errorf("expected %+v, got %+v", expected, del.Fields)
+	}
+
+	spec.Server = &definitionv1alpha1.Server{URL: "https://staging.example.com/api/v3"}
+	res, err = operations.Build(doc, &spec, "v1alpha1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := res.Resources[0].Operations[0].Server; got != spec.Server.URL {
+		t.Errorf("expected the server override, got %s", got)
+	}
+
+	spec.Resource.VerbsDescription = append(spec.Resource.VerbsDescription,
+		definitionv1alpha1.VerbsDescription{Action: "get", Method: "GET", Path: "/pets/{petId}"})
+	if _, err := operations.Build(doc, &spec, "v1alpha1"); err == nil {
+		t.Errorf("expected an error for an unknown path")
+	}
+}
+
+func TestBuildAfterGenerate(t *testing.T) {
+	doc, err := oas.Load("../generator/tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to load document: %v", err)
+	}
+
+	spec := definitionv1alpha1.RestDefinitionSpec{
+		ResourceGroup: "petstore.krateo.io",
+		Resource: definitionv1alpha1.Resource{
+			Kind:        "Pet",
+			Identifiers: []string{"id"},
+			VerbsDescription: []definitionv1alpha1.VerbsDescription{
+				{Action: "create", Method: "POST", Path: "/pet"},
+				{Action: "get", Method: "GET", Path: "/pet/{petId}"},
+				{Action: "delete", Method: "DELETE", Path: "/pet/{petId}"},
+			},
+		},
+	}
+
+	// The generator adds the parameters and the authentication references to the
+	// body schema of the create action
+	if _, err, _ := generator.GenerateByteSchemas(doc, spec.Resource, spec.Resource.Identifiers); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	res, err := operations.Build(doc, &spec, "v1alpha1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	create := res.Resources[0].Operations[0]
+	for _, f := range create.Fields {
+		if f.In != operations.InBody {
+			t.Errorf("unexpected field %+v", f)
+		}
+		if slices.Contains([]string{"petId", "api_key", "authenticationRefs"}, f.Field) {
+			t.Errorf("expected %s not to be a body property", f.Field)
+		}
+	}
+	if !slices.Contains(create.Fields, operations.Field{Field: "name", In: operations.InBody, Name: "name", Required: true}) {
+		t.Errorf("expected the name body property to be required, got %+v", create.Fields)
+	}
+	if ids := res.Resources[0].Identifiers; len(ids) != 1 || ids[0].Type != "integer" {
+		t.Errorf("unexpected identifiers %+v", ids)
+	}
+}
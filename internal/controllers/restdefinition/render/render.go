@@ -0,0 +1,442 @@
+// Package render builds the manifests the provider installs for a RestDefinition:
+// the CRDs generated from the OAS document, the RBAC and the Deployment of the
+// dynamic controller. It never talks to a cluster.
+package render
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/generator"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/operations"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/crds"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/emitter"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/text"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/rbactools"
+
+	"github.com/krateoplatformops/crdgen"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResourceVersion is the version of the CRDs generated for a RestDefinition.
+const ResourceVersion = "v1alpha1"
+
+// ResourceGVK returns the GroupVersionKind of the resource res of cr.
+func ResourceGVK(cr *definitionv1alpha1.RestDefinition, res definitionv1alpha1.Resource) schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   cr.Spec.ResourceGroup,
+		Version: ResourceVersion,
+		Kind:    text.CapitaliseFirstLetter(res.Kind),
+	}
+}
+
+// ResourceGVKs returns the GroupVersionKind of each resource of cr.
+func ResourceGVKs(cr *definitionv1alpha1.RestDefinition) []schema.GroupVersionKind {
+	resources := cr.Spec.AllResources()
+	res := make([]schema.GroupVersionKind, 0, len(resources))
+	for _, r := range resources {
+		res = append(res, ResourceGVK(cr, r))
+	}
+	return res
+}
+
+// RecordedGVKs returns the ResourceGVKs of cr followed by the kinds recorded in its
+// status and removed from its spec, whose custom resources are still to be drained.
+func RecordedGVKs(cr *definitionv1alpha1.RestDefinition) []schema.GroupVersionKind {
+	res := ResourceGVKs(cr)
+	for _, el := range cr.Status.Resources {
+		gvk := schema.FromAPIVersionAndKind(el.APIVersion, el.Kind)
+		if !slices.Contains(res, gvk) {
+			res = append(res, gvk)
+		}
+	}
+	return res
+}
+
+// AuthGVK returns the GroupVersionKind of the authentication resource generated
+// for the security scheme authSchemaName.
+func AuthGVK(cr *definitionv1alpha1.RestDefinition, authSchemaName string) schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   cr.Spec.ResourceGroup,
+		Version: ResourceVersion,
+		Kind:    text.CapitaliseFirstLetter(authSchemaName),
+	}
+}
+
+// AuthSchemaNames returns the name of the authentication resource of each
+// security scheme of the document. Unsupported schemes are skipped and their
+// errors returned.
+func AuthSchemaNames(doc *libopenapi.DocumentModel[v3.Document]) ([]string, []error) {
+	names := []string{}
+	errs := []error{}
+	if doc.Model.Components == nil {
+		return names, errs
+	}
+	for secSchemaPair := doc.Model.Components.SecuritySchemes.First(); secSchemaPair != nil; secSchemaPair = secSchemaPair.Next() {
+		authSchemaName, err := generation.GenerateAuthSchemaName(secSchemaPair.Value())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		names = append(names, authSchemaName)
+	}
+	return names, errs
+}
+
+// Schemas pairs a resource of a RestDefinition with the schemas generated for it.
+type Schemas struct {
+	Resource  definitionv1alpha1.Resource
+	Generator *generator.OASSchemaGenerator
+}
+
+// GenerateSchemas generates the schemas of each resource of cr, in the order of
+// spec.AllResources. The non fatal errors of the generator are returned as warnings.
+func GenerateSchemas(doc *libopenapi.DocumentModel[v3.Document], cr *definitionv1alpha1.RestDefinition) ([]Schemas, []error, error) {
+	res := []Schemas{}
+	warnings := []error{}
+	for _, r := range cr.Spec.AllResources() {
+		gen, err, errs := generator.GenerateByteSchemas(doc, r, r.Identifiers)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generating byte schemas of %s: %w", r.Kind, err)
+		}
+		warnings = append(warnings, errs...)
+		res = append(res, Schemas{Resource: r, Generator: gen})
+	}
+	if len(res) == 0 {
+		return nil, nil, fmt.Errorf("no resource to generate")
+	}
+	return res, warnings, nil
+}
+
+// Digest returns the digest of the schemas generated for a RestDefinition. It is
+// the digest of the generator when there is a single resource.
+func Digest(schemas []Schemas) string {
+	if len(schemas) == 1 {
+		return schemas[0].Generator.Digest()
+	}
+
+	h := sha256.New()
+	for _, s := range schemas {
+		h.Write([]byte(s.Resource.Kind))
+		h.Write([]byte{0})
+		h.Write([]byte(s.Generator.Digest()))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("sha256:%s", hex.EncodeToString(h.Sum(nil)))
+}
+
+// ResourceCRD renders the CRD of the resource res of cr from the schemas of gen.
+func ResourceCRD(ctx context.Context, gen *generator.OASSchemaGenerator, cr *definitionv1alpha1.RestDefinition, res definitionv1alpha1.Resource) (*apiextensionsv1.CustomResourceDefinition, error) {
+	resource := crdgen.Generate(ctx, crdgen.Options{
+		Managed:                true,
+		WorkDir:                fmt.Sprintf("gen-crds/%s", res.Kind),
+		GVK:                    ResourceGVK(cr, res),
+		Categories:             []string{strings.ToLower(res.Kind)},
+		SpecJsonSchemaGetter:   gen.OASSpecJsonSchemaGetter(),
+		StatusJsonSchemaGetter: gen.OASStatusJsonSchemaGetter(),
+	})
+	if resource.Err != nil {
+		return nil, fmt.Errorf("generating CRD: %w", resource.Err)
+	}
+
+	crd, err := crds.UnmarshalCRD(resource.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling CRD: %w", err)
+	}
+	if cr.Spec.Scope == definitionv1alpha1.ScopeCluster {
+		crd.Spec.Scope = apiextensionsv1.ClusterScoped
+	}
+
+	deployment.SetOwnerLabels(crd, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name})
+	return crd, nil
+}
+
+// AuthCRD renders the CRD of the authentication resource authSchemaName from the schemas of gen.
+func AuthCRD(ctx context.Context, gen *generator.OASSchemaGenerator, cr *definitionv1alpha1.RestDefinition, authSchemaName string) (*apiextensionsv1.CustomResourceDefinition, error) {
+	resource := crdgen.Generate(ctx, crdgen.Options{
+		Managed:                false,
+		WorkDir:                fmt.Sprintf("gen-crds/%s", authSchemaName),
+		GVK:                    AuthGVK(cr, authSchemaName),
+		Categories:             categories(cr),
+		SpecJsonSchemaGetter:   gen.OASAuthJsonSchemaGetter(authSchemaName),
+		StatusJsonSchemaGetter: generator.StaticJsonSchemaGetter(),
+	})
+	if resource.Err != nil {
+		return nil, fmt.Errorf("generating CRD: %w", resource.Err)
+	}
+
+	crd, err := crds.UnmarshalCRD(resource.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling CRD: %w", err)
+	}
+
+	deployment.SetOwnerLabels(crd, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name})
+	return crd, nil
+}
+
+// categories returns the categories of the authentication CRDs of cr: the kind
+// of each of its resources.
+func categories(cr *definitionv1alpha1.RestDefinition) []string {
+	res := []string{}
+	for _, r := range cr.Spec.AllResources() {
+		res = append(res, strings.ToLower(r.Kind))
+	}
+	return res
+}
+
+// Role returns the role of the dynamic controller of cr, granting access to its
+// resources, to the supplied authentication resources and, when connection details
+// are declared, the creation of the connection secrets. Access to the secrets referenced
+// by the authentication resources, and to the connection secrets already created, is
+// not included, since it depends on the resources living in the cluster.
+func Role(cr *definitionv1alpha1.RestDefinition, authGVKs []schema.GroupVersionKind) (rbacv1.Role, error) {
+	role, err := rbactools.InitRole(deployment.ControllerNamespacedName(types.NamespacedName{
+		Namespace: cr.GetNamespace(),
+		Name:      cr.GetName(),
+	}))
+	if err != nil {
+		return rbacv1.Role{}, fmt.Errorf("initializing role: %w", err)
+	}
+
+	for _, gvk := range ResourceGVKs(cr) {
+		rbactools.PopulateRole(gvk, &role)
+	}
+	for _, gvk := range authGVKs {
+		rbactools.PopulateAuthRole(gvk, &role)
+	}
+	for _, res := range cr.Spec.AllResources() {
+		if len(res.ConnectionDetails) > 0 {
+			rbactools.PopulateConnectionSecretsRole(&role)
+			break
+		}
+	}
+	return role, nil
+}
+
+// ProviderRole returns the ClusterRole aggregated into the role of the provider for
+// cr, granting access to its resources (see RecordedGVKs), drained when cr is deleted
+// or when they are removed from its spec, and read access
+// to the supplied authentication resources, whose secret references make up the
+// role of the dynamic controller.
+func ProviderRole(cr *definitionv1alpha1.RestDefinition, authGVKs []schema.GroupVersionKind) rbacv1.ClusterRole {
+	role := rbacv1.Role{}
+	for _, gvk := range RecordedGVKs(cr) {
+		rbactools.PopulateInstancesRole(gvk, &role)
+	}
+	for _, gvk := range authGVKs {
+		rbactools.PopulateAuthRole(gvk, &role)
+	}
+	return deployment.ProviderClusterRole(types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}, role.Rules)
+}
+
+// TypeScriptKey is the key of the TypeScript definitions in the artifacts ConfigMap.
+const TypeScriptKey = "types.d.ts"
+
+// SchemaKey returns the key of the JSON schema of the root type name (i.e. PetSpec)
+// in the artifacts ConfigMap.
+func SchemaKey(name string) string {
+	return name + ".schema.json"
+}
+
+// Artifacts renders the ConfigMap publishing the JSON schemas generated for cr
+// (spec and status of the resources, spec of the authentication resources) and the
+// TypeScript definitions of the same types.
+func Artifacts(cr *definitionv1alpha1.RestDefinition, schemas []Schemas) (*corev1.ConfigMap, error) {
+	if len(schemas) == 0 {
+		return nil, fmt.Errorf("no schemas to publish")
+	}
+
+	roots := []emitter.TypeScriptRoot{}
+	for _, s := range schemas {
+		kind := ResourceGVK(cr, s.Resource).Kind
+		roots = append(roots, emitter.TypeScriptRoot{Kind: kind, Name: kind + "Spec", Schema: s.Generator.SpecSchema()})
+		if len(s.Generator.StatusSchema()) > 0 {
+			roots = append(roots, emitter.TypeScriptRoot{Kind: kind, Name: kind + "Status", Schema: s.Generator.StatusSchema()})
+		}
+	}
+	// The authentication schemas come from the security schemes of the document,
+	// they are the same for every resource.
+	auths := schemas[0].Generator.AuthSchemas()
+	authNames := make([]string, 0, len(auths))
+	for name := range auths {
+		authNames = append(authNames, name)
+	}
+	sort.Strings(authNames)
+	for _, name := range authNames {
+		authKind := AuthGVK(cr, name).Kind
+		roots = append(roots, emitter.TypeScriptRoot{Kind: authKind, Name: authKind + "Spec", Schema: auths[name]})
+	}
+
+	data := make(map[string]string, len(roots)+1)
+	for _, root := range roots {
+		buf := &bytes.Buffer{}
+		if err := json.Indent(buf, root.Schema, "", "  "); err != nil {
+			return nil, fmt.Errorf("formatting schema of %s: %w", root.Name, err)
+		}
+		data[SchemaKey(root.Name)] = buf.String()
+	}
+	ts, err := emitter.EmitTypeScript(roots...)
+	if err != nil {
+		return nil, fmt.Errorf("generating TypeScript definitions: %w", err)
+	}
+	data[TypeScriptKey] = string(ts)
+
+	nn := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	res := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.ArtifactsNamespacedName(nn).Name,
+			Namespace: cr.Namespace,
+		},
+		Data: data,
+	}
+	deployment.SetOwnerLabels(res, nn)
+	return res, nil
+}
+
+// Operations renders the ConfigMap holding the operation map of cr, mounted into
+// its dynamic controller.
+func Operations(doc *libopenapi.DocumentModel[v3.Document], cr *definitionv1alpha1.RestDefinition) (*corev1.ConfigMap, error) {
+	m, err := operations.Build(doc, &cr.Spec, ResourceVersion)
+	if err != nil {
+		return nil, fmt.Errorf("building operation map: %w", err)
+	}
+	dat, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding operation map: %w", err)
+	}
+
+	nn := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	res := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.OperationsNamespacedName(nn).Name,
+			Namespace: cr.Namespace,
+		},
+		Data: map[string]string{deployment.OperationsKey: string(dat)},
+	}
+	deployment.SetOwnerLabels(res, nn)
+	return res, nil
+}
+
+// Result holds the rendered manifests, in installation order, and the warnings
+// of the schema generation.
+type Result struct {
+	Objects  []client.Object
+	Warnings []error
+}
+
+// Render renders every manifest the provider installs for cr: the resource and
+// authentication CRDs, the artifacts and operations ConfigMaps, the role of the provider on
+// the generated kinds, the ServiceAccount, the RBAC and the Deployment of the dynamic
+// controller shared by the resources. extendedArgs is true when the dynamic controller
+// image accepts one -resource argument per resource and the -operations argument.
+func Render(ctx context.Context, doc *libopenapi.DocumentModel[v3.Document], cr *definitionv1alpha1.RestDefinition, extendedArgs bool) (Result, error) {
+	schemas, warnings, err := GenerateSchemas(doc, cr)
+	if err != nil {
+		return Result{}, err
+	}
+	res := Result{Warnings: warnings}
+
+	for _, s := range schemas {
+		crd, err := ResourceCRD(ctx, s.Generator, cr, s.Resource)
+		if err != nil {
+			return Result{}, err
+		}
+		res.Objects = append(res.Objects, crd)
+	}
+
+	names, errs := AuthSchemaNames(doc)
+	res.Warnings = append(res.Warnings, errs...)
+	authGVKs := make([]schema.GroupVersionKind, 0, len(names))
+	for _, name := range names {
+		crd, err := AuthCRD(ctx, schemas[0].Generator, cr, name)
+		if err != nil {
+			return Result{}, err
+		}
+		res.Objects = append(res.Objects, crd)
+		authGVKs = append(authGVKs, AuthGVK(cr, name))
+	}
+
+	artifacts, err := Artifacts(cr, schemas)
+	if err != nil {
+		return Result{}, err
+	}
+	res.Objects = append(res.Objects, artifacts)
+
+	ops, err := Operations(doc, cr)
+	if err != nil {
+		return Result{}, err
+	}
+	res.Objects = append(res.Objects, ops)
+
+	providerRole := ProviderRole(cr, authGVKs)
+	res.Objects = append(res.Objects, &providerRole)
+
+	nn := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	role, err := Role(cr, authGVKs)
+	if err != nil {
+		return Result{}, err
+	}
+	watchNamespaces := deployment.WatchNamespaces(&cr.Spec, cr.Namespace)
+
+	if len(cr.Spec.ControllerPool) > 0 {
+		// The shared controller of a pool serving cr alone: the other members
+		// are only known in the cluster.
+		pool := deployment.NewPool(types.NamespacedName{Namespace: cr.Namespace, Name: cr.Spec.ControllerPool}, []deployment.PoolMember{{
+			NamespacedName:  nn,
+			GVRs:            deployment.ResourceGVRs(&cr.Spec, ResourceVersion),
+			Rules:           role.Rules,
+			WatchNamespaces: watchNamespaces,
+		}})
+		pool.ExtendedArgs = extendedArgs
+		res.Objects = append(res.Objects, deployment.DesiredRBAC(pool.RBACOptions(nil, nil))...)
+
+		dep, err := pool.Deployment()
+		if err != nil {
+			return Result{}, fmt.Errorf("creating deployment: %w", err)
+		}
+		dep.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+		res.Objects = append(res.Objects, &dep)
+		return res, nil
+	}
+
+	res.Objects = append(res.Objects, deployment.DesiredRBAC(deployment.RBACOptions{
+		NamespacedName:  nn,
+		Rules:           role.Rules,
+		WatchNamespaces: watchNamespaces,
+	})...)
+
+	dep, err := deployment.CreateDeployment(deployment.ResourceGVRs(&cr.Spec, ResourceVersion), nn, watchNamespaces, extendedArgs)
+	if err != nil {
+		return Result{}, fmt.Errorf("creating deployment: %w", err)
+	}
+	dep.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	res.Objects = append(res.Objects, &dep)
+
+	return res, nil
+}
@@ -0,0 +1,264 @@
+package render_test
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/operations"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/render"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var petDefinition = &definitionv1alpha1.RestDefinition{
+	ObjectMeta: metav1.ObjectMeta{Name: "def-pet", Namespace: "default"},
+	Spec: definitionv1alpha1.RestDefinitionSpec{
+		ResourceGroup: "petstore.swagger.io",
+		Resource:      definitionv1alpha1.Resource{Kind: "pet"},
+	},
+}
+
+func TestAuthSchemaNames(t *testing.T) {
+	contents, err := os.ReadFile("../generator/tests/oas/petstore_auth.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	doc, err := oas.Parse(contents)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	names, errs := render.AuthSchemaNames(doc)
+	if len(names)+len(errs) != doc.Model.Components.SecuritySchemes.Len() {
+		t.Errorf("expected a name or an error per security scheme, got %v and %v", names, errs)
+	}
+	for _, name := range names {
+		gvk := render.AuthGVK(petDefinition, name)
+		if gvk.Group != "petstore.swagger.io" || gvk.Version != render.ResourceVersion || len(gvk.Kind) == 0 {
+			t.Errorf("unexpected auth gvk %s", gvk)
+		}
+	}
+}
+
+func TestRole(t *testing.T) {
+	gvk := render.ResourceGVK(petDefinition, petDefinition.Spec.Resource)
+	if gvk.Kind != "Pet" {
+		t.Errorf("expected capitalised kind, got %s", gvk.Kind)
+	}
+
+	role, err := render.Role(petDefinition, []schema.GroupVersionKind{
+		{Group: "petstore.swagger.io", Version: render.ResourceVersion, Kind: "BasicAuth"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if role.Namespace != "default" || len(role.Name) == 0 {
+		t.Errorf("unexpected role name %s/%s", role.Namespace, role.Name)
+	}
+
+	resources := map[string]bool{}
+	for _, rule := range role.Rules {
+		for _, res := range rule.Resources {
+			resources[res] = true
+		}
+	}
+	for _, res := range []string{"pets", "pets/status", "basicauths"} {
+		if !resources[res] {
+			t.Errorf("expected role to grant access to %s, got %v", res, role.Rules)
+		}
+	}
+	if resources["secrets"] {
+		t.Errorf("expected no access to secrets, got %v", role.Rules)
+	}
+
+	provider := render.ProviderRole(petDefinition, []schema.GroupVersionKind{
+		{Group: "petstore.swagger.io", Version: render.ResourceVersion, Kind: "BasicAuth"},
+	})
+	if provider.Labels[deployment.LabelAggregateToProvider] != "true" {
+		t.Errorf("expected the provider role to be aggregated, got labels %v", provider.Labels)
+	}
+	if len(provider.Rules) != 2 {
+		t.Fatalf("expected access to the generated kinds only, got %v", provider.Rules)
+	}
+	if pets := provider.Rules[0]; pets.Resources[0] != "pets" || !slices.Contains(pets.Verbs, "delete") {
+		t.Errorf("expected the provider to drain the resources, got %v", pets)
+	}
+	if auth := provider.Rules[1]; auth.APIGroups[0] != "petstore.swagger.io" || auth.Resources[0] != "basicauths" || slices.Contains(auth.Verbs, "delete") {
+		t.Errorf("expected read access to the authentication resources, got %v", auth)
+	}
+
+	cr := petDefinition.DeepCopy()
+	cr.Spec.Resource.ConnectionDetails = []definitionv1alpha1.ConnectionDetail{{Key: "token", Pointer: "/token"}}
+	role, err = render.Role(cr, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last := role.Rules[len(role.Rules)-1]
+	if len(last.Resources) != 1 || last.Resources[0] != "secrets" || len(last.ResourceNames) > 0 || !slices.Equal(last.Verbs, []string{"create"}) {
+		t.Errorf("expected the creation of the connection secrets only, got %v", role.Rules)
+	}
+}
+
+func TestArtifacts(t *testing.T) {
+	contents, err := os.ReadFile("../generator/tests/oas/petstore_auth.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	doc, err := oas.Parse(contents)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	cr := petDefinition.DeepCopy()
+	cr.Spec.Resource.VerbsDescription = []definitionv1alpha1.VerbsDescription{
+		{Action: "create", Method: "POST", Path: "/pet"},
+		{Action: "get", Method: "GET", Path: "/pet/{petId}"},
+	}
+	cr.Spec.Resource.Identifiers = []string{"id"}
+	schemas, _, err := render.GenerateSchemas(doc, cr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gen := schemas[0].Generator
+
+	cm, err := render.Artifacts(cr, schemas)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cm.Name != "def-pet-artifacts" || cm.Namespace != "default" {
+		t.Errorf("unexpected configmap name %s/%s", cm.Namespace, cm.Name)
+	}
+	if !deployment.HasOwnerLabels(cm, types.NamespacedName{Namespace: "default", Name: "def-pet"}) {
+		t.Errorf("expected owner labels, got %v", cm.Labels)
+	}
+	for _, key := range []string{render.SchemaKey("PetSpec"), render.SchemaKey("PetStatus"), render.TypeScriptKey} {
+		if len(cm.Data[key]) == 0 {
+			t.Errorf("expected key %s, got %v", key, cm.Data)
+		}
+	}
+	for _, name := range []string{"PetSpec", "PetStatus"} {
+		if !strings.Contains(cm.Data[render.TypeScriptKey], "export interface "+name+" {") {
+			t.Errorf("expected interface %s in:\n%s", name, cm.Data[render.TypeScriptKey])
+		}
+	}
+	for name := range gen.AuthSchemas() {
+		if len(cm.Data[render.SchemaKey(render.AuthGVK(cr, name).Kind+"Spec")]) == 0 {
+			t.Errorf("expected schema of %s, got %v", name, cm.Data)
+		}
+	}
+}
+
+func TestOperations(t *testing.T) {
+	doc, err := oas.Load("../generator/tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to load document: %v", err)
+	}
+
+	cr := petDefinition.DeepCopy()
+	cr.Spec.Resource.VerbsDescription = []definitionv1alpha1.VerbsDescription{
+		{Action: "create", Method: "POST", Path: "/pet"},
+		{Action: "get", Method: "GET", Path: "/pet/{petId}"},
+	}
+	cm, err := render.Operations(doc, cr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cm.Name != "def-pet-operations" || cm.Namespace != "default" {
+		t.Errorf("unexpected configmap name %s/%s", cm.Namespace, cm.Name)
+	}
+	if !deployment.HasOwnerLabels(cm, types.NamespacedName{Namespace: "default", Name: "def-pet"}) {
+		t.Errorf("expected owner labels, got %v", cm.Labels)
+	}
+
+	m := operations.Map{}
+	if err := json.Unmarshal([]byte(cm.Data[deployment.OperationsKey]), &m); err != nil {
+		t.Fatalf("expected a JSON operation map, got %v", err)
+	}
+	if m.Version != operations.Version || len(m.Resources) != 1 || len(m.Resources[0].Operations) != 2 {
+		t.Errorf("unexpected operation map %+v", m)
+	}
+}
+
+func TestMultipleResources(t *testing.T) {
+	contents, err := os.ReadFile("../generator/tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	doc, err := oas.Parse(contents)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	cr := petDefinition.DeepCopy()
+	cr.Spec.Resource.VerbsDescription = []definitionv1alpha1.VerbsDescription{
+		{Action: "create", Method: "POST", Path: "/pet"},
+		{Action: "get", Method: "GET", Path: "/pet/{petId}"},
+	}
+	cr.Spec.Resources = []definitionv1alpha1.Resource{{
+		Kind: "user",
+		VerbsDescription: []definitionv1alpha1.VerbsDescription{
+			{Action: "create", Method: "POST", Path: "/user"},
+			{Action: "get", Method: "GET", Path: "/user/{username}"},
+		},
+	}}
+
+	schemas, _, err := render.GenerateSchemas(doc, cr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schemas) != 2 || schemas[0].Resource.Kind != "pet" || schemas[1].Resource.Kind != "user" {
+		t.Fatalf("expected the schemas of pet and user, got %v", schemas)
+	}
+	if render.Digest(schemas[:1]) != schemas[0].Generator.Digest() {
+		t.Errorf("expected the digest of a single resource to be the one of its generator")
+	}
+	if d := render.Digest(schemas); d == schemas[0].Generator.Digest() || d == schemas[1].Generator.Digest() {
+		t.Errorf("expected a combined digest, got %s", d)
+	}
+
+	cm, err := render.Artifacts(cr, schemas)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"PetSpec", "UserSpec"} {
+		if len(cm.Data[render.SchemaKey(name)]) == 0 {
+			t.Errorf("expected schema of %s, got %v", name, cm.Data)
+		}
+	}
+
+	role, err := render.Role(cr, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resources := map[string]bool{}
+	for _, rule := range role.Rules {
+		for _, res := range rule.Resources {
+			resources[res] = true
+		}
+	}
+	for _, res := range []string{"pets", "users"} {
+		if !resources[res] {
+			t.Errorf("expected role to grant access to %s, got %v", res, role.Rules)
+		}
+	}
+}
+
+func TestRecordedGVKs(t *testing.T) {
+	cr := petDefinition.DeepCopy()
+	cr.Status.Resources = []definitionv1alpha1.ResourceStatus{
+		{APIVersion: "petstore.swagger.io/v1alpha1", Kind: "Pet"},
+		{APIVersion: "petstore.swagger.io/v1alpha1", Kind: "User"},
+	}
+
+	got := render.RecordedGVKs(cr)
+	if len(got) != 2 || got[0].Kind != "Pet" || got[1].Kind != "User" {
+		t.Fatalf("expected the kinds of the spec followed by the removed ones, got %v", got)
+	}
+}
@@ -0,0 +1,119 @@
+// Package scaffold builds RestDefinitions from the resources inferred by lint
+// from the paths of an OAS document.
+package scaffold
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/lint"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// actionOrder is the order of the scaffolded verbsDescription.
+var actionOrder = []string{"create", "get", "update", "delete", "findby"}
+
+// Options of RestDefinitions.
+type Options struct {
+	// OASPath of the scaffolded RestDefinitions.
+	OASPath string
+	// ResourceGroup of the scaffolded RestDefinitions.
+	ResourceGroup string
+	// Namespace of the scaffolded RestDefinitions.
+	Namespace string
+	// Kinds to scaffold. All the compatible resources when empty.
+	Kinds []string
+}
+
+// Resource returns the verbsDescription and identifiers inferred for kind.
+// Returns an error when no compatible resource of that kind is found.
+func Resource(doc *libopenapi.DocumentModel[v3.Document], kind string) (definitionv1alpha1.Resource, error) {
+	return find(lint.Lint(doc).Resources, kind)
+}
+
+// RestDefinitions returns a RestDefinition for each compatible resource of doc,
+// or for each of opts.Kinds. The skipped resources are described by the returned errors.
+func RestDefinitions(doc *libopenapi.DocumentModel[v3.Document], opts Options) ([]definitionv1alpha1.RestDefinition, []error) {
+	res := []definitionv1alpha1.RestDefinition{}
+	errs := []error{}
+
+	candidates := lint.Lint(doc).Resources
+	if len(opts.Kinds) > 0 {
+		for _, kind := range opts.Kinds {
+			resource, err := find(candidates, kind)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			res = append(res, restDefinition(resource, opts))
+		}
+		return res, errs
+	}
+
+	for _, candidate := range candidates {
+		if !candidate.Compatible {
+			errs = append(errs, fmt.Errorf("resource %s skipped: %s", candidate.Kind, errorMessages(candidate)))
+			continue
+		}
+		res = append(res, restDefinition(toResource(candidate), opts))
+	}
+	return res, errs
+}
+
+func find(candidates []lint.Resource, kind string) (definitionv1alpha1.Resource, error) {
+	for _, res := range candidates {
+		if !strings.EqualFold(res.Kind, kind) {
+			continue
+		}
+		if !res.Compatible {
+			return definitionv1alpha1.Resource{}, fmt.Errorf("resource %s is not compatible: %s", kind, errorMessages(res))
+		}
+		return toResource(res), nil
+	}
+	return definitionv1alpha1.Resource{}, fmt.Errorf("no resource %s found in the OAS document", kind)
+}
+
+func toResource(res lint.Resource) definitionv1alpha1.Resource {
+	verbs := slices.Clone(res.VerbsDescription)
+	slices.SortStableFunc(verbs, func(a, b definitionv1alpha1.VerbsDescription) int {
+		return slices.Index(actionOrder, a.Action) - slices.Index(actionOrder, b.Action)
+	})
+	return definitionv1alpha1.Resource{
+		Kind:             res.Kind,
+		VerbsDescription: verbs,
+		Identifiers:      res.Identifiers,
+	}
+}
+
+func restDefinition(resource definitionv1alpha1.Resource, opts Options) definitionv1alpha1.RestDefinition {
+	return definitionv1alpha1.RestDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: definitionv1alpha1.SchemeGroupVersion.String(),
+			Kind:       definitionv1alpha1.RestDefinitionKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("def-%s", flect.Dasherize(resource.Kind)),
+			Namespace: opts.Namespace,
+		},
+		Spec: definitionv1alpha1.RestDefinitionSpec{
+			OASPath:       opts.OASPath,
+			ResourceGroup: opts.ResourceGroup,
+			Resource:      resource,
+		},
+	}
+}
+
+func errorMessages(res lint.Resource) string {
+	msgs := []string{}
+	for _, is := range res.Issues {
+		if is.Severity == lint.SeverityError {
+			msgs = append(msgs, is.Message)
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
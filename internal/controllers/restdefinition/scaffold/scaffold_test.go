@@ -0,0 +1,82 @@
+package scaffold_test
+
+import (
+	"os"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/scaffold"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+)
+
+func TestRestDefinitions(t *testing.T) {
+	contents, err := os.ReadFile("../generator/tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	doc, err := oas.Parse(contents)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	defs, errs := scaffold.RestDefinitions(doc, scaffold.Options{
+		OASPath:       "https://example.com/petstore.yaml",
+		ResourceGroup: "petstore.swagger.io",
+		Namespace:     "demo",
+	})
+	if len(errs) == 0 {
+		t.Errorf("expected the incompatible resources to be reported")
+	}
+
+	byKind := map[string]definitionv1alpha1.RestDefinition{}
+	for _, def := range defs {
+		byKind[def.Spec.Resource.Kind] = def
+		if errs := validation.ValidateRestDefinition(doc, &def); len(errs) > 0 {
+			t.Errorf("expected %s to be valid, got %v", def.Name, errs)
+		}
+	}
+
+	pet, ok := byKind["Pet"]
+	if !ok {
+		t.Fatalf("expected a Pet RestDefinition, got %v", byKind)
+	}
+	if pet.Name != "def-pet" || pet.Namespace != "demo" || pet.Spec.OASPath != "https://example.com/petstore.yaml" {
+		t.Errorf("unexpected metadata: %s/%s %s", pet.Namespace, pet.Name, pet.Spec.OASPath)
+	}
+	expected := []string{"create", "get", "update", "delete"}
+	if len(pet.Spec.Resource.VerbsDescription) != len(expected) {
+		t.Fatalf("unexpected verbs: %v", pet.Spec.Resource.VerbsDescription)
+	}
+	for i, action := range expected {
+		if pet.Spec.Resource.VerbsDescription[i].Action != action {
+			t.Errorf("expected action %s at index %d, got %v", action, i, pet.Spec.Resource.VerbsDescription)
+		}
+	}
+}
+
+func TestResource(t *testing.T) {
+	contents, err := os.ReadFile("../generator/tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	doc, err := oas.Parse(contents)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	res, err := scaffold.Resource(doc, "user")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.VerbsDescription) != 4 || len(res.Identifiers) == 0 {
+		t.Errorf("unexpected resource: %+v", res)
+	}
+
+	if _, err := scaffold.Resource(doc, "Inventory"); err == nil {
+		t.Errorf("expected an error for an incompatible resource")
+	}
+	if _, err := scaffold.Resource(doc, "Unknown"); err == nil {
+		t.Errorf("expected an error for an unknown resource")
+	}
+}
@@ -0,0 +1,129 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// asyncActions are the actions that may be answered with '202 Accepted'.
+var asyncActions = []string{"create", "update", "delete"}
+
+// ValidateAsync checks the async descriptor of verb against its operation op: op
+// must document a 202 response, declaring the status header if any, and the state
+// pointer must exist in the response of the status endpoint. Errors are rooted at fldPath.
+func ValidateAsync(doc *libopenapi.DocumentModel[v3.Document], verb definitionv1alpha1.VerbsDescription, op *v3.Operation, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	a := verb.Async
+	if a == nil {
+		return allErrs
+	}
+	if !slices.Contains(asyncActions, strings.ToLower(verb.Action)) {
+		return append(allErrs, field.Forbidden(fldPath,
+			fmt.Sprintf("async is supported by the %s actions only", strings.Join(asyncActions, ", "))))
+	}
+
+	endpoint := fmt.Sprintf("%s %s", strings.ToUpper(verb.Method), verb.Path)
+	var accepted *v3.Response
+	if op.Responses != nil && op.Responses.Codes != nil {
+		accepted = op.Responses.Codes.Value("202")
+	}
+	if accepted == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, verb.Action,
+			fmt.Sprintf("no 202 response defined for %s", endpoint)))
+	}
+
+	if len(a.SucceededValues) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("succeededValues"), "at least a terminal state is required"))
+	}
+	for i, v := range a.FailedValues {
+		if slices.Contains(a.SucceededValues, v) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("failedValues").Index(i), v))
+		}
+	}
+	pointerOk := strings.HasPrefix(a.StatePointer, "/")
+	if !pointerOk {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("statePointer"), a.StatePointer,
+			"JSON pointer must start with '/'"))
+	}
+
+	switch {
+	case len(a.StatusPath) > 0 && len(a.StatusHeader) > 0:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("statusHeader"), "statusPath and statusHeader are mutually exclusive"))
+	case len(a.StatusHeader) > 0:
+		if accepted != nil && !hasHeader(accepted, a.StatusHeader) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("statusHeader"), a.StatusHeader,
+				fmt.Sprintf("header not declared by the 202 response of %s", endpoint)))
+		}
+	case len(a.StatusPath) > 0:
+		status := definitionv1alpha1.VerbsDescription{Method: "GET", Path: a.StatusPath}
+		statusOp, ferr := LookupOperation(doc, status, fldPath)
+		if ferr != nil {
+			ferr.Field = fldPath.Child("statusPath").String()
+			return append(allErrs, ferr)
+		}
+		if pointerOk {
+			allErrs = append(allErrs, validateState(a, statusOp, "GET "+a.StatusPath, fldPath)...)
+		}
+	default:
+		allErrs = append(allErrs, field.Required(fldPath.Child("statusPath"), "either statusPath or statusHeader is required"))
+	}
+
+	return allErrs
+}
+
+// validateState checks that the state pointer of a exists in the response of the
+// status endpoint op and, when the state is an enum, that the terminal states are
+// values of the enum.
+func validateState(a *definitionv1alpha1.Async, op *v3.Operation, endpoint string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	schema, ok := ResponseSchema(op)
+	if !ok {
+		return append(allErrs, field.Invalid(fldPath.Child("statusPath"), a.StatusPath,
+			fmt.Sprintf("no JSON response schema defined for %s", endpoint)))
+	}
+	state, err := SchemaAt(schema, a.StatePointer)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath.Child("statePointer"), a.StatePointer,
+			fmt.Sprintf("%s in the response of %s", err, endpoint)))
+	}
+	if len(state.Enum) == 0 {
+		return allErrs
+	}
+
+	enum := make([]string, 0, len(state.Enum))
+	for _, n := range state.Enum {
+		if n != nil {
+			enum = append(enum, n.Value)
+		}
+	}
+	for i, v := range a.SucceededValues {
+		if !slices.Contains(enum, v) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("succeededValues").Index(i), v, enum))
+		}
+	}
+	for i, v := range a.FailedValues {
+		if !slices.Contains(enum, v) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("failedValues").Index(i), v, enum))
+		}
+	}
+	return allErrs
+}
+
+// hasHeader returns true if res declares the header name. Header names are case insensitive.
+func hasHeader(res *v3.Response, name string) bool {
+	if res.Headers == nil {
+		return false
+	}
+	for el := res.Headers.First(); el != nil; el = el.Next() {
+		if strings.EqualFold(el.Key(), name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const asyncOAS = `openapi: 3.0.3
+info:
+  title: async
+  version: 1.0.0
+paths:
+  /clusters:
+    post:
+      responses:
+        "202":
+          description: accepted
+          headers:
+            Location:
+              schema:
+                type: string
+  /clusters/{name}:
+    get:
+      responses:
+        "200":
+          description: ok
+    delete:
+      responses:
+        "204":
+          description: deleted
+  /operations/{id}:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  status:
+                    type: string
+                    enum: [Running, Succeeded, Failed]
+`
+
+func TestValidateAsync(t *testing.T) {
+	doc, err := oas.Parse([]byte(asyncOAS))
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	create := definitionv1alpha1.VerbsDescription{Action: "create", Method: "POST", Path: "/clusters"}
+	testCases := []struct {
+		name     string
+		verb     definitionv1alpha1.VerbsDescription
+		async    definitionv1alpha1.Async
+		expected []string
+	}{
+		{
+			name: "Status path",
+			verb: create,
+			async: definitionv1alpha1.Async{
+				StatusPath: "/operations/{id}", StatePointer: "/status",
+				SucceededValues: []string{"Succeeded"}, FailedValues: []string{"Failed"},
+			},
+		},
+		{
+			name:  "Status header",
+			verb:  create,
+			async: definitionv1alpha1.Async{StatusHeader: "location", StatePointer: "/status", SucceededValues: []string{"Succeeded"}},
+		},
+		{
+			name:     "Not an async action",
+			verb:     definitionv1alpha1.VerbsDescription{Action: "get", Method: "GET", Path: "/clusters/{name}"},
+			async:    definitionv1alpha1.Async{StatusHeader: "Location", StatePointer: "/status", SucceededValues: []string{"Succeeded"}},
+			expected: []string{"async: Forbidden: async is supported by the create, update, delete actions only"},
+		},
+		{
+			name:     "No 202 response",
+			verb:     definitionv1alpha1.VerbsDescription{Action: "delete", Method: "DELETE", Path: "/clusters/{name}"},
+			async:    definitionv1alpha1.Async{StatusPath: "/operations/{id}", StatePointer: "/status", SucceededValues: []string{"Succeeded"}},
+			expected: []string{`async: Invalid value: "delete": no 202 response defined for DELETE /clusters/{name}`},
+		},
+		{
+			name:     "Undeclared header",
+			verb:     create,
+			async:    definitionv1alpha1.Async{StatusHeader: "Operation-Location", StatePointer: "/status", SucceededValues: []string{"Succeeded"}},
+			expected: []string{`async.statusHeader: Invalid value: "Operation-Location": header not declared by the 202 response of POST /clusters`},
+		},
+		{
+			name:     "Unknown status path",
+			verb:     create,
+			async:    definitionv1alpha1.Async{StatusPath: "/ops/{id}", StatePointer: "/status", SucceededValues: []string{"Succeeded"}},
+			expected: []string{`async.statusPath: Not found: "/ops/{id}"`},
+		},
+		{
+			name:     "Unknown state",
+			verb:     create,
+			async:    definitionv1alpha1.Async{StatusPath: "/operations/{id}", StatePointer: "/state", SucceededValues: []string{"Succeeded"}},
+			expected: []string{`async.statePointer: Invalid value: "/state": property "state" not found in the response of GET /operations/{id}`},
+		},
+		{
+			name: "Terminal states not in enum",
+			verb: create,
+			async: definitionv1alpha1.Async{
+				StatusPath: "/operations/{id}", StatePointer: "/status",
+				SucceededValues: []string{"Succeeded", "Done"}, FailedValues: []string{"Succeeded"},
+			},
+			expected: []string{
+				`async.failedValues[0]: Duplicate value: "Succeeded"`,
+				`async.succeededValues[1]: Unsupported value: "Done": supported values: "Running", "Succeeded", "Failed"`,
+			},
+		},
+		{
+			name:  "Missing status endpoint",
+			verb:  create,
+			async: definitionv1alpha1.Async{StatePointer: "/status", SucceededValues: []string{"Succeeded"}},
+			expected: []string{
+				"async.statusPath: Required value: either statusPath or statusHeader is required",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verb := tc.verb
+			verb.Async = &tc.async
+			op, ferr := validation.LookupOperation(doc, verb, field.NewPath("verb"))
+			if ferr != nil {
+				t.Fatalf("unexpected error: %v", ferr)
+			}
+			errs := validation.ValidateAsync(doc, verb, op, field.NewPath("async"))
+			got := []string{}
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// IsSensitive returns true if schema describes a value that must not be written to
+// the status: a password or a write only property.
+func IsSensitive(schema *base.Schema) bool {
+	return schema != nil && (schema.Format == "password" || (schema.WriteOnly != nil && *schema.WriteOnly))
+}
+
+// ValidateConnectionDetails checks that the connection details of res have unique and
+// valid Secret keys, and that their pointers exist in the response of its create
+// action. Errors are rooted at fldPath, the path of res.
+func ValidateConnectionDetails(doc *libopenapi.DocumentModel[v3.Document], res definitionv1alpha1.Resource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(res.ConnectionDetails) == 0 {
+		return allErrs
+	}
+	detailsPath := fldPath.Child("connectionDetails")
+
+	keys := map[string]bool{}
+	for i, detail := range res.ConnectionDetails {
+		keyPath := detailsPath.Index(i).Child("key")
+		for _, msg := range utilvalidation.IsConfigMapKey(detail.Key) {
+			allErrs = append(allErrs, field.Invalid(keyPath, detail.Key, msg))
+		}
+		if keys[detail.Key] {
+			allErrs = append(allErrs, field.Duplicate(keyPath, detail.Key))
+		}
+		keys[detail.Key] = true
+	}
+
+	j := -1
+	for i, verb := range res.VerbsDescription {
+		if strings.EqualFold(verb.Action, "create") {
+			j = i
+			break
+		}
+	}
+	if j < 0 {
+		return append(allErrs, field.Required(fldPath.Child("verbsDescription"),
+			"a create action is required to write connection details"))
+	}
+	create := res.VerbsDescription[j]
+	op, ferr := LookupOperation(doc, create, fldPath.Child("verbsDescription").Index(j))
+	if ferr != nil {
+		// Reported by ValidateResource.
+		return allErrs
+	}
+	endpoint := fmt.Sprintf("%s %s", strings.ToUpper(create.Method), create.Path)
+	schema, ok := ResponseSchema(op)
+	if !ok {
+		return append(allErrs, field.Invalid(detailsPath, len(res.ConnectionDetails),
+			fmt.Sprintf("no JSON response schema defined for %s", endpoint)))
+	}
+	root, err := SchemaAt(schema, create.ResponsePointer)
+	if err != nil {
+		// Reported by ValidateResponsePointer.
+		return allErrs
+	}
+
+	for i, detail := range res.ConnectionDetails {
+		pointerPath := detailsPath.Index(i).Child("pointer")
+		if !strings.HasPrefix(detail.Pointer, "/") {
+			allErrs = append(allErrs, field.Invalid(pointerPath, detail.Pointer, "JSON pointer must start with '/'"))
+			continue
+		}
+		if _, err := SchemaAt(root, detail.Pointer); err != nil {
+			allErrs = append(allErrs, field.Invalid(pointerPath, detail.Pointer,
+				fmt.Sprintf("%s in the response of %s", err, endpoint)))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,114 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const tokensOAS = `openapi: 3.0.3
+info:
+  title: tokens
+  version: 1.0.0
+paths:
+  /tokens:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: object
+                    properties:
+                      id:
+                        type: string
+                      token:
+                        type: string
+                        format: password
+                      secret:
+                        type: string
+                        writeOnly: true
+`
+
+func TestValidateConnectionDetails(t *testing.T) {
+	doc, err := oas.Parse([]byte(tokensOAS))
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	res := definitionv1alpha1.Resource{
+		Kind: "Token",
+		VerbsDescription: []definitionv1alpha1.VerbsDescription{
+			{Action: "create", Method: "POST", Path: "/tokens", ResponsePointer: "/data"},
+		},
+		ConnectionDetails: []definitionv1alpha1.ConnectionDetail{
+			{Key: "token", Pointer: "/token"},
+			{Key: "webhook.secret", Pointer: "/secret"},
+		},
+	}
+	if errs := validation.ValidateConnectionDetails(doc, res, field.NewPath("spec", "resource")); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	res.ConnectionDetails = []definitionv1alpha1.ConnectionDetail{
+		{Key: "token", Pointer: "/token"},
+		{Key: "token", Pointer: "/password"},
+		{Key: "api key", Pointer: "token"},
+	}
+	got := []string{}
+	for _, e := range validation.ValidateConnectionDetails(doc, res, field.NewPath("spec", "resource")) {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		`spec.resource.connectionDetails[1].key: Duplicate value: "token"`,
+		`spec.resource.connectionDetails[2].key: Invalid value: "api key": a valid config key must consist of alphanumeric characters, '-', '_' or '.' (e.g. 'key.name',  or 'KEY_NAME',  or 'key-name', regex used for validation is '[-._a-zA-Z0-9]+')`,
+		`spec.resource.connectionDetails[1].pointer: Invalid value: "/password": property "password" not found in the response of POST /tokens`,
+		`spec.resource.connectionDetails[2].pointer: Invalid value: "token": JSON pointer must start with '/'`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	res.VerbsDescription[0].Action = "get"
+	errs := validation.ValidateConnectionDetails(doc, res, field.NewPath("spec", "resource"))
+	if len(errs) == 0 || !strings.Contains(errs[len(errs)-1].Error(), "a create action is required") {
+		t.Errorf("expected a missing create action, got %v", errs)
+	}
+}
+
+func TestIsSensitive(t *testing.T) {
+	doc, err := oas.Parse([]byte(tokensOAS))
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+	op, ferr := validation.LookupOperation(doc, definitionv1alpha1.VerbsDescription{Method: "POST", Path: "/tokens"}, field.NewPath("verb"))
+	if ferr != nil {
+		t.Fatalf("unexpected error: %v", ferr)
+	}
+	schema, _ := validation.ResponseSchema(op)
+	for pointer, expected := range map[string]bool{"/data/id": false, "/data/token": true, "/data/secret": true} {
+		sch, err := validation.SchemaAt(schema, pointer)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := validation.IsSensitive(sch); got != expected {
+			t.Errorf("IsSensitive(%s) = %v, want %v", pointer, got, expected)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package validation
+
+import (
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const errExtendedArgs = "requires the dynamic controller extended arguments, enable them with the --dynamic-controller-extended-args flag of the provider"
+
+// ValidateControllerArgs checks that the dynamic controller of spec, a RestDefinition
+// in namespace, can be deployed. Unless extendedArgs is true, the dynamic controller
+// image accepts a single -resource and a single -namespace argument: several
+// resources, a controller pool and a number of watched namespaces other than one
+// are refused.
+func ValidateControllerArgs(spec *definitionv1alpha1.RestDefinitionSpec, namespace string, extendedArgs bool) field.ErrorList {
+	if extendedArgs {
+		return nil
+	}
+	specPath := field.NewPath("spec")
+
+	allErrs := field.ErrorList{}
+	if len(spec.AllResources()) > 1 {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("resources"), "serving several resources "+errExtendedArgs))
+	}
+	if len(spec.ControllerPool) > 0 {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("controllerPool"), "a controller pool "+errExtendedArgs))
+	}
+	if len(deployment.WatchNamespaces(spec, namespace)) != 1 {
+		if spec.Scope == definitionv1alpha1.ScopeCluster {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("scope"), "watching all namespaces "+errExtendedArgs))
+		} else {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("watchNamespaces"), "watching all or several namespaces "+errExtendedArgs))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,71 @@
+package validation_test
+
+import (
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateControllerArgs(t *testing.T) {
+	pet := definitionv1alpha1.Resource{Kind: "Pet"}
+	user := definitionv1alpha1.Resource{Kind: "User"}
+
+	tests := []struct {
+		name   string
+		spec   definitionv1alpha1.RestDefinitionSpec
+		fields []string
+	}{
+		{
+			name: "single resource in the namespace of the definition",
+			spec: definitionv1alpha1.RestDefinitionSpec{Resource: pet},
+		},
+		{
+			name: "single watched namespace",
+			spec: definitionv1alpha1.RestDefinitionSpec{Resource: pet, WatchNamespaces: []string{"other", "other"}},
+		},
+		{
+			name:   "several resources",
+			spec:   definitionv1alpha1.RestDefinitionSpec{Resources: []definitionv1alpha1.Resource{pet, user}},
+			fields: []string{"spec.resources"},
+		},
+		{
+			name:   "controller pool",
+			spec:   definitionv1alpha1.RestDefinitionSpec{Resource: pet, ControllerPool: "shared"},
+			fields: []string{"spec.controllerPool"},
+		},
+		{
+			name:   "several watched namespaces",
+			spec:   definitionv1alpha1.RestDefinitionSpec{Resource: pet, WatchNamespaces: []string{"ns-1", "ns-2"}},
+			fields: []string{"spec.watchNamespaces"},
+		},
+		{
+			name:   "all namespaces",
+			spec:   definitionv1alpha1.RestDefinitionSpec{Resource: pet, WatchNamespaces: []string{definitionv1alpha1.AllNamespaces}},
+			fields: []string{"spec.watchNamespaces"},
+		},
+		{
+			name:   "cluster scope",
+			spec:   definitionv1alpha1.RestDefinitionSpec{Resource: pet, Scope: definitionv1alpha1.ScopeCluster},
+			fields: []string{"spec.scope"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validation.ValidateControllerArgs(&tt.spec, "default", false)
+			if len(errs) != len(tt.fields) {
+				t.Fatalf("expected errors on %v, got %v", tt.fields, errs)
+			}
+			for i, err := range errs {
+				if err.Type != field.ErrorTypeForbidden || err.Field != tt.fields[i] {
+					t.Errorf("expected forbidden %s, got %v", tt.fields[i], err)
+				}
+			}
+
+			if errs := validation.ValidateControllerArgs(&tt.spec, "default", true); len(errs) > 0 {
+				t.Errorf("expected no error with the extended arguments, got %v", errs)
+			}
+		})
+	}
+}
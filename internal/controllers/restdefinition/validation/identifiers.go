@@ -0,0 +1,209 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var aliasRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Identifier is an entry of the identifiers of a resource, written '[<field>=]<path>'.
+// The path is a property name (i.e. 'id'), a JSON pointer (i.e. '/metadata/uid') or a
+// JSONPath (i.e. '$.metadata.uid') in the resource returned by the get action.
+type Identifier struct {
+	// Field is the name of the status field holding the identifier: the alias, or the
+	// last property of the path.
+	Field string
+	// Pointer is the path as a JSON pointer.
+	Pointer string
+}
+
+// ParseIdentifier parses an entry of the identifiers of a resource.
+func ParseIdentifier(s string) (Identifier, error) {
+	res := Identifier{}
+	path := s
+	if alias, rest, ok := strings.Cut(s, "="); ok && aliasRegexp.MatchString(alias) {
+		res.Field, path = alias, rest
+	}
+
+	tokens := []string{}
+	switch {
+	case len(path) == 0:
+		return res, fmt.Errorf("path must not be empty")
+	case strings.HasPrefix(path, "/"):
+		for _, token := range strings.Split(path[1:], "/") {
+			tokens = append(tokens, strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~"))
+		}
+	case strings.HasPrefix(path, "$"):
+		var err error
+		tokens, err = parseJSONPath(path)
+		if err != nil {
+			return res, err
+		}
+	default:
+		tokens = []string{path}
+	}
+
+	res.Pointer = pointer(tokens)
+	if len(res.Field) == 0 {
+		for i := len(tokens) - 1; i >= 0 && len(res.Field) == 0; i-- {
+			if _, err := strconv.Atoi(tokens[i]); err != nil {
+				res.Field = tokens[i]
+			}
+		}
+	}
+	if len(res.Field) == 0 {
+		return res, fmt.Errorf("a field name is required, i.e. 'id=%s'", path)
+	}
+	return res, nil
+}
+
+// parseJSONPath returns the tokens of a JSONPath made of child selectors only
+// (i.e. "$.items[0]['name']").
+func parseJSONPath(path string) ([]string, error) {
+	unsupported := fmt.Errorf("unsupported JSONPath %q: only child selectors are supported", path)
+	tokens := []string{}
+	rest := path[1:]
+	for len(rest) > 0 {
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			return nil, unsupported
+		case rest[0] == '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if len(name) == 0 || name == "*" {
+				return nil, unsupported
+			}
+			tokens = append(tokens, name)
+			rest = rest[end:]
+		case rest[0] == '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: missing ']'", path)
+			}
+			sel := rest[1:end]
+			if len(sel) > 1 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0] {
+				tokens = append(tokens, sel[1:len(sel)-1])
+			} else if _, err := strconv.Atoi(sel); err == nil {
+				tokens = append(tokens, sel)
+			} else {
+				return nil, unsupported
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q", path)
+		}
+	}
+	return tokens, nil
+}
+
+func pointer(tokens []string) string {
+	b := strings.Builder{}
+	for _, token := range tokens {
+		b.WriteString("/")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// ResolvedIdentifier is an identifier with the schema of its value, nil when unknown.
+type ResolvedIdentifier struct {
+	Identifier
+	Schema *base.Schema
+}
+
+// ResolveIdentifiers parses the identifiers of res and resolves them in the resource
+// returned by its get action. The schemas are nil when the get action or its JSON
+// response schema are missing. Errors are rooted at fldPath, the path of res.
+func ResolveIdentifiers(doc *libopenapi.DocumentModel[v3.Document], res definitionv1alpha1.Resource, fldPath *field.Path) ([]ResolvedIdentifier, field.ErrorList) {
+	allErrs := field.ErrorList{}
+	ids := make([]ResolvedIdentifier, 0, len(res.Identifiers))
+	fields := map[string]bool{}
+	for i, s := range res.Identifiers {
+		id, err := ParseIdentifier(s)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("identifiers").Index(i), s, err.Error()))
+			continue
+		}
+		if fields[id.Field] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("identifiers").Index(i), id.Field))
+			continue
+		}
+		fields[id.Field] = true
+		ids = append(ids, ResolvedIdentifier{Identifier: id})
+	}
+	if len(allErrs) > 0 {
+		return nil, allErrs
+	}
+
+	j := -1
+	for i, verb := range res.VerbsDescription {
+		if strings.EqualFold(verb.Action, "get") {
+			j = i
+			break
+		}
+	}
+	if j < 0 || len(ids) == 0 {
+		return ids, allErrs
+	}
+	get := res.VerbsDescription[j]
+	op, ferr := LookupOperation(doc, get, fldPath.Child("verbsDescription").Index(j))
+	if ferr != nil {
+		// Reported by ValidateResource.
+		return ids, allErrs
+	}
+	schema, ok := ResponseSchema(op)
+	if !ok {
+		return ids, allErrs
+	}
+	root, err := SchemaAt(schema, get.ResponsePointer)
+	if err != nil {
+		// Reported by ValidateResponsePointer.
+		return ids, allErrs
+	}
+
+	for i := range ids {
+		ids[i].Schema, err = SchemaAt(root, ids[i].Pointer)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("identifiers").Index(i), res.Identifiers[i],
+				fmt.Sprintf("identifier not found in the response of %s %s", strings.ToUpper(get.Method), get.Path)))
+		case IsType(ids[i].Schema, "object") || IsType(ids[i].Schema, "array"):
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("identifiers").Index(i), res.Identifiers[i],
+				fmt.Sprintf("identifier must be a string, a number or a boolean in the response of %s %s", strings.ToUpper(get.Method), get.Path)))
+		}
+	}
+	return ids, allErrs
+}
+
+// ValidateResponsePointer checks that the response pointer of verb exists in the
+// schema of the response of its operation op. Errors are rooted at fldPath.
+func ValidateResponsePointer(verb definitionv1alpha1.VerbsDescription, op *v3.Operation, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(verb.ResponsePointer) == 0 {
+		return allErrs
+	}
+	endpoint := fmt.Sprintf("%s %s", strings.ToUpper(verb.Method), verb.Path)
+	schema, ok := ResponseSchema(op)
+	if !ok {
+		return append(allErrs, field.Invalid(fldPath, verb.ResponsePointer,
+			fmt.Sprintf("no JSON response schema defined for %s", endpoint)))
+	}
+	if _, err := SchemaAt(schema, verb.ResponsePointer); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, verb.ResponsePointer,
+			fmt.Sprintf("%s in the response of %s", err, endpoint)))
+	}
+	return allErrs
+}
@@ -0,0 +1,122 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestParseIdentifier(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected validation.Identifier
+		err      string
+	}{
+		{in: "id", expected: validation.Identifier{Field: "id", Pointer: "/id"}},
+		{in: "/metadata/uid", expected: validation.Identifier{Field: "uid", Pointer: "/metadata/uid"}},
+		{in: "$.metadata.uid", expected: validation.Identifier{Field: "uid", Pointer: "/metadata/uid"}},
+		{in: "$['meta.data'].tags[0]", expected: validation.Identifier{Field: "tags", Pointer: "/meta.data/tags/0"}},
+		{in: "clusterId=$.metadata.uid", expected: validation.Identifier{Field: "clusterId", Pointer: "/metadata/uid"}},
+		{in: "/a~1b", expected: validation.Identifier{Field: "a/b", Pointer: "/a~1b"}},
+		{in: "$..uid", err: "unsupported JSONPath"},
+		{in: "$.items[*]", err: "unsupported JSONPath"},
+		{in: "/0", err: "a field name is required"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := validation.ParseIdentifier(tc.in)
+			if len(tc.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, got)
+			}
+		})
+	}
+}
+
+const envelopeOAS = `openapi: 3.0.3
+info:
+  title: envelope
+  version: 1.0.0
+paths:
+  /clusters/{name}:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: object
+                    properties:
+                      name:
+                        type: string
+                      metadata:
+                        type: object
+                        properties:
+                          uid:
+                            type: string
+                          generation:
+                            type: integer
+`
+
+func TestResolveIdentifiers(t *testing.T) {
+	doc, err := oas.Parse([]byte(envelopeOAS))
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	res := definitionv1alpha1.Resource{
+		Kind:        "Cluster",
+		Identifiers: []string{"name", "clusterId=$.metadata.uid", "/metadata/generation"},
+		VerbsDescription: []definitionv1alpha1.VerbsDescription{
+			{Action: "get", Method: "GET", Path: "/clusters/{name}", ResponsePointer: "/data"},
+		},
+	}
+	ids, errs := validation.ResolveIdentifiers(doc, res, field.NewPath("spec", "resource"))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	expected := map[string]string{"name": "string", "clusterId": "string", "generation": "integer"}
+	for _, id := range ids {
+		if id.Schema == nil || !validation.IsType(id.Schema, expected[id.Field]) {
+			t.Errorf("expected %s to be a %s, got %+v", id.Field, expected[id.Field], id.Schema)
+		}
+	}
+
+	res.Identifiers = []string{"$.metadata.id", "metadata"}
+	_, errs = validation.ResolveIdentifiers(doc, res, field.NewPath("spec", "resource"))
+	got := []string{}
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		`spec.resource.identifiers[0]: Invalid value: "$.metadata.id": identifier not found in the response of GET /clusters/{name}`,
+		`spec.resource.identifiers[1]: Invalid value: "metadata": identifier must be a string, a number or a boolean in the response of GET /clusters/{name}`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	res.VerbsDescription[0].ResponsePointer = "/payload"
+	op, _ := validation.LookupOperation(doc, res.VerbsDescription[0], field.NewPath("verb"))
+	errs = validation.ValidateResponsePointer(res.VerbsDescription[0], op, field.NewPath("responsePointer"))
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `property "payload" not found`) {
+		t.Errorf("expected an unknown response pointer, got %v", errs)
+	}
+}
@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidatePagination checks the pagination of verb against its operation op: only
+// findby actions are paged, the parameters must be query parameters of op and the
+// pointers must exist in the schema of its response. Errors are rooted at fldPath.
+func ValidatePagination(doc *libopenapi.DocumentModel[v3.Document], verb definitionv1alpha1.VerbsDescription, op *v3.Operation, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	p := verb.Pagination
+	if p == nil {
+		return allErrs
+	}
+	if !strings.EqualFold(verb.Action, "findby") {
+		return append(allErrs, field.Forbidden(fldPath, "pagination is supported by the findby action only"))
+	}
+
+	endpoint := fmt.Sprintf("%s %s", strings.ToUpper(verb.Method), verb.Path)
+	query := queryParameters(doc, verb.Path, op)
+
+	switch p.Strategy {
+	case definitionv1alpha1.PaginationPageNumber, definitionv1alpha1.PaginationCursor:
+		if len(p.PageParameter) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("pageParameter"),
+				fmt.Sprintf("required by the %s strategy", p.Strategy)))
+		} else if !query[p.PageParameter] {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("pageParameter"), p.PageParameter,
+				fmt.Sprintf("not a query parameter of %s", endpoint)))
+		}
+	case definitionv1alpha1.PaginationLinkHeader:
+		if len(p.PageParameter) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("pageParameter"),
+				"the next page is read from the Link header"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("strategy"), p.Strategy, []string{
+			definitionv1alpha1.PaginationPageNumber, definitionv1alpha1.PaginationCursor, definitionv1alpha1.PaginationLinkHeader,
+		}))
+	}
+
+	if len(p.SizeParameter) > 0 && !query[p.SizeParameter] {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("sizeParameter"), p.SizeParameter,
+			fmt.Sprintf("not a query parameter of %s", endpoint)))
+	}
+	if p.PageSize != nil && len(p.SizeParameter) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("sizeParameter"), "required to set the pageSize"))
+	}
+
+	if p.Strategy == definitionv1alpha1.PaginationCursor && len(p.NextCursorPointer) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("nextCursorPointer"), "required by the Cursor strategy"))
+	}
+	if p.Strategy != definitionv1alpha1.PaginationCursor && len(p.NextCursorPointer) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("nextCursorPointer"),
+			fmt.Sprintf("not used by the %s strategy", p.Strategy)))
+	}
+
+	schema, ok := ResponseSchema(op)
+	if !ok {
+		return append(allErrs, field.Invalid(fldPath, p.Strategy,
+			fmt.Sprintf("no JSON response schema defined for %s", endpoint)))
+	}
+
+	items, err := SchemaAt(schema, p.ItemsPointer)
+	switch {
+	case err != nil:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("itemsPointer"), p.ItemsPointer,
+			fmt.Sprintf("%s in the response of %s", err, endpoint)))
+	case !IsType(items, "array"):
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("itemsPointer"), p.ItemsPointer,
+			fmt.Sprintf("not an array in the response of %s", endpoint)))
+	}
+
+	if len(p.NextCursorPointer) > 0 && p.Strategy == definitionv1alpha1.PaginationCursor {
+		cursor, err := SchemaAt(schema, p.NextCursorPointer)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nextCursorPointer"), p.NextCursorPointer,
+				fmt.Sprintf("%s in the response of %s", err, endpoint)))
+		case !IsType(cursor, "string") && !IsType(cursor, "integer"):
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nextCursorPointer"), p.NextCursorPointer,
+				fmt.Sprintf("not a string or an integer in the response of %s", endpoint)))
+		}
+	}
+
+	return allErrs
+}
+
+// queryParameters returns the names of the query parameters of op and of its path.
+func queryParameters(doc *libopenapi.DocumentModel[v3.Document], path string, op *v3.Operation) map[string]bool {
+	res := map[string]bool{}
+	params := op.Parameters
+	if item := doc.Model.Paths.PathItems.Value(path); item != nil {
+		params = slices.Concat(params, item.Parameters)
+	}
+	for _, p := range params {
+		if p != nil && p.In == "query" {
+			res[p.Name] = true
+		}
+	}
+	return res
+}
@@ -0,0 +1,145 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+const paginationOAS = `openapi: 3.0.3
+info:
+  title: pagination
+  version: 1.0.0
+paths:
+  /items:
+    parameters:
+    - name: per_page
+      in: query
+      schema:
+        type: integer
+    get:
+      parameters:
+      - name: page
+        in: query
+        schema:
+          type: integer
+      - name: cursor
+        in: query
+        schema:
+          type: string
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data:
+                    type: array
+                    items:
+                      type: object
+                      properties:
+                        id:
+                          type: string
+                  meta:
+                    type: object
+                    properties:
+                      next_cursor:
+                        type: string
+`
+
+func TestValidatePagination(t *testing.T) {
+	doc, err := oas.Parse([]byte(paginationOAS))
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		action     string
+		pagination definitionv1alpha1.Pagination
+		expected   []string
+	}{
+		{
+			name:   "Page number",
+			action: "findby",
+			pagination: definitionv1alpha1.Pagination{
+				Strategy: "PageNumber", PageParameter: "page", SizeParameter: "per_page",
+				PageSize: ptr.To(50), ItemsPointer: "/data", MaxPages: ptr.To(10),
+			},
+		},
+		{
+			name:   "Cursor",
+			action: "findby",
+			pagination: definitionv1alpha1.Pagination{
+				Strategy: "Cursor", PageParameter: "cursor", ItemsPointer: "/data", NextCursorPointer: "/meta/next_cursor",
+			},
+		},
+		{
+			name:       "Link header",
+			action:     "findby",
+			pagination: definitionv1alpha1.Pagination{Strategy: "LinkHeader", ItemsPointer: "/data"},
+		},
+		{
+			name:       "Not a findby action",
+			action:     "get",
+			pagination: definitionv1alpha1.Pagination{Strategy: "LinkHeader", ItemsPointer: "/data"},
+			expected:   []string{"pagination: Forbidden: pagination is supported by the findby action only"},
+		},
+		{
+			name:   "Unknown parameters",
+			action: "findby",
+			pagination: definitionv1alpha1.Pagination{
+				Strategy: "PageNumber", PageParameter: "p", SizeParameter: "size", ItemsPointer: "/data",
+			},
+			expected: []string{
+				`pagination.pageParameter: Invalid value: "p": not a query parameter of GET /items`,
+				`pagination.sizeParameter: Invalid value: "size": not a query parameter of GET /items`,
+			},
+		},
+		{
+			name:   "Missing cursor",
+			action: "findby",
+			pagination: definitionv1alpha1.Pagination{
+				Strategy: "Cursor", PageParameter: "cursor", ItemsPointer: "/data", NextCursorPointer: "/meta/cursor",
+			},
+			expected: []string{`pagination.nextCursorPointer: Invalid value: "/meta/cursor": property "cursor" not found in the response of GET /items`},
+		},
+		{
+			name:       "Items not an array",
+			action:     "findby",
+			pagination: definitionv1alpha1.Pagination{Strategy: "LinkHeader", ItemsPointer: "/meta"},
+			expected:   []string{`pagination.itemsPointer: Invalid value: "/meta": not an array in the response of GET /items`},
+		},
+		{
+			name:       "Items of the body",
+			action:     "findby",
+			pagination: definitionv1alpha1.Pagination{Strategy: "LinkHeader"},
+			expected:   []string{`pagination.itemsPointer: Invalid value: "": not an array in the response of GET /items`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verb := definitionv1alpha1.VerbsDescription{Action: tc.action, Method: "GET", Path: "/items", Pagination: &tc.pagination}
+			op, ferr := validation.LookupOperation(doc, verb, field.NewPath("verb"))
+			if ferr != nil {
+				t.Fatalf("unexpected error: %v", ferr)
+			}
+			errs := validation.ValidatePagination(doc, verb, op, field.NewPath("pagination"))
+			got := []string{}
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
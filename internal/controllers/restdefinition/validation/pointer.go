@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+)
+
+// SchemaAt returns the schema of the value found at the JSON pointer (RFC 6901)
+// in a document described by schema. The empty pointer refers to the document.
+func SchemaAt(schema *base.Schema, pointer string) (*base.Schema, error) {
+	if len(pointer) == 0 {
+		return schema, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("JSON pointer must start with '/'")
+	}
+
+	cur := schema
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		next, err := schemaChild(cur, token)
+		if err != nil {
+			return nil, err
+		}
+		cur = next
+	}
+	return cur, nil
+}
+
+func schemaChild(schema *base.Schema, token string) (*base.Schema, error) {
+	if IsType(schema, "array") {
+		if _, err := strconv.Atoi(token); err != nil {
+			return nil, fmt.Errorf("%q is not an index of an array", token)
+		}
+		if schema.Items == nil || !schema.Items.IsA() || schema.Items.A == nil {
+			return nil, fmt.Errorf("items of the array at %q have no schema", token)
+		}
+		return schema.Items.A.BuildSchema()
+	}
+
+	props := map[string]*base.SchemaProxy{}
+	collectProperties(schema, props)
+	prop, ok := props[token]
+	if !ok {
+		return nil, fmt.Errorf("property %q not found", token)
+	}
+	return prop.BuildSchema()
+}
+
+// IsType returns true if schema declares the JSON type typ.
+func IsType(schema *base.Schema, typ string) bool {
+	return schema != nil && slices.Contains(schema.Type, typ)
+}
@@ -0,0 +1,143 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ServerURL returns the base URL of the requests sent for verb, whose operation is op.
+// The server is chosen by sel (nil selects the first one) among the servers of op, of
+// its path or of the document, the most specific ones winning, and its variables
+// are replaced with the values of sel or with their default. Returns an empty URL
+// when no server is declared. Errors are rooted at selPath.
+func ServerURL(doc *libopenapi.DocumentModel[v3.Document], verb definitionv1alpha1.VerbsDescription, op *v3.Operation, sel *definitionv1alpha1.Server, selPath *field.Path) (string, *field.Error) {
+	if sel == nil {
+		sel = &definitionv1alpha1.Server{}
+	}
+	if len(sel.URL) > 0 {
+		if ferr := validateURL(sel.URL, selPath.Child("url")); ferr != nil {
+			return "", ferr
+		}
+		return sel.URL, nil
+	}
+
+	servers, where := doc.Model.Servers, "the document"
+	if doc.Model.Paths != nil && doc.Model.Paths.PathItems != nil {
+		if item := doc.Model.Paths.PathItems.Value(verb.Path); item != nil && len(item.Servers) > 0 {
+			servers, where = item.Servers, fmt.Sprintf("path %s", verb.Path)
+		}
+	}
+	if op != nil && len(op.Servers) > 0 {
+		servers, where = op.Servers, fmt.Sprintf("operation %s %s", strings.ToUpper(verb.Method), verb.Path)
+	}
+	if len(servers) == 0 {
+		return "", nil
+	}
+
+	var server *v3.Server
+	switch {
+	case len(sel.Description) > 0:
+		for _, el := range servers {
+			if el != nil && el.Description == sel.Description {
+				server = el
+				break
+			}
+		}
+		if server == nil {
+			return "", field.Invalid(selPath.Child("description"), sel.Description,
+				fmt.Sprintf("no server with this description in %s", where))
+		}
+	case sel.Index != nil:
+		if *sel.Index < 0 || *sel.Index >= len(servers) {
+			return "", field.Invalid(selPath.Child("index"), *sel.Index,
+				fmt.Sprintf("%d server(s) defined in %s", len(servers), where))
+		}
+		server = servers[*sel.Index]
+	default:
+		server = servers[0]
+	}
+	if server == nil {
+		return "", nil
+	}
+
+	// Variables not declared by the server would be silently ignored
+	names := make([]string, 0, len(sel.Variables))
+	for name := range sel.Variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if server.Variables == nil || server.Variables.Value(name) == nil {
+			return "", field.NotFound(selPath.Child("variables").Key(name), name)
+		}
+	}
+
+	res := server.URL
+	if server.Variables == nil {
+		return res, nil
+	}
+	for el := server.Variables.First(); el != nil; el = el.Next() {
+		name, variable := el.Key(), el.Value()
+		value, ok := sel.Variables[name]
+		if !ok {
+			value = variable.Default
+		}
+		if ok && len(variable.Enum) > 0 && !slices.Contains(variable.Enum, value) {
+			return "", field.NotSupported(selPath.Child("variables").Key(name), value, variable.Enum)
+		}
+		res = strings.ReplaceAll(res, "{"+name+"}", value)
+	}
+	return res, nil
+}
+
+// ValidateServer checks that spec.server selects a server for every verb of the
+// resources of spec.
+func ValidateServer(doc *libopenapi.DocumentModel[v3.Document], spec *definitionv1alpha1.RestDefinitionSpec, selPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	sel := spec.Server
+	if sel == nil {
+		return allErrs
+	}
+	if len(sel.URL) > 0 {
+		if ferr := validateURL(sel.URL, selPath.Child("url")); ferr != nil {
+			allErrs = append(allErrs, ferr)
+		}
+		return allErrs
+	}
+
+	seen := map[string]bool{}
+	for _, res := range spec.AllResources() {
+		for _, verb := range res.VerbsDescription {
+			op, ferr := LookupOperation(doc, verb, field.NewPath("verbsDescription"))
+			if ferr != nil {
+				// Reported by ValidateResource.
+				continue
+			}
+			if _, ferr := ServerURL(doc, verb, op, sel, selPath); ferr != nil && !seen[ferr.Error()] {
+				seen[ferr.Error()] = true
+				allErrs = append(allErrs, ferr)
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateURL checks that u, replacing the servers of the document, is an absolute
+// http(s) URL.
+func validateURL(u string, fldPath *field.Path) *field.Error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return field.Invalid(fldPath, u, err.Error())
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" || len(parsed.Host) == 0 {
+		return field.Invalid(fldPath, u, "must be an absolute http or https URL")
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package validation_test
+
+import (
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const serversOAS = `openapi: 3.0.3
+info:
+  title: servers
+  version: 1.0.0
+servers:
+- url: https://{region}.example.com/{basePath}
+  description: prod
+  variables:
+    region:
+      default: eu
+      enum: [eu, us]
+    basePath:
+      default: v1
+- url: https://staging.example.com/v1
+  description: staging
+paths:
+  /items:
+    get:
+      responses:
+        "200":
+          description: ok
+    post:
+      servers:
+      - url: https://upload.example.com
+      responses:
+        "201":
+          description: created
+  /legacy:
+    servers:
+    - url: https://legacy.example.com
+    get:
+      responses:
+        "200":
+          description: ok
+`
+
+func TestServerURL(t *testing.T) {
+	doc, err := oas.Parse([]byte(serversOAS))
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+	one := 1
+	five := 5
+
+	testCases := []struct {
+		name     string
+		verb     definitionv1alpha1.VerbsDescription
+		sel      *definitionv1alpha1.Server
+		expected string
+		err      string
+	}{
+		{
+			name:     "Defaults",
+			verb:     definitionv1alpha1.VerbsDescription{Method: "GET", Path: "/items"},
+			expected: "https://eu.example.com/v1",
+		},
+		{
+			name:     "Variables",
+			verb:     definitionv1alpha1.VerbsDescription{Method: "GET", Path: "/items"},
+			sel:      &definitionv1alpha1.Server{Variables: map[string]string{"region": "us", "basePath": "v2"}},
+			expected: "https://us.example.com/v2",
+		},
+		{
+			name: "Variable not in enum",
+			verb: definitionv1alpha1.VerbsDescription{Method: "GET", Path: "/items"},
+			sel:  &definitionv1alpha1.Server{Variables: map[string]string{"region": "ap"}},
+			err:  `spec.server.variables[region]: Unsupported value: "ap": supported values: "eu", "us"`,
+		},
+		{
+			name: "Unknown variable",
+			verb: definitionv1alpha1.VerbsDescription{Method: "GET", Path: "/items"},
+			sel:  &definitionv1alpha1.Server{Variables: map[string]string{"region": "us", "zone": "a"}},
+			err:  `spec.server.variables[zone]: Not found: "zone"`,
+		},
+		{
+			name: "Variable of a server without variables",
+			verb: definitionv1alpha1.VerbsDescription{Method: "GET", Path: "/legacy"},
+			sel:  &definitionv1alpha1.Server{Variables: map[string]string{"region": "us"}},
+			err:  `spec.server.variables[region]: Not found: "region"`,
+		},
+		{
+			name:     "Index",
+			verb:     definitionv1alpha1.VerbsDescription{Method: "GET", Path: "/items"},
+			sel:      &definitionv1alpha1.Server{Index: &one},
+			expected: "https://staging.example.com/v1",
+		},
+		{
+			name: "Index out of range",
+			verb: definitionv1alpha1.VerbsDescription{Method: "GET", Path: "/items"},
+			sel:  &definitionv1alpha1.Server{Index: &five},
+			err:  "spec.server.index: Invalid value: 5: 2 server(s) defined in the document",
+		},
+		{
+			name:     "Description",
+			verb:     definitionv1alpha1.VerbsDescription{Method: "GET", Path: "/items"},
+			sel:      &definitionv1alpha1.Server{Description: "staging"},
+			expected: "https://staging.example.com/v1",
+		},
+		{
+			name:     "Operation servers",
+			verb:     definitionv1alpha1.VerbsDescription{Method: "POST", Path: "/items"},
+			expected: "https://upload.example.com",
+		},
+		{
+			name:     "Path servers",
+			verb:     definitionv1alpha1.VerbsDescription{Method: "GET", Path: "/legacy"},
+			expected: "https://legacy.example.com",
+		},
+		{
+			name: "Description not in path servers",
+			verb: definitionv1alpha1.VerbsDescription{Method: "GET", Path: "/legacy"},
+			sel:  &definitionv1alpha1.Server{Description: "staging"},
+			err:  `spec.server.description: Invalid value: "staging": no server with this description in path /legacy`,
+		},
+		{
+			name:     "URL override",
+			verb:     definitionv1alpha1.VerbsDescription{Method: "POST", Path: "/items"},
+			sel:      &definitionv1alpha1.Server{URL: "http://localhost:8080", Index: &five},
+			expected: "http://localhost:8080",
+		},
+		{
+			name: "Relative URL override",
+			verb: definitionv1alpha1.VerbsDescription{Method: "GET", Path: "/items"},
+			sel:  &definitionv1alpha1.Server{URL: "/api/v3"},
+			err:  `spec.server.url: Invalid value: "/api/v3": must be an absolute http or https URL`,
+		},
+		{
+			name: "URL override with another scheme",
+			verb: definitionv1alpha1.VerbsDescription{Method: "GET", Path: "/items"},
+			sel:  &definitionv1alpha1.Server{URL: "ftp://example.com"},
+			err:  `spec.server.url: Invalid value: "ftp://example.com": must be an absolute http or https URL`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			op, ferr := validation.LookupOperation(doc, tc.verb, field.NewPath("verb"))
+			if ferr != nil {
+				t.Fatalf("unexpected error: %v", ferr)
+			}
+			got, ferr := validation.ServerURL(doc, tc.verb, op, tc.sel, field.NewPath("spec", "server"))
+			if len(tc.err) > 0 {
+				if ferr == nil || ferr.Error() != tc.err {
+					t.Errorf("expected error %q, got %v", tc.err, ferr)
+				}
+				return
+			}
+			if ferr != nil {
+				t.Fatalf("unexpected error: %v", ferr)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var kindRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*$`)
+
+// ValidateKind checks that kind can be used as the Kind of a generated CRD.
+func ValidateKind(kind string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(kind) == 0 {
+		return append(allErrs, field.Required(fldPath, "kind must be specified"))
+	}
+	if !kindRegexp.MatchString(kind) {
+		return append(allErrs, field.Invalid(fldPath, kind, "kind must start with a letter and contain only alphanumeric characters"))
+	}
+
+	plural := strings.ToLower(flect.Pluralize(kind))
+	for _, msg := range utilvalidation.IsDNS1035Label(plural) {
+		allErrs = append(allErrs, field.Invalid(fldPath, kind, fmt.Sprintf("plural resource name %q is invalid: %s", plural, msg)))
+	}
+
+	return allErrs
+}
+
+// ValidateResource checks the resource against the OAS document: every verb must
+// reference an existing path and method, actions must be unique and identifiers
+// must be found in the resource returned by the 'get' action.
+func ValidateResource(doc *libopenapi.DocumentModel[v3.Document], res definitionv1alpha1.Resource, fldPath *field.Path) field.ErrorList {
+	allErrs := ValidateKind(res.Kind, fldPath.Child("kind"))
+
+	actions := map[string]int{}
+	for i, verb := range res.VerbsDescription {
+		verbPath := fldPath.Child("verbsDescription").Index(i)
+
+		if j, ok := actions[strings.ToLower(verb.Action)]; ok {
+			allErrs = append(allErrs, field.Duplicate(verbPath.Child("action"),
+				fmt.Sprintf("%s (already defined at index %d)", verb.Action, j)))
+		} else {
+			actions[strings.ToLower(verb.Action)] = i
+		}
+
+		op, err := LookupOperation(doc, verb, verbPath)
+		if err != nil {
+			allErrs = append(allErrs, err)
+			continue
+		}
+		allErrs = append(allErrs, ValidateResponsePointer(verb, op, verbPath.Child("responsePointer"))...)
+		allErrs = append(allErrs, ValidatePagination(doc, verb, op, verbPath.Child("pagination"))...)
+		allErrs = append(allErrs, ValidateAsync(doc, verb, op, verbPath.Child("async"))...)
+	}
+
+	_, errs := ResolveIdentifiers(doc, res, fldPath)
+	allErrs = append(allErrs, errs...)
+	allErrs = append(allErrs, ValidateConnectionDetails(doc, res, fldPath)...)
+
+	return allErrs
+}
+
+// LookupOperation returns the operation referenced by verb. Errors are rooted at verbPath.
+func LookupOperation(doc *libopenapi.DocumentModel[v3.Document], verb definitionv1alpha1.VerbsDescription, verbPath *field.Path) (*v3.Operation, *field.Error) {
+	if doc.Model.Paths == nil || doc.Model.Paths.PathItems == nil {
+		return nil, field.NotFound(verbPath.Child("path"), verb.Path)
+	}
+	path := doc.Model.Paths.PathItems.Value(verb.Path)
+	if path == nil {
+		return nil, field.NotFound(verbPath.Child("path"), verb.Path)
+	}
+
+	ops := path.GetOperations()
+	if ops == nil || ops.Len() == 0 {
+		return nil, field.Invalid(verbPath.Child("method"), verb.Method,
+			fmt.Sprintf("no operations defined for path %s", verb.Path))
+	}
+	op := ops.Value(strings.ToLower(verb.Method))
+	if op == nil {
+		available := []string{}
+		for el := ops.First(); el != nil; el = el.Next() {
+			available = append(available, strings.ToUpper(el.Key()))
+		}
+		return nil, field.Invalid(verbPath.Child("method"), verb.Method,
+			fmt.Sprintf("method not defined for path %s (available: %s)", verb.Path, strings.Join(available, ", ")))
+	}
+
+	return op, nil
+}
+
+// ResponseSchema returns the schema of the first successful JSON response of op.
+// The boolean is false when no such response schema exists.
+func ResponseSchema(op *v3.Operation) (*base.Schema, bool) {
+	if op.Responses == nil || op.Responses.Codes == nil {
+		return nil, false
+	}
+
+	for el := op.Responses.Codes.First(); el != nil; el = el.Next() {
+		if !strings.HasPrefix(el.Key(), "2") || el.Value() == nil || el.Value().Content == nil {
+			continue
+		}
+		media := el.Value().Content.Value("application/json")
+		if media == nil || media.Schema == nil {
+			continue
+		}
+		schema, err := media.Schema.BuildSchema()
+		if err != nil || schema == nil {
+			continue
+		}
+		return schema, true
+	}
+
+	return nil, false
+}
+
+// ResponseProperties returns the top level properties of the first successful
+// JSON response of op. The boolean is false when no such response schema exists.
+func ResponseProperties(op *v3.Operation) (map[string]*base.SchemaProxy, bool) {
+	schema, ok := ResponseSchema(op)
+	if !ok {
+		return nil, false
+	}
+	props := map[string]*base.SchemaProxy{}
+	collectProperties(schema, props)
+	return props, true
+}
+
+func collectProperties(schema *base.Schema, props map[string]*base.SchemaProxy) {
+	if schema.Properties != nil {
+		for prop := schema.Properties.First(); prop != nil; prop = prop.Next() {
+			props[prop.Key()] = prop.Value()
+		}
+	}
+	for _, proxy := range schema.AllOf {
+		sch, err := proxy.BuildSchema()
+		if err != nil || sch == nil {
+			continue
+		}
+		collectProperties(sch, props)
+	}
+}
+
+// ValidateRestDefinition checks the spec of cr against the OAS document.
+func ValidateRestDefinition(doc *libopenapi.DocumentModel[v3.Document], cr *definitionv1alpha1.RestDefinition) field.ErrorList {
+	specPath := field.NewPath("spec")
+
+	allErrs := field.ErrorList{}
+	for _, msg := range utilvalidation.IsDNS1123Subdomain(cr.Spec.ResourceGroup) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("resourceGroup"), cr.Spec.ResourceGroup, msg))
+	}
+
+	if len(cr.Spec.Resource.Kind) > 0 || len(cr.Spec.Resources) == 0 {
+		allErrs = append(allErrs, ValidateResource(doc, cr.Spec.Resource, specPath.Child("resource"))...)
+	}
+
+	kinds := map[string]bool{strings.ToLower(cr.Spec.Resource.Kind): len(cr.Spec.Resource.Kind) > 0}
+	for i, res := range cr.Spec.Resources {
+		resPath := specPath.Child("resources").Index(i)
+		if kinds[strings.ToLower(res.Kind)] {
+			allErrs = append(allErrs, field.Duplicate(resPath.Child("kind"), res.Kind))
+		}
+		kinds[strings.ToLower(res.Kind)] = true
+
+		allErrs = append(allErrs, ValidateResource(doc, res, resPath)...)
+	}
+
+	allErrs = append(allErrs, ValidateServer(doc, &cr.Spec, specPath.Child("server"))...)
+
+	return allErrs
+}
@@ -0,0 +1,169 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateResource(t *testing.T) {
+	doc, err := oas.Load("../generator/tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to load document: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		resource definitionv1alpha1.Resource
+		expected []string
+	}{
+		{
+			name: "Valid resource",
+			resource: definitionv1alpha1.Resource{
+				Kind:        "Pet",
+				Identifiers: []string{"id", "name"},
+				VerbsDescription: []definitionv1alpha1.VerbsDescription{
+					{Action: "create", Method: "POST", Path: "/pet"},
+					{Action: "get", Method: "GET", Path: "/pet/{petId}"},
+					{Action: "update", Method: "PUT", Path: "/pet"},
+					{Action: "delete", Method: "DELETE", Path: "/pet/{petId}"},
+				},
+			},
+		},
+		{
+			name: "Unknown path",
+			resource: definitionv1alpha1.Resource{
+				Kind: "Pet",
+				VerbsDescription: []definitionv1alpha1.VerbsDescription{
+					{Action: "create", Method: "POST", Path: "/pets"},
+				},
+			},
+			expected: []string{`spec.resource.verbsDescription[0].path: Not found: "/pets"`},
+		},
+		{
+			name: "Unknown method",
+			resource: definitionv1alpha1.Resource{
+				Kind: "Pet",
+				VerbsDescription: []definitionv1alpha1.VerbsDescription{
+					{Action: "create", Method: "PATCH", Path: "/pet"},
+				},
+			},
+			expected: []string{`spec.resource.verbsDescription[0].method: Invalid value: "PATCH": method not defined for path /pet (available: PUT, POST)`},
+		},
+		{
+			name: "Duplicated action",
+			resource: definitionv1alpha1.Resource{
+				Kind: "Pet",
+				VerbsDescription: []definitionv1alpha1.VerbsDescription{
+					{Action: "update", Method: "POST", Path: "/pet"},
+					{Action: "update", Method: "PUT", Path: "/pet"},
+				},
+			},
+			expected: []string{`spec.resource.verbsDescription[1].action: Duplicate value: "update (already defined at index 0)"`},
+		},
+		{
+			name: "Unknown identifier",
+			resource: definitionv1alpha1.Resource{
+				Kind:        "Pet",
+				Identifiers: []string{"id", "uuid"},
+				VerbsDescription: []definitionv1alpha1.VerbsDescription{
+					{Action: "get", Method: "GET", Path: "/pet/{petId}"},
+				},
+			},
+			expected: []string{`spec.resource.identifiers[1]: Invalid value: "uuid": identifier not found in the response of GET /pet/{petId}`},
+		},
+		{
+			name: "Invalid kind",
+			resource: definitionv1alpha1.Resource{
+				Kind: "my-pet",
+			},
+			expected: []string{`spec.resource.kind: Invalid value: "my-pet": kind must start with a letter and contain only alphanumeric characters`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validation.ValidateResource(doc, tc.resource, field.NewPath("spec", "resource"))
+			if len(errs) != len(tc.expected) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tc.expected), len(errs), errs)
+			}
+			for i := range errs {
+				if !strings.EqualFold(errs[i].Error(), tc.expected[i]) {
+					t.Errorf("expected error %q, got %q", tc.expected[i], errs[i].Error())
+				}
+			}
+		})
+	}
+}
+
+func TestValidateKind(t *testing.T) {
+	testCases := []struct {
+		kind  string
+		valid bool
+	}{
+		{kind: "Pet", valid: true},
+		{kind: "pipelinePermission", valid: true},
+		{kind: "", valid: false},
+		{kind: "1Pet", valid: false},
+		{kind: "Pet_Store", valid: false},
+		{kind: strings.Repeat("A", 70), valid: false},
+	}
+
+	for _, tc := range testCases {
+		errs := validation.ValidateKind(tc.kind, field.NewPath("kind"))
+		if (len(errs) == 0) != tc.valid {
+			t.Errorf("kind %q: expected valid=%v, got errors %v", tc.kind, tc.valid, errs)
+		}
+	}
+}
+
+func TestValidateRestDefinitionResources(t *testing.T) {
+	doc, err := oas.Load("../generator/tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to load document: %v", err)
+	}
+
+	pet := definitionv1alpha1.Resource{
+		Kind: "Pet",
+		VerbsDescription: []definitionv1alpha1.VerbsDescription{
+			{Action: "create", Method: "POST", Path: "/pet"},
+			{Action: "get", Method: "GET", Path: "/pet/{petId}"},
+		},
+	}
+	user := definitionv1alpha1.Resource{
+		Kind: "User",
+		VerbsDescription: []definitionv1alpha1.VerbsDescription{
+			{Action: "create", Method: "POST", Path: "/user"},
+			{Action: "get", Method: "GET", Path: "/users/{username}"},
+		},
+	}
+
+	cr := &definitionv1alpha1.RestDefinition{
+		Spec: definitionv1alpha1.RestDefinitionSpec{
+			ResourceGroup: "petstore.swagger.io",
+			Resources:     []definitionv1alpha1.Resource{pet, user},
+		},
+	}
+	errs := validation.ValidateRestDefinition(doc, cr)
+	if len(errs) != 1 || errs[0].Field != "spec.resources[1].verbsDescription[1].path" {
+		t.Errorf("expected an error on the second resource, got %v", errs)
+	}
+
+	cr.Spec.Resource = pet
+	cr.Spec.Resources = []definitionv1alpha1.Resource{pet}
+	errs = validation.ValidateRestDefinition(doc, cr)
+	if len(errs) != 1 || errs[0].Type != field.ErrorTypeDuplicate || errs[0].Field != "spec.resources[0].kind" {
+		t.Errorf("expected a duplicate kind, got %v", errs)
+	}
+
+	cr.Spec.Resource = definitionv1alpha1.Resource{}
+	cr.Spec.Resources = nil
+	errs = validation.ValidateRestDefinition(doc, cr)
+	if len(errs) != 1 || errs[0].Field != "spec.resource.kind" {
+		t.Errorf("expected a required kind, got %v", errs)
+	}
+}
@@ -8,12 +8,13 @@ import (
 )
 
 // Setup creates all controllers with the supplied logger and adds them to
-// the supplied manager.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
-	for _, setup := range []func(ctrl.Manager, controller.Options) error{
+// the supplied manager. extendedArgs is true when the dynamic controller image
+// accepts one -resource argument per resource and the -operations argument.
+func Setup(mgr ctrl.Manager, o controller.Options, extendedArgs bool) error {
+	for _, setup := range []func(ctrl.Manager, controller.Options, bool) error{
 		repo.Setup,
 	} {
-		if err := setup(mgr, o); err != nil {
+		if err := setup(mgr, o, extendedArgs); err != nil {
 			return err
 		}
 	}
@@ -10,6 +10,7 @@ import (
 func TxtFuncMap() ttemplate.FuncMap {
 	return ttemplate.FuncMap(map[string]any{
 		"quote": quote,
+		"split": split,
 	})
 }
 
@@ -23,6 +24,17 @@ func quote(str ...any) string {
 	return strings.Join(out, " ")
 }
 
+// split returns the non empty elements of the sep separated list s.
+func split(s, sep string) []string {
+	out := []string{}
+	for _, el := range strings.Split(s, sep) {
+		if len(el) > 0 {
+			out = append(out, el)
+		}
+	}
+	return out
+}
+
 func strval(v any) string {
 	switch v := v.(type) {
 	case string:
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	_ "embed"
 	"fmt"
+	"strings"
 	"text/template"
 )
 
@@ -20,9 +21,21 @@ type Renderoptions struct {
 	Name       string
 	Tag        string
 	ClientType string
+	// WatchNamespaces watched by the controller, nil means all namespaces.
+	WatchNamespaces []string
+	// Resources served by the controller, all of Group and Version.
+	// Defaults to Resource.
+	Resources []string
 }
 
 func Values(opts Renderoptions) map[string]string {
+	if len(opts.Resources) == 0 {
+		opts.Resources = []string{opts.Resource}
+	}
+	if len(opts.Resource) == 0 {
+		opts.Resource = opts.Resources[0]
+	}
+
 	if len(opts.Name) == 0 {
 		opts.Name = fmt.Sprintf("%s-controller", opts.Resource)
 	}
@@ -39,6 +52,9 @@ func Values(opts Renderoptions) map[string]string {
 		"namespace":  opts.Namespace,
 		"tag":        opts.Tag,
 		"clientType": opts.ClientType,
+		"resources":  strings.Join(opts.Resources, ","),
+
+		"watchNamespaces": strings.Join(opts.WatchNamespaces, ","),
 	}
 }
 
@@ -3,6 +3,7 @@ package templates
 import (
 	_ "embed"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -21,3 +22,23 @@ func TestDeploymentManifest(t *testing.T) {
 
 	fmt.Println(string(bin))
 }
+
+func TestDeploymentManifestResources(t *testing.T) {
+	values := Values(Renderoptions{
+		Group:     "petstore.swagger.io",
+		Version:   "v1alpha1",
+		Resources: []string{"pets", "users"},
+		Name:      "def-petstore-controller",
+		Namespace: "default",
+	})
+	bin, err := RenderDeployment(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, arg := range []string{"- -resource=pets\n", "- -resource=users\n"} {
+		if !strings.Contains(string(bin), arg) {
+			t.Errorf("expected %q in:\n%s", arg, bin)
+		}
+	}
+}
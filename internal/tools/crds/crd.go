@@ -61,6 +61,33 @@ func InstallCRD(ctx context.Context, kube client.Client, obj *apiextensionsv1.Cu
 	)
 }
 
+// ApplyCRD creates the CRD if it does not exist, otherwise it updates its spec
+// (and labels) in place, preserving the existing custom resources.
+func ApplyCRD(ctx context.Context, kube client.Client, obj *apiextensionsv1.CustomResourceDefinition) error {
+	return retry.Do(
+		func() error {
+			tmp := apiextensionsv1.CustomResourceDefinition{}
+			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					return kube.Create(ctx, obj)
+				}
+
+				return err
+			}
+
+			if len(obj.Labels) > 0 && tmp.Labels == nil {
+				tmp.Labels = map[string]string{}
+			}
+			for k, v := range obj.Labels {
+				tmp.Labels[k] = v
+			}
+			tmp.Spec = obj.Spec
+			return kube.Update(ctx, &tmp)
+		},
+	)
+}
+
 func LookupCRD(ctx context.Context, kube client.Client, gvr schema.GroupVersionResource) (bool, error) {
 	res := apiextensionsv1.CustomResourceDefinition{}
 	err := kube.Get(ctx, client.ObjectKey{Name: gvr.GroupResource().String()}, &res, &client.GetOptions{})
@@ -0,0 +1,161 @@
+package crds
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// DiffCRD returns the changes between the openAPIV3Schema of version in the
+// installed CRD and in the desired one, sorted by path.
+func DiffCRD(installed, desired *apiextensionsv1.CustomResourceDefinition, version string) []definitionv1alpha1.SchemaChange {
+	return DiffSchema(versionSchema(installed, version), versionSchema(desired, version))
+}
+
+// HasBreakingChanges returns true if any of the changes is breaking.
+func HasBreakingChanges(changes []definitionv1alpha1.SchemaChange) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffSchema returns the changes between the old and new schema, sorted by path.
+// A change is breaking when resources valid against the old schema may not be
+// valid against the new one: a field is removed, its type changes, it becomes
+// required or its enum loses values.
+func DiffSchema(old, new *apiextensionsv1.JSONSchemaProps) []definitionv1alpha1.SchemaChange {
+	res := []definitionv1alpha1.SchemaChange{}
+	if old == nil || new == nil {
+		return res
+	}
+	diffSchema("", old, new, &res)
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Path < res[j].Path
+	})
+	return res
+}
+
+func versionSchema(crd *apiextensionsv1.CustomResourceDefinition, version string) *apiextensionsv1.JSONSchemaProps {
+	if crd == nil {
+		return nil
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version && v.Schema != nil {
+			return v.Schema.OpenAPIV3Schema
+		}
+	}
+	return nil
+}
+
+func diffSchema(path string, old, new *apiextensionsv1.JSONSchemaProps, res *[]definitionv1alpha1.SchemaChange) {
+	add := func(p, typ string, breaking bool, msg string) {
+		*res = append(*res, definitionv1alpha1.SchemaChange{Path: p, Type: typ, Breaking: breaking, Message: msg})
+	}
+
+	if len(old.Type) > 0 && old.Type != new.Type {
+		add(pathOrRoot(path), definitionv1alpha1.SchemaChangeTypeChanged, true,
+			fmt.Sprintf("type changed from %q to %q", old.Type, new.Type))
+		return
+	}
+	// An untyped field (x-kubernetes-preserve-unknown-fields, int-or-string) accepts
+	// values the new type may reject.
+	if len(old.Type) == 0 && len(new.Type) > 0 {
+		add(pathOrRoot(path), definitionv1alpha1.SchemaChangeTypeChanged, true,
+			fmt.Sprintf("type set to %q on an untyped field", new.Type))
+		return
+	}
+
+	for _, r := range new.Required {
+		if !slices.Contains(old.Required, r) {
+			add(path+"."+r, definitionv1alpha1.SchemaChangeNewlyRequired, true, "field is now required")
+		}
+	}
+	for _, r := range old.Required {
+		if !slices.Contains(new.Required, r) {
+			add(path+"."+r, definitionv1alpha1.SchemaChangeNoLongerRequired, false, "field is no longer required")
+		}
+	}
+
+	diffEnum(pathOrRoot(path), old.Enum, new.Enum, add)
+
+	for name, oldProp := range old.Properties {
+		newProp, ok := new.Properties[name]
+		if !ok {
+			add(path+"."+name, definitionv1alpha1.SchemaChangeFieldRemoved, true, "field removed")
+			continue
+		}
+		diffSchema(path+"."+name, &oldProp, &newProp, res)
+	}
+	for name := range new.Properties {
+		if _, ok := old.Properties[name]; !ok {
+			add(path+"."+name, definitionv1alpha1.SchemaChangeFieldAdded, false, "field added")
+		}
+	}
+
+	if old.Items != nil && new.Items != nil && old.Items.Schema != nil && new.Items.Schema != nil {
+		diffSchema(path+"[]", old.Items.Schema, new.Items.Schema, res)
+	}
+}
+
+func diffEnum(path string, old, new []apiextensionsv1.JSON, add func(p, typ string, breaking bool, msg string)) {
+	if len(old) == 0 && len(new) == 0 {
+		return
+	}
+	if len(new) == 0 {
+		add(path, definitionv1alpha1.SchemaChangeEnumWidened, false, "enum constraint removed")
+		return
+	}
+	if len(old) == 0 {
+		add(path, definitionv1alpha1.SchemaChangeEnumNarrowed, true,
+			fmt.Sprintf("values restricted to %s", joinEnum(new)))
+		return
+	}
+
+	removed := enumDifference(old, new)
+	if len(removed) > 0 {
+		add(path, definitionv1alpha1.SchemaChangeEnumNarrowed, true,
+			fmt.Sprintf("values removed: %s", joinEnum(removed)))
+	}
+	added := enumDifference(new, old)
+	if len(added) > 0 {
+		add(path, definitionv1alpha1.SchemaChangeEnumWidened, false,
+			fmt.Sprintf("values added: %s", joinEnum(added)))
+	}
+}
+
+// enumDifference returns the values of a that are not in b.
+func enumDifference(a, b []apiextensionsv1.JSON) []apiextensionsv1.JSON {
+	res := []apiextensionsv1.JSON{}
+	for _, x := range a {
+		found := slices.ContainsFunc(b, func(y apiextensionsv1.JSON) bool {
+			return string(x.Raw) == string(y.Raw)
+		})
+		if !found {
+			res = append(res, x)
+		}
+	}
+	return res
+}
+
+func joinEnum(values []apiextensionsv1.JSON) string {
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		res = append(res, string(v.Raw))
+	}
+	return strings.Join(res, ", ")
+}
+
+func pathOrRoot(path string) string {
+	if len(path) == 0 {
+		return "."
+	}
+	return path
+}
@@ -0,0 +1,113 @@
+package crds_test
+
+import (
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/crds"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
+)
+
+func enum(values ...string) []apiextensionsv1.JSON {
+	res := []apiextensionsv1.JSON{}
+	for _, v := range values {
+		res = append(res, apiextensionsv1.JSON{Raw: []byte(`"` + v + `"`)})
+	}
+	return res
+}
+
+func crdWithSpec(spec apiextensionsv1.JSONSchemaProps) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name: "v1alpha1",
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type:       "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{"spec": spec},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestDiffCRD(t *testing.T) {
+	installed := crdWithSpec(apiextensionsv1.JSONSchemaProps{
+		Type:     "object",
+		Required: []string{"name"},
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"name":   {Type: "string"},
+			"age":    {Type: "integer"},
+			"status": {Type: "string", Enum: enum("available", "pending", "sold")},
+			"tags": {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+				Schema: &apiextensionsv1.JSONSchemaProps{Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"id": {Type: "integer"},
+				}},
+			}},
+			"nickname": {Type: "string"},
+			"extra":    {XPreserveUnknownFields: ptr.To(true)},
+		},
+	})
+	desired := crdWithSpec(apiextensionsv1.JSONSchemaProps{
+		Type:     "object",
+		Required: []string{"age"},
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"name":   {Type: "string"},
+			"age":    {Type: "string"},
+			"status": {Type: "string", Enum: enum("available", "sold", "reserved")},
+			"tags": {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+				Schema: &apiextensionsv1.JSONSchemaProps{Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"id":   {Type: "integer"},
+					"name": {Type: "string"},
+				}},
+			}},
+			"color": {Type: "string"},
+			"extra": {Type: "object"},
+		},
+	})
+
+	changes := crds.DiffCRD(installed, desired, "v1alpha1")
+
+	// path/type -> breaking
+	expected := map[string]bool{
+		".spec.age/" + definitionv1alpha1.SchemaChangeTypeChanged:        true,
+		".spec.age/" + definitionv1alpha1.SchemaChangeNewlyRequired:      true,
+		".spec.name/" + definitionv1alpha1.SchemaChangeNoLongerRequired:  false,
+		".spec.nickname/" + definitionv1alpha1.SchemaChangeFieldRemoved:  true,
+		".spec.color/" + definitionv1alpha1.SchemaChangeFieldAdded:       false,
+		".spec.status/" + definitionv1alpha1.SchemaChangeEnumNarrowed:    true,
+		".spec.status/" + definitionv1alpha1.SchemaChangeEnumWidened:     false,
+		".spec.tags[].name/" + definitionv1alpha1.SchemaChangeFieldAdded: false,
+		".spec.extra/" + definitionv1alpha1.SchemaChangeTypeChanged:      true,
+	}
+
+	if len(changes) != len(expected) {
+		t.Errorf("expected %d changes, got %d: %+v", len(expected), len(changes), changes)
+	}
+	for _, c := range changes {
+		breaking, ok := expected[c.Path+"/"+c.Type]
+		if !ok {
+			t.Errorf("unexpected change: %+v", c)
+			continue
+		}
+		if c.Breaking != breaking {
+			t.Errorf("expected breaking=%v for %s %s", breaking, c.Path, c.Type)
+		}
+	}
+	for i := 1; i < len(changes); i++ {
+		if changes[i-1].Path > changes[i].Path {
+			t.Errorf("expected changes sorted by path, got %s before %s", changes[i-1].Path, changes[i].Path)
+		}
+	}
+
+	if !crds.HasBreakingChanges(changes) {
+		t.Errorf("expected breaking changes")
+	}
+	if changes := crds.DiffCRD(installed, installed, "v1alpha1"); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
@@ -0,0 +1,107 @@
+package deployment
+
+import (
+	"context"
+	"maps"
+
+	"github.com/avast/retry-go"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/naming"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/rbactools"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ArtifactsNamespacedName returns the namespaced name of the ConfigMap holding the
+// artifacts of the kinds generated for the RestDefinition nn.
+func ArtifactsNamespacedName(nn types.NamespacedName) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: nn.Namespace,
+		Name:      naming.ArtifactsName(nn.Name),
+	}
+}
+
+// ApplyConfigMap creates the configmap if it does not exist, otherwise it updates
+// its data and labels when they differ from the desired ones.
+// Returns true if the configmap was created or updated.
+func ApplyConfigMap(ctx context.Context, kube client.Client, obj *corev1.ConfigMap) (changed bool, err error) {
+	err = retry.Do(
+		func() error {
+			tmp := corev1.ConfigMap{}
+			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					changed = true
+					return rbactools.CreateOrAdopt(ctx, kube, obj)
+				}
+
+				return err
+			}
+
+			upToDate := maps.Equal(tmp.Data, obj.Data)
+			for k, v := range obj.Labels {
+				if tmp.Labels[k] != v {
+					upToDate = false
+				}
+			}
+			if upToDate {
+				return nil
+			}
+
+			if tmp.Labels == nil {
+				tmp.Labels = map[string]string{}
+			}
+			maps.Copy(tmp.Labels, obj.Labels)
+			tmp.Data = obj.Data
+			changed = true
+			return kube.Update(ctx, &tmp)
+		},
+	)
+	return changed, err
+}
+
+// LookupConfigMap returns true if the configmap nn exists.
+func LookupConfigMap(ctx context.Context, kube client.Client, nn types.NamespacedName) (bool, error) {
+	err := kube.Get(ctx, nn, &corev1.ConfigMap{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// UninstallConfigMap deletes the configmap opts.NamespacedName, if it exists.
+func UninstallConfigMap(ctx context.Context, opts UninstallOptions) error {
+	return retry.Do(
+		func() error {
+			obj := corev1.ConfigMap{}
+			err := opts.KubeClient.Get(ctx, opts.NamespacedName, &obj, &client.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					return nil
+				}
+
+				return err
+			}
+
+			err = opts.KubeClient.Delete(ctx, &obj, &client.DeleteOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					return nil
+				}
+
+				return err
+			}
+
+			if opts.Log != nil {
+				opts.Log("ConfigMap successfully uninstalled",
+					"name", obj.GetName(), "namespace", obj.GetNamespace())
+			}
+
+			return nil
+		},
+	)
+}
@@ -0,0 +1,111 @@
+package deployment_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestApplyConfigMap(t *testing.T) {
+	ctx := context.TODO()
+	kube := fake.NewClientBuilder().Build()
+	nn := types.NamespacedName{Namespace: "demo", Name: "def-pet"}
+
+	obj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.ArtifactsNamespacedName(nn).Name,
+			Namespace: nn.Namespace,
+		},
+		Data: map[string]string{"a": "1"},
+	}
+	deployment.SetOwnerLabels(obj, nn)
+
+	changed, err := deployment.ApplyConfigMap(ctx, kube, obj.DeepCopy())
+	if err != nil || !changed {
+		t.Fatalf("expected configmap to be created, got changed=%v err=%v", changed, err)
+	}
+
+	changed, err = deployment.ApplyConfigMap(ctx, kube, obj.DeepCopy())
+	if err != nil || changed {
+		t.Fatalf("expected no changes, got changed=%v err=%v", changed, err)
+	}
+
+	obj.Data = map[string]string{"a": "2"}
+	changed, err = deployment.ApplyConfigMap(ctx, kube, obj.DeepCopy())
+	if err != nil || !changed {
+		t.Fatalf("expected configmap to be updated, got changed=%v err=%v", changed, err)
+	}
+
+	live := corev1.ConfigMap{}
+	if err := kube.Get(ctx, deployment.ArtifactsNamespacedName(nn), &live); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if live.Data["a"] != "2" || !deployment.HasOwnerLabels(&live, nn) {
+		t.Errorf("unexpected configmap: %+v", live)
+	}
+
+	err = deployment.UninstallConfigMap(ctx, deployment.UninstallOptions{
+		KubeClient:     kube,
+		NamespacedName: deployment.ArtifactsNamespacedName(nn),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found, err := deployment.LookupConfigMap(ctx, kube, deployment.ArtifactsNamespacedName(nn))
+	if err != nil || found {
+		t.Errorf("expected configmap to be uninstalled, got found=%v err=%v", found, err)
+	}
+}
+
+func TestApplyConfigMapAdopts(t *testing.T) {
+	ctx := context.TODO()
+	nn := types.NamespacedName{Namespace: "demo", Name: "def-pet"}
+
+	// The cache of the provider only holds the labeled objects.
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "def-pet-artifacts", Namespace: nn.Namespace},
+		Data:       map[string]string{"key": "stale"},
+	}
+	kube := fake.NewClientBuilder().WithObjects(existing).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if err := c.Get(ctx, key, obj, opts...); err != nil {
+				return err
+			}
+			if _, ok := deployment.OwnerOf(obj); !ok {
+				return apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+			}
+			return nil
+		},
+	}).Build()
+
+	obj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: existing.Name, Namespace: nn.Namespace},
+		Data:       map[string]string{"key": "value"},
+	}
+	deployment.SetOwnerLabels(obj, nn)
+	if _, err := deployment.ApplyConfigMap(ctx, kube, obj.DeepCopy()); err != nil {
+		t.Fatalf("expected the existing configmap to be adopted, got %v", err)
+	}
+
+	// Once labeled, the configmap is found and updated.
+	changed, err := deployment.ApplyConfigMap(ctx, kube, obj.DeepCopy())
+	if err != nil || !changed {
+		t.Fatalf("expected the adopted configmap to be updated, got %v, %v", changed, err)
+	}
+	live := corev1.ConfigMap{}
+	if err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &live); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !deployment.HasOwnerLabels(&live, nn) || live.Data["key"] != "value" {
+		t.Errorf("expected the adopted configmap to be labeled and up to date, got %v %v", live.Labels, live.Data)
+	}
+}
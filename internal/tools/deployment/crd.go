@@ -62,26 +62,69 @@ func InstallCRD(ctx context.Context, kube client.Client, obj *apiextensionsv1.Cu
 	)
 }
 
-func LookupCRD(ctx context.Context, kube client.Client, gvr schema.GroupVersionResource) (bool, error) {
+// LookupCRD reports whether the CRD serving gvr exists with the requested version
+// and whether it is established, i.e. its names are accepted and it is served.
+func LookupCRD(ctx context.Context, kube client.Client, gvr schema.GroupVersionResource) (found bool, established bool, err error) {
+	res, err := GetCRD(ctx, kube, gvr)
+	if err != nil {
+		return false, false, err
+	}
+	if res == nil {
+		log.Printf("[WRN] CRD NOT found (gvr: %s)\n", gvr.String())
+		return false, false, nil
+	}
+
+	log.Printf("[DBG] Looking for matching version (%s)\n", gvr.Version)
+	if !CRDHasVersion(res, gvr.Version) {
+		return false, false, nil
+	}
+
+	return true, CRDEstablished(res), nil
+}
+
+// CRDHasVersion reports whether the CRD defines version.
+func CRDHasVersion(crd *apiextensionsv1.CustomResourceDefinition, version string) bool {
+	for _, el := range crd.Spec.Versions {
+		if el.Name == version {
+			return true
+		}
+	}
+	return false
+}
+
+// CRDEstablished reports whether the names of the CRD are accepted and the CRD is established.
+func CRDEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	return CRDCondition(crd, apiextensionsv1.NamesAccepted).Status == apiextensionsv1.ConditionTrue &&
+		CRDCondition(crd, apiextensionsv1.Established).Status == apiextensionsv1.ConditionTrue
+}
+
+// GetCRD returns the CRD serving gvr or nil when it does not exist.
+func GetCRD(ctx context.Context, kube client.Client, gvr schema.GroupVersionResource) (*apiextensionsv1.CustomResourceDefinition, error) {
 	res := apiextensionsv1.CustomResourceDefinition{}
 	err := kube.Get(ctx, client.ObjectKey{Name: gvr.GroupResource().String()}, &res, &client.GetOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			log.Printf("[WRN] CRD NOT found (gvr: %s)\n", gvr.String())
-			return false, nil
-		} else {
-			return false, err
+			return nil, nil
 		}
+		return nil, err
 	}
 
-	log.Printf("[DBG] Looking for matching version (%s)\n", gvr.Version)
-	for _, el := range res.Spec.Versions {
-		if el.Name == gvr.Version {
-			return true, nil
+	return &res, nil
+}
+
+// CRDCondition returns the condition of type ct of the CRD. A condition
+// with unknown status is returned when the CRD does not report it yet.
+func CRDCondition(crd *apiextensionsv1.CustomResourceDefinition, ct apiextensionsv1.CustomResourceDefinitionConditionType) apiextensionsv1.CustomResourceDefinitionCondition {
+	for _, c := range crd.Status.Conditions {
+		if c.Type == ct {
+			return c
 		}
 	}
 
-	return false, nil
+	return apiextensionsv1.CustomResourceDefinitionCondition{
+		Type:   ct,
+		Status: apiextensionsv1.ConditionUnknown,
+	}
 }
 
 func UnmarshalCRD(dat []byte) (*apiextensionsv1.CustomResourceDefinition, error) {
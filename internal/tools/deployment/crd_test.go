@@ -66,7 +66,7 @@ package deployment_test
 // 		Version:  "v1",
 // 		Resource: "testcrds",
 // 	}
-// 	found, err := deployment.LookupCRD(ctx, kube, gvr)
+// 	found, _, err := deployment.LookupCRD(ctx, kube, gvr)
 // 	if err != nil {
 // 		t.Fatalf("failed to lookup CRD: %v", err)
 // 	}
@@ -135,7 +135,7 @@ package deployment_test
 // 		Version:  "v1",
 // 		Resource: "testcrds",
 // 	}
-// 	found, err := deployment.LookupCRD(ctx, kube, gvr)
+// 	found, _, err := deployment.LookupCRD(ctx, kube, gvr)
 // 	if err != nil {
 // 		t.Fatalf("failed to lookup CRD: %v", err)
 // 	}
@@ -7,112 +7,145 @@ import (
 
 	"github.com/gobuffalo/flect"
 	definitionsv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
-	"github.com/krateoplatformops/oasgen-provider/internal/tools/generation"
 	rbactools "github.com/krateoplatformops/oasgen-provider/internal/tools/rbactools"
-	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
-	"github.com/pb33f/libopenapi/orderedmap"
 	v1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// AuthGroupResource returns the group resource of the CRD of the authentication kind auth.
+func AuthGroupResource(auth definitionsv1alpha1.KindApiVersion) schema.GroupResource {
+	return schema.GroupResource{
+		Group:    schema.FromAPIVersionAndKind(auth.APIVersion, auth.Kind).Group,
+		Resource: flect.Pluralize(strings.ToLower(auth.Kind)),
+	}
+}
+
 type UndeployOptions struct {
-	KubeClient      client.Client
-	NamespacedName  types.NamespacedName
-	GVR             schema.GroupVersionResource
-	Log             func(msg string, keysAndValues ...any)
-	SecuritySchemes *orderedmap.Map[string, *v3.SecurityScheme]
+	KubeClient client.Client
+	// Reader, when set, reads the objects installed under legacy names, see UninstallLegacy.
+	Reader         client.Reader
+	NamespacedName types.NamespacedName
+	// GVRs of the resources of the RestDefinition.
+	GVRs []schema.GroupVersionResource
+	Log  func(msg string, keysAndValues ...any)
+	// Authentications resources whose CRDs are removed: the ones installed for
+	// the RestDefinition and not used by another one.
+	Authentications []definitionsv1alpha1.KindApiVersion
+	// WatchNamespaces of the dynamic controller, as returned by WatchNamespaces.
+	WatchNamespaces []string
+	// Inventory of the objects installed for the RestDefinition. The objects left
+	// by the steps above are removed last.
+	Inventory []definitionsv1alpha1.InventoryItem
 }
 
 func Undeploy(ctx context.Context, opts UndeployOptions) error {
+	controller := ControllerNamespacedName(opts.NamespacedName)
+
+	if opts.Reader != nil {
+		err := UninstallLegacy(ctx, opts.KubeClient, opts.Reader, opts.NamespacedName, opts.GVRs, opts.Log)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := UninstallDeployment(ctx, UninstallOptions{
-		KubeClient: opts.KubeClient,
-		NamespacedName: types.NamespacedName{
-			Namespace: opts.NamespacedName.Namespace,
-			Name:      fmt.Sprintf("%s-%s-controller", opts.GVR.Resource, opts.GVR.Version),
-		},
-		Log: opts.Log,
+		KubeClient:     opts.KubeClient,
+		NamespacedName: controller,
+		Log:            opts.Log,
 	})
 	if err != nil {
 		return err
 	}
 
-	err = rbactools.UninstallRoleBinding(ctx, rbactools.UninstallOptions{
+	err = UninstallConfigMap(ctx, UninstallOptions{
 		KubeClient:     opts.KubeClient,
-		NamespacedName: opts.NamespacedName,
+		NamespacedName: ArtifactsNamespacedName(opts.NamespacedName),
 		Log:            opts.Log,
 	})
 	if err != nil {
 		return err
 	}
-	if opts.Log != nil {
-		opts.Log("RoleBinding successfully uninstalled", "name", opts.NamespacedName.String())
-	}
 
-	err = rbactools.UninstallRole(ctx, rbactools.UninstallOptions{
+	err = UninstallConfigMap(ctx, UninstallOptions{
 		KubeClient:     opts.KubeClient,
-		NamespacedName: opts.NamespacedName,
+		NamespacedName: OperationsNamespacedName(opts.NamespacedName),
 		Log:            opts.Log,
 	})
 	if err != nil {
 		return err
 	}
+
+	err = UninstallRBAC(ctx, RBACOptions{
+		KubeClient:      opts.KubeClient,
+		NamespacedName:  opts.NamespacedName,
+		WatchNamespaces: opts.WatchNamespaces,
+		Log:             opts.Log,
+	})
+	if err != nil {
+		return err
+	}
 	if opts.Log != nil {
-		opts.Log("Role successfully uninstalled", "name", opts.NamespacedName.String())
+		opts.Log("RBAC successfully uninstalled", "name", opts.NamespacedName.String())
 	}
 
 	err = rbactools.UninstallServiceAccount(ctx, rbactools.UninstallOptions{
 		KubeClient:     opts.KubeClient,
-		NamespacedName: opts.NamespacedName,
+		NamespacedName: controller,
 		Log:            opts.Log,
 	})
 	if err != nil {
 		return err
 	}
 	if opts.Log != nil {
-		opts.Log("ServiceAccount successfully uninstalled", "name", opts.NamespacedName.String())
+		opts.Log("ServiceAccount successfully uninstalled", "name", controller.String())
 	}
 
-	err = UninstallCRD(ctx, opts.KubeClient, opts.GVR.GroupResource())
-	if err == nil {
-		if opts.Log != nil {
-			opts.Log("CRD successfully uninstalled", "name", opts.GVR.GroupResource().String())
+	for _, gvr := range opts.GVRs {
+		err = UninstallCRD(ctx, opts.KubeClient, gvr.GroupResource())
+		if err == nil {
+			if opts.Log != nil {
+				opts.Log("CRD successfully uninstalled", "name", gvr.GroupResource().String())
+			}
 		}
-	}
-	if err != nil {
-		if opts.Log != nil {
-			opts.Log("failed to uninstall CRD", "name", opts.GVR.GroupResource().String(), "error", err)
+		if err != nil {
+			if opts.Log != nil {
+				opts.Log("failed to uninstall CRD", "name", gvr.GroupResource().String(), "error", err)
+			}
 		}
 	}
 
-	for secSchemaPair := opts.SecuritySchemes.First(); secSchemaPair != nil; secSchemaPair = secSchemaPair.Next() {
-		authSchemaName, err := generation.GenerateAuthSchemaName(secSchemaPair.Value())
-		if err != nil {
-			continue
-		}
+	for _, auth := range opts.Authentications {
+		gr := AuthGroupResource(auth)
 
 		if opts.Log != nil {
-			opts.Log("uninstalling CRD", "name", authSchemaName, "Group", opts.GVR.Group)
+			opts.Log("uninstalling CRD", "name", gr.String())
 		}
 
-		err = UninstallCRD(ctx, opts.KubeClient, schema.GroupResource{
-			Group:    opts.GVR.Group,
-			Resource: flect.Pluralize(strings.ToLower(authSchemaName)),
-		})
+		err = UninstallCRD(ctx, opts.KubeClient, gr)
 		if err != nil {
 			if opts.Log != nil {
-				opts.Log("failed to uninstall CRD", "name", authSchemaName, "error", err)
+				opts.Log("failed to uninstall CRD", "name", gr.String(), "error", err)
 			}
 		}
 		if err == nil {
 			if opts.Log != nil {
-				opts.Log("CRD successfully uninstalled", "name", opts.GVR.GroupResource().String())
+				opts.Log("CRD successfully uninstalled", "name", gr.String())
 			}
 		}
 	}
+	if err != nil {
+		return err
+	}
+
+	// The provider no longer needs access to the generated kinds.
+	err = UninstallProviderClusterRole(ctx, opts.KubeClient, opts.NamespacedName, opts.Log)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return UninstallInventory(ctx, opts.KubeClient, opts.Inventory, opts.Log)
 }
 
 type DeployOptions struct {
@@ -121,74 +154,48 @@ type DeployOptions struct {
 	Spec            *definitionsv1alpha1.RestDefinitionSpec
 	ResourceVersion string
 	Role            v1.Role
-	Log             func(msg string, keysAndValues ...any)
+	// Secrets read by the dynamic controller, by namespace.
+	Secrets map[string][]string
+	// ConnectionSecrets created by the dynamic controller, by namespace.
+	ConnectionSecrets map[string][]string
+	// ExtendedArgs is true when the dynamic controller image accepts one -resource
+	// argument per resource and the -operations argument.
+	ExtendedArgs bool
+	Log          func(msg string, keysAndValues ...any)
 }
 
 func Deploy(ctx context.Context, opts DeployOptions) error {
-
-	sa := rbactools.CreateServiceAccount(opts.NamespacedName)
-	if err := rbactools.InstallServiceAccount(ctx, opts.KubeClient, &sa); err != nil {
-		return fmt.Errorf("failed to install service account: %w", err)
-	}
-	if opts.Log != nil {
-		opts.Log("ServiceAccount successfully installed",
-			"name", sa.Name, "namespace", sa.Namespace)
-	}
-
-	gvr := ToGroupVersionResource(schema.GroupVersionKind{
-		Group:   opts.Spec.ResourceGroup,
-		Version: opts.ResourceVersion,
-		Kind:    opts.Spec.Resource.Kind,
+	gvrs := ResourceGVRs(opts.Spec, opts.ResourceVersion)
+
+	watchNamespaces := WatchNamespaces(opts.Spec, opts.NamespacedName.Namespace)
+
+	_, err := ApplyRBAC(ctx, RBACOptions{
+		KubeClient:        opts.KubeClient,
+		NamespacedName:    opts.NamespacedName,
+		Rules:             opts.Role.Rules,
+		WatchNamespaces:   watchNamespaces,
+		Secrets:           opts.Secrets,
+		ConnectionSecrets: opts.ConnectionSecrets,
+		Log:               opts.Log,
 	})
-
-	if err := rbactools.InstallRole(ctx, opts.KubeClient, &opts.Role); err != nil {
-		return fmt.Errorf("failed to install role: %w", err)
-	}
-	if opts.Log != nil {
-		opts.Log("Role successfully installed",
-			"gvr", gvr.String(), "name", opts.Role.Name, "namespace", opts.Role.Namespace)
-	}
-
-	rb := rbactools.CreateRoleBinding(opts.NamespacedName)
-	if err := rbactools.InstallRoleBinding(ctx, opts.KubeClient, &rb); err != nil {
+	if err != nil {
 		return err
 	}
-	if opts.Log != nil {
-		opts.Log("RoleBinding successfully installed",
-			"gvr", gvr.String(), "name", rb.Name, "namespace", rb.Namespace)
-	}
-
-	// cr := CreateClusterRole(opts.NamespacedName)
-	// if err := InstallClusterRole(ctx, opts.KubeClient, &cr); err != nil {
-	// 	return err
-	// }
-	// if opts.Log != nil {
-	// 	opts.Log("ClusterRole successfully installed",
-	// 		"gvr", gvr.String(), "name", cr.Name, "namespace", cr.Namespace)
-	// }
-
-	// crb := CreateClusterRoleBinding(opts.NamespacedName)
-	// if err := InstallClusterRoleBinding(ctx, opts.KubeClient, &crb); err != nil {
-	// 	return err
-	// }
-	// if opts.Log != nil {
-	// 	opts.Log("ClusterRoleBinding successfully installed",
-	// 		"gvr", gvr.String(), "name", crb.Name, "namespace", crb.Namespace)
-	// }
-	dep, err := CreateDeployment(gvr, opts.NamespacedName)
+
+	dep, err := CreateDeployment(gvrs, opts.NamespacedName, watchNamespaces, opts.ExtendedArgs)
 	if err != nil {
 		return fmt.Errorf("failed to create deployment: %w", err)
 	}
 	// b, _ := yaml.Marshal(dep)
 	// fmt.Println(string(b))
 
-	err = InstallDeployment(ctx, opts.KubeClient, &dep)
+	_, err = ApplyDeployment(ctx, opts.KubeClient, &dep)
 	if err != nil {
 		return fmt.Errorf("failed to install deployment: %w", err)
 	}
 	if opts.Log != nil {
 		opts.Log("Deployment successfully installed",
-			"gvr", gvr.String(), "name", dep.Name, "namespace", dep.Namespace)
+			"gvrs", gvrs, "name", dep.Name, "namespace", dep.Namespace)
 	}
 
 	return nil
@@ -2,10 +2,13 @@ package deployment
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"slices"
 
 	"github.com/avast/retry-go"
 	"github.com/krateoplatformops/oasgen-provider/internal/templates"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/rbactools"
 	appsv1 "k8s.io/api/apps/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -61,7 +64,7 @@ func InstallDeployment(ctx context.Context, kube client.Client, obj *appsv1.Depl
 			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
 			if err != nil {
 				if apierrors.IsNotFound(err) {
-					return kube.Create(ctx, obj)
+					return rbactools.CreateOrAdopt(ctx, kube, obj)
 				}
 
 				return err
@@ -72,15 +75,38 @@ func InstallDeployment(ctx context.Context, kube client.Client, obj *appsv1.Depl
 	)
 }
 
-func CreateDeployment(gvr schema.GroupVersionResource, nn types.NamespacedName) (appsv1.Deployment, error) {
+// CreateDeployment renders the deployment of the dynamic controller of the
+// RestDefinition nn, serving the supplied resources (all of the same group and
+// version). The controller watches the supplied namespaces, or all namespaces
+// when watchNamespaces is nil.
+// Unless extendedArgs is true, the dynamic controller image is expected to accept
+// a single -resource argument, a single namespace in the -namespace argument and
+// no -operations argument: the operation map is mounted anyway.
+func CreateDeployment(gvrs []schema.GroupVersionResource, nn types.NamespacedName, watchNamespaces []string, extendedArgs bool) (appsv1.Deployment, error) {
+	if len(gvrs) == 0 {
+		return appsv1.Deployment{}, fmt.Errorf("no resource to serve")
+	}
+	if len(gvrs) > 1 && !extendedArgs {
+		return appsv1.Deployment{}, fmt.Errorf("serving %d resources with a single dynamic controller requires the extended arguments of the dynamic controller", len(gvrs))
+	}
+	if len(watchNamespaces) != 1 && !extendedArgs {
+		return appsv1.Deployment{}, fmt.Errorf("watching all or several namespaces requires the extended arguments of the dynamic controller")
+	}
+	resources := make([]string, 0, len(gvrs))
+	for _, gvr := range gvrs {
+		resources = append(resources, gvr.Resource)
+	}
+
+	controller := ControllerNamespacedName(nn)
 	values := templates.Values(templates.Renderoptions{
-		Group:      gvr.Group,
-		Version:    gvr.Version,
-		Resource:   gvr.Resource,
-		Namespace:  nn.Namespace,
-		Name:       nn.Name,
-		Tag:        os.Getenv("CDC_IMAGE_TAG"),
-		ClientType: "REST",
+		Group:           gvrs[0].Group,
+		Version:         gvrs[0].Version,
+		Resources:       resources,
+		Namespace:       controller.Namespace,
+		Name:            controller.Name,
+		Tag:             os.Getenv("CDC_IMAGE_TAG"),
+		ClientType:      "REST",
+		WatchNamespaces: watchNamespaces,
 	})
 
 	dat, err := templates.RenderDeployment(values)
@@ -98,7 +124,83 @@ func CreateDeployment(gvr schema.GroupVersionResource, nn types.NamespacedName)
 
 	res := appsv1.Deployment{}
 	_, _, err = s.Decode(dat, nil, &res)
-	return res, err
+	if err != nil {
+		return res, err
+	}
+	SetOwnerLabels(&res, nn)
+	MountOperations(&res, []string{OperationsNamespacedName(nn).Name}, extendedArgs)
+	return res, nil
+}
+
+// ApplyDeployment creates the deployment if it does not exist, otherwise it restores
+// the fields managed by the provider when they have been changed.
+// Returns true if the deployment was created or updated.
+func ApplyDeployment(ctx context.Context, kube client.Client, obj *appsv1.Deployment) (changed bool, err error) {
+	err = retry.Do(
+		func() error {
+			tmp := appsv1.Deployment{}
+			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					changed = true
+					return rbactools.CreateOrAdopt(ctx, kube, obj)
+				}
+
+				return err
+			}
+
+			if DeploymentUpToDate(&tmp, obj) {
+				return nil
+			}
+
+			for k, v := range obj.Labels {
+				if tmp.Labels == nil {
+					tmp.Labels = map[string]string{}
+				}
+				tmp.Labels[k] = v
+			}
+			tmp.Spec = obj.Spec
+			changed = true
+			return kube.Update(ctx, &tmp)
+		},
+	)
+	return changed, err
+}
+
+// DeploymentUpToDate returns true if the fields of the live deployment managed by the
+// provider (labels, replicas, service account, containers images and arguments,
+// mounted operation maps) match
+// the desired ones. Fields defaulted by the API server are ignored.
+func DeploymentUpToDate(live, desired *appsv1.Deployment) bool {
+	for k, v := range desired.Labels {
+		if live.Labels[k] != v {
+			return false
+		}
+	}
+
+	if desired.Spec.Replicas != nil &&
+		(live.Spec.Replicas == nil || *live.Spec.Replicas != *desired.Spec.Replicas) {
+		return false
+	}
+
+	lspec, dspec := live.Spec.Template.Spec, desired.Spec.Template.Spec
+	if lspec.ServiceAccountName != dspec.ServiceAccountName {
+		return false
+	}
+	if len(lspec.Containers) != len(dspec.Containers) {
+		return false
+	}
+	for i := range dspec.Containers {
+		lc, dc := lspec.Containers[i], dspec.Containers[i]
+		if lc.Name != dc.Name || lc.Image != dc.Image || !slices.Equal(lc.Args, dc.Args) {
+			return false
+		}
+	}
+	if !slices.Equal(MountedOperations(live), MountedOperations(desired)) {
+		return false
+	}
+
+	return true
 }
 
 func LookupDeployment(ctx context.Context, kube client.Client, obj *appsv1.Deployment) (bool, bool, error) {
@@ -111,7 +213,5 @@ func LookupDeployment(ctx context.Context, kube client.Client, obj *appsv1.Deplo
 		return false, false, err
 	}
 
-	ready := obj.Spec.Replicas != nil && *obj.Spec.Replicas == obj.Status.ReadyReplicas
-
-	return true, ready, nil
+	return true, RolloutStatus(obj).Done, nil
 }
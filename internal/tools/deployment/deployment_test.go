@@ -3,6 +3,8 @@ package deployment_test
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,6 +14,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -89,18 +92,57 @@ func TestCreateDeployment(t *testing.T) {
 	}
 
 	// Create the deployment
-	deploymentObj, err := deployment.CreateDeployment(gvr, nn)
+	deploymentObj, err := deployment.CreateDeployment([]schema.GroupVersionResource{gvr}, nn, []string{"ns-1", "ns-2"}, true)
 	if err != nil {
 		t.Errorf("failed to create deployment: %v", err)
 	}
 
 	// Verify the deployment fields
-	if deploymentObj.Name != fmt.Sprintf("%s-%s-controller", gvr.Resource, gvr.Version) {
-		t.Errorf("expected deployment name to be %s, got %s", nn.Name, deploymentObj.Name)
+	if deploymentObj.Name != fmt.Sprintf("%s-controller", nn.Name) {
+		t.Errorf("expected deployment name to be %s-controller, got %s", nn.Name, deploymentObj.Name)
+	}
+	if deploymentObj.Spec.Template.Spec.ServiceAccountName != deploymentObj.Name {
+		t.Errorf("expected service account name to be %s, got %s", deploymentObj.Name, deploymentObj.Spec.Template.Spec.ServiceAccountName)
 	}
 	if deploymentObj.Namespace != nn.Namespace {
 		t.Errorf("expected deployment namespace to be %s, got %s", nn.Namespace, deploymentObj.Namespace)
 	}
+	args := deploymentObj.Spec.Template.Spec.Containers[0].Args
+	if !slices.Contains(args, "-namespace=ns-1,ns-2") {
+		t.Errorf("expected watched namespaces in args, got %v", args)
+	}
+	if !slices.Contains(args, "-operations="+deployment.OperationsMountPath) {
+		t.Errorf("expected the operation maps in args, got %v", args)
+	}
+	if got := deployment.MountedOperations(&deploymentObj); !slices.Equal(got, []string{"test-deployment-operations"}) {
+		t.Errorf("expected the operations configmap to be mounted, got %v", got)
+	}
+
+	// Without the extended arguments, the operation maps are mounted but not passed
+	// to the dynamic controller, and a single resource in a single namespace is served
+	deploymentObj, err = deployment.CreateDeployment([]schema.GroupVersionResource{gvr}, nn, []string{nn.Namespace}, false)
+	if err != nil {
+		t.Fatalf("failed to create deployment: %v", err)
+	}
+	args = deploymentObj.Spec.Template.Spec.Containers[0].Args
+	if !slices.Contains(args, "-namespace="+nn.Namespace) || !slices.Contains(args, "-resource="+gvr.Resource) {
+		t.Errorf("expected the namespace and the resource in args, got %v", args)
+	}
+	if slices.ContainsFunc(args, func(arg string) bool { return strings.HasPrefix(arg, "-operations=") }) {
+		t.Errorf("expected no operation maps in args, got %v", args)
+	}
+	if got := deployment.MountedOperations(&deploymentObj); !slices.Equal(got, []string{"test-deployment-operations"}) {
+		t.Errorf("expected the operations configmap to be mounted, got %v", got)
+	}
+	other := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}
+	if _, err := deployment.CreateDeployment([]schema.GroupVersionResource{gvr, other}, nn, []string{nn.Namespace}, false); err == nil {
+		t.Errorf("expected an error serving two resources without the extended arguments")
+	}
+	for _, watchNamespaces := range [][]string{nil, {"ns-1", "ns-2"}} {
+		if _, err := deployment.CreateDeployment([]schema.GroupVersionResource{gvr}, nn, watchNamespaces, false); err == nil {
+			t.Errorf("expected an error watching %v without the extended arguments", watchNamespaces)
+		}
+	}
 	// Add more assertions for other fields if needed
 }
 
@@ -138,3 +180,58 @@ func TestLookupDeployment(t *testing.T) {
 		t.Logf("deployment is not ready")
 	}
 }
+
+func TestApplyDeployment(t *testing.T) {
+	ctx := context.TODO()
+	nn := types.NamespacedName{Namespace: "default", Name: "def-pet"}
+	gvr := schema.GroupVersionResource{Group: "petstore.swagger.io", Version: "v1alpha1", Resource: "pets"}
+
+	desired, err := deployment.CreateDeployment([]schema.GroupVersionResource{gvr}, nn, []string{"default"}, true)
+	if err != nil {
+		t.Fatalf("failed to create deployment: %v", err)
+	}
+	if owner, ok := deployment.OwnerOf(&desired); !ok || owner != nn {
+		t.Errorf("expected deployment to be labeled with its RestDefinition, got %v", desired.Labels)
+	}
+
+	client := fake.NewClientBuilder().Build()
+
+	changed, err := deployment.ApplyDeployment(ctx, client, desired.DeepCopy())
+	if err != nil {
+		t.Fatalf("failed to apply deployment: %v", err)
+	}
+	if !changed {
+		t.Errorf("expected deployment to be created")
+	}
+
+	live := appsv1.Deployment{}
+	if err := client.Get(ctx, deployment.ControllerNamespacedName(nn), &live); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if !deployment.DeploymentUpToDate(&live, &desired) {
+		t.Errorf("expected deployment to be up to date")
+	}
+
+	live.Spec.Template.Spec.Containers[0].Image = "busybox"
+	live.Spec.Replicas = ptr.To(int32(0))
+	if err := client.Update(ctx, &live); err != nil {
+		t.Fatalf("failed to update deployment: %v", err)
+	}
+	if deployment.DeploymentUpToDate(&live, &desired) {
+		t.Errorf("expected edited deployment not to be up to date")
+	}
+
+	changed, err = deployment.ApplyDeployment(ctx, client, desired.DeepCopy())
+	if err != nil {
+		t.Fatalf("failed to apply deployment: %v", err)
+	}
+	if !changed {
+		t.Errorf("expected deployment to be restored")
+	}
+	if err := client.Get(ctx, deployment.ControllerNamespacedName(nn), &live); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if !deployment.DeploymentUpToDate(&live, &desired) {
+		t.Errorf("expected restored deployment to be up to date")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/gobuffalo/flect"
+	definitionsv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -14,3 +15,16 @@ func ToGroupVersionResource(gvk schema.GroupVersionKind) schema.GroupVersionReso
 		Resource: strings.ToLower(flect.Pluralize(gvk.Kind)),
 	}
 }
+
+// ResourceGVRs returns the GroupVersionResource of each resource of spec, at version.
+func ResourceGVRs(spec *definitionsv1alpha1.RestDefinitionSpec, version string) []schema.GroupVersionResource {
+	res := []schema.GroupVersionResource{}
+	for _, r := range spec.AllResources() {
+		res = append(res, ToGroupVersionResource(schema.GroupVersionKind{
+			Group:   spec.ResourceGroup,
+			Version: version,
+			Kind:    r.Kind,
+		}))
+	}
+	return res
+}
@@ -3,6 +3,7 @@ package deployment_test
 import (
 	"testing"
 
+	definitionsv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
 	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -25,3 +26,22 @@ func TestToGroupVersionResource(t *testing.T) {
 		t.Errorf("Expected %v, but got %v", expected, result)
 	}
 }
+
+func TestResourceGVRs(t *testing.T) {
+	spec := &definitionsv1alpha1.RestDefinitionSpec{
+		ResourceGroup: "petstore.swagger.io",
+		Resource:      definitionsv1alpha1.Resource{Kind: "Pet"},
+		Resources:     []definitionsv1alpha1.Resource{{Kind: "User"}, {Kind: "Order"}},
+	}
+
+	gvrs := deployment.ResourceGVRs(spec, "v1alpha1")
+	expected := []string{"pets", "users", "orders"}
+	if len(gvrs) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, gvrs)
+	}
+	for i, gvr := range gvrs {
+		if gvr.Resource != expected[i] || gvr.Group != "petstore.swagger.io" || gvr.Version != "v1alpha1" {
+			t.Errorf("unexpected gvr %s", gvr)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package deployment
+
+import (
+	"context"
+
+	"github.com/avast/retry-go"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ListInstances returns the custom resources of kind gvk in all namespaces.
+// No custom resource is returned when the kind is not served.
+func ListInstances(ctx context.Context, kube client.Client, gvk schema.GroupVersionKind) ([]unstructured.Unstructured, error) {
+	list := unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	err := kube.List(ctx, &list)
+	if err != nil {
+		if apimeta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// DeleteInstances requests the deletion of the custom resources not being deleted yet.
+// Their finalizers are left to the dynamic controller.
+func DeleteInstances(ctx context.Context, kube client.Client, items []unstructured.Unstructured) error {
+	for i := range items {
+		obj := &items[i]
+		if obj.GetDeletionTimestamp() != nil {
+			continue
+		}
+		err := kube.Delete(ctx, obj)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// OrphanInstances removes the finalizers of the custom resources and deletes them,
+// so that they go away without their external resources being deleted.
+// The dynamic controller must be stopped first.
+func OrphanInstances(ctx context.Context, kube client.Client, items []unstructured.Unstructured) error {
+	for i := range items {
+		key := client.ObjectKeyFromObject(&items[i])
+		gvk := items[i].GroupVersionKind()
+		err := retry.Do(
+			func() error {
+				obj := unstructured.Unstructured{}
+				obj.SetGroupVersionKind(gvk)
+				err := kube.Get(ctx, key, &obj)
+				if err != nil {
+					if apierrors.IsNotFound(err) {
+						return nil
+					}
+					return err
+				}
+
+				if len(obj.GetFinalizers()) > 0 {
+					obj.SetFinalizers(nil)
+					err = kube.Update(ctx, &obj)
+					if err != nil {
+						if apierrors.IsNotFound(err) {
+							return nil
+						}
+						return err
+					}
+				}
+				if obj.GetDeletionTimestamp() != nil {
+					return nil
+				}
+
+				err = kube.Delete(ctx, &obj)
+				if err != nil && !apierrors.IsNotFound(err) {
+					return err
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
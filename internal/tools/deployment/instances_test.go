@@ -0,0 +1,84 @@
+package deployment_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var petGVK = schema.GroupVersionKind{Group: "petstore.swagger.io", Version: "v1alpha1", Kind: "Pet"}
+
+func newPet(namespace, name string, finalizers ...string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(petGVK)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetFinalizers(finalizers)
+	return obj
+}
+
+func TestListInstances(t *testing.T) {
+	ctx := context.TODO()
+	kube := fake.NewClientBuilder().WithObjects(newPet("a", "fido"), newPet("b", "rex")).Build()
+
+	items, err := deployment.ListInstances(ctx, kube, petGVK)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("expected 2 instances, got %d", len(items))
+	}
+}
+
+func TestDeleteInstances(t *testing.T) {
+	ctx := context.TODO()
+	kube := fake.NewClientBuilder().WithObjects(
+		newPet("a", "fido", "composition.krateo.io/finalizer"),
+		newPet("b", "rex"),
+	).Build()
+
+	items, err := deployment.ListInstances(ctx, kube, petGVK)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := deployment.DeleteInstances(ctx, kube, items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The instance with a finalizer is left to the dynamic controller.
+	items, err = deployment.ListInstances(ctx, kube, petGVK)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].GetName() != "fido" || items[0].GetDeletionTimestamp() == nil {
+		t.Errorf("expected fido to be deleting, got %v", items)
+	}
+}
+
+func TestOrphanInstances(t *testing.T) {
+	ctx := context.TODO()
+	kube := fake.NewClientBuilder().WithObjects(
+		newPet("a", "fido", "composition.krateo.io/finalizer"),
+		newPet("b", "rex"),
+	).Build()
+
+	items, err := deployment.ListInstances(ctx, kube, petGVK)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := deployment.OrphanInstances(ctx, kube, items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items, err = deployment.ListInstances(ctx, kube, petGVK)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("expected no instances, got %v", items)
+	}
+}
@@ -0,0 +1,80 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	definitionsv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// uninstallOrder is the order in which the objects of an inventory are removed:
+// the dynamic controller first, the CRDs last. Other kinds are removed at the end.
+var uninstallOrder = []string{
+	"Deployment", "ConfigMap", "RoleBinding", "ClusterRoleBinding", "Role", "ClusterRole",
+	"ServiceAccount", "CustomResourceDefinition",
+}
+
+// Inventory returns the references to objs. The kinds of typed objects are
+// resolved with scheme.
+func Inventory(scheme *runtime.Scheme, objs ...client.Object) ([]definitionsv1alpha1.InventoryItem, error) {
+	res := make([]definitionsv1alpha1.InventoryItem, 0, len(objs))
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return nil, fmt.Errorf("resolving kind of %s: %w", obj.GetName(), err)
+		}
+		item := definitionsv1alpha1.InventoryItem{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Name:       obj.GetName(),
+			Namespace:  obj.GetNamespace(),
+		}
+		if !slices.Contains(res, item) {
+			res = append(res, item)
+		}
+	}
+	return res, nil
+}
+
+// UninstallInventory deletes the objects of the inventory, in uninstallOrder.
+// Objects already deleted and kinds no longer served are skipped.
+func UninstallInventory(ctx context.Context, kube client.Client, items []definitionsv1alpha1.InventoryItem, log func(msg string, keysAndValues ...any)) error {
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b definitionsv1alpha1.InventoryItem) int {
+		return kindRank(a.Kind) - kindRank(b.Kind)
+	})
+
+	for _, item := range sorted {
+		obj := unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(item.APIVersion, item.Kind))
+		obj.SetName(item.Name)
+		obj.SetNamespace(item.Namespace)
+
+		err := kube.Delete(ctx, &obj)
+		if err != nil {
+			if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+				continue
+			}
+			return fmt.Errorf("deleting %s %s: %w", item.Kind, item.Name, err)
+		}
+		if log != nil {
+			log("Object successfully uninstalled", "kind", item.Kind, "name", item.Name, "namespace", item.Namespace)
+		}
+	}
+	return nil
+}
+
+func kindRank(kind string) int {
+	if i := slices.Index(uninstallOrder, kind); i >= 0 {
+		return i
+	}
+	return len(uninstallOrder)
+}
@@ -0,0 +1,67 @@
+package deployment_test
+
+import (
+	"context"
+	"testing"
+
+	definitionsv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientsetscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestInventory(t *testing.T) {
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "def-pet-controller", Namespace: "demo"}}
+	cr := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "demo-def-pet-controller"}}
+
+	items, err := deployment.Inventory(clientsetscheme.Scheme, dep, cr, dep)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []definitionsv1alpha1.InventoryItem{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "def-pet-controller", Namespace: "demo"},
+		{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "demo-def-pet-controller"},
+	}
+	if len(items) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, items)
+	}
+	for i := range expected {
+		if items[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], items[i])
+		}
+	}
+}
+
+func TestUninstallInventory(t *testing.T) {
+	ctx := context.TODO()
+	objs := []client.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "def-pet-controller", Namespace: "demo"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "def-pet-controller", Namespace: "demo"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "demo-def-pet-controller"}},
+	}
+	kube := fake.NewClientBuilder().WithObjects(objs...).Build()
+
+	items, err := deployment.Inventory(clientsetscheme.Scheme, objs...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Already deleted objects are skipped.
+	items = append(items, definitionsv1alpha1.InventoryItem{APIVersion: "v1", Kind: "ConfigMap", Name: "def-pet-artifacts", Namespace: "demo"})
+
+	if err := deployment.UninstallInventory(ctx, kube, items, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, obj := range objs {
+		err := kube.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, obj)
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("expected %s to be uninstalled, got %v", obj.GetName(), err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package deployment
+
+import (
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/naming"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// LabelRestDefinitionName is set on the objects generated for a RestDefinition
+	// to the (possibly truncated, see naming.SafeName) name of the RestDefinition.
+	LabelRestDefinitionName = "swaggergen.krateo.io/restdefinition-name"
+	// LabelRestDefinitionNamespace is set on the objects generated for a RestDefinition
+	// to the namespace of the RestDefinition.
+	LabelRestDefinitionNamespace = "swaggergen.krateo.io/restdefinition-namespace"
+)
+
+// OwnerLabels returns the labels identifying the objects generated for the RestDefinition nn.
+func OwnerLabels(nn types.NamespacedName) map[string]string {
+	return map[string]string{
+		LabelRestDefinitionName:      naming.SafeName(nn.Name),
+		LabelRestDefinitionNamespace: nn.Namespace,
+	}
+}
+
+// SetOwnerLabels adds the OwnerLabels of the RestDefinition nn to obj.
+func SetOwnerLabels(obj client.Object, nn types.NamespacedName) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range OwnerLabels(nn) {
+		labels[k] = v
+	}
+	obj.SetLabels(labels)
+}
+
+// OwnerOf returns the RestDefinition obj has been generated for, as recorded by
+// its OwnerLabels. The name is truncated when the RestDefinition name is longer
+// than naming.MaxLength.
+func OwnerOf(obj client.Object) (types.NamespacedName, bool) {
+	labels := obj.GetLabels()
+	name, ok := labels[LabelRestDefinitionName]
+	if !ok || len(name) == 0 {
+		return types.NamespacedName{}, false
+	}
+	namespace, ok := labels[LabelRestDefinitionNamespace]
+	if !ok || len(namespace) == 0 {
+		return types.NamespacedName{}, false
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: name}, true
+}
+
+// HasOwnerLabels returns true if obj carries all the OwnerLabels of the RestDefinition nn.
+func HasOwnerLabels(obj client.Object, nn types.NamespacedName) bool {
+	labels := obj.GetLabels()
+	for k, v := range OwnerLabels(nn) {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// GeneratedSelector selects the objects generated for a RestDefinition or a controller
+// pool: both carry LabelRestDefinitionNamespace.
+func GeneratedSelector() labels.Selector {
+	req, _ := labels.NewRequirement(LabelRestDefinitionNamespace, selection.Exists, nil)
+	return labels.NewSelector().Add(*req)
+}
@@ -0,0 +1,118 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// legacyDeploymentNames returns the names the Deployment of the dynamic controller of
+// the RestDefinition nn had before ControllerNamespacedName: the name of the
+// RestDefinition and '<resource>-<version>-controller'.
+func legacyDeploymentNames(nn types.NamespacedName, gvrs []schema.GroupVersionResource) []string {
+	res := []string{nn.Name}
+	for _, gvr := range gvrs {
+		res = append(res, fmt.Sprintf("%s-%s-controller", gvr.Resource, gvr.Version))
+	}
+	return res
+}
+
+// UninstallLegacy removes the dynamic controller of the RestDefinition nn installed
+// under the names used before ControllerNamespacedName: its Deployment, and the
+// ServiceAccount, Role and RoleBinding named after nn. Those objects carry no owner
+// labels, so they are not in the cache of the provider: they are read through reader.
+// Objects with owner or pool labels, or not matching the dynamic controller of the
+// supplied resources, are left untouched.
+func UninstallLegacy(ctx context.Context, kube client.Client, reader client.Reader, nn types.NamespacedName, gvrs []schema.GroupVersionResource, log func(msg string, keysAndValues ...any)) error {
+	del := func(obj client.Object) error {
+		if err := kube.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete legacy %T %s: %w", obj, client.ObjectKeyFromObject(obj), err)
+		}
+		if log != nil {
+			log(fmt.Sprintf("Legacy %T successfully uninstalled", obj), "name", obj.GetName(), "namespace", obj.GetNamespace())
+		}
+		return nil
+	}
+	get := func(name string, obj client.Object) (bool, error) {
+		err := reader.Get(ctx, types.NamespacedName{Namespace: nn.Namespace, Name: name}, obj)
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return !labeled(obj), nil
+	}
+
+	for _, name := range legacyDeploymentNames(nn, gvrs) {
+		dep := appsv1.Deployment{}
+		ok, err := get(name, &dep)
+		if err != nil {
+			return err
+		}
+		if ok && servesAny(&dep, gvrs) {
+			if err := del(&dep); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The RoleBinding tells the ServiceAccount and Role of the dynamic controller apart
+	// from other objects named after the RestDefinition.
+	rb := rbacv1.RoleBinding{}
+	ok, err := get(nn.Name, &rb)
+	if err != nil || !ok {
+		return err
+	}
+	if rb.RoleRef.Kind != "Role" || rb.RoleRef.Name != nn.Name ||
+		!slices.Contains(rb.Subjects, rbacv1.Subject{Kind: "ServiceAccount", Name: nn.Name, Namespace: nn.Namespace}) {
+		return nil
+	}
+	if err := del(&rb); err != nil {
+		return err
+	}
+	role := rbacv1.Role{}
+	if ok, err := get(nn.Name, &role); err != nil {
+		return err
+	} else if ok {
+		if err := del(&role); err != nil {
+			return err
+		}
+	}
+	sa := corev1.ServiceAccount{}
+	if ok, err := get(nn.Name, &sa); err != nil {
+		return err
+	} else if ok {
+		if err := del(&sa); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// labeled returns true if obj carries the owner or pool labels of the provider.
+func labeled(obj client.Object) bool {
+	_, owned := OwnerOf(obj)
+	_, pooled := PoolOf(obj)
+	return owned || pooled
+}
+
+// servesAny returns true if dep runs a dynamic controller serving one of gvrs.
+func servesAny(dep *appsv1.Deployment, gvrs []schema.GroupVersionResource) bool {
+	for _, c := range dep.Spec.Template.Spec.Containers {
+		for _, gvr := range gvrs {
+			if slices.Contains(c.Args, "-group="+gvr.Group) && slices.Contains(c.Args, "-resource="+gvr.Resource) {
+				return true
+			}
+		}
+	}
+	return false
+}
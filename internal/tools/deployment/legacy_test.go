@@ -0,0 +1,84 @@
+package deployment_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUninstallLegacy(t *testing.T) {
+	ctx := context.TODO()
+	nn := types.NamespacedName{Namespace: "default", Name: "def-pet"}
+	gvr := schema.GroupVersionResource{Group: "petstore.swagger.io", Version: "v1alpha1", Resource: "pets"}
+
+	legacyDeployment := func(name string, args ...string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: nn.Namespace},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: name, Args: args}},
+			}}},
+		}
+	}
+	meta := metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace}
+
+	// The current dynamic controller of another RestDefinition, named like the legacy one of nn.
+	current := legacyDeployment(nn.Name, "-group="+gvr.Group, "-resource="+gvr.Resource)
+	deployment.SetOwnerLabels(current, types.NamespacedName{Namespace: nn.Namespace, Name: "def"})
+
+	kube := fake.NewClientBuilder().WithObjects(
+		legacyDeployment("pets-v1alpha1-controller", "-group="+gvr.Group, "-resource="+gvr.Resource),
+		legacyDeployment("users-v1alpha1-controller", "-group="+gvr.Group, "-resource=users"),
+		current,
+		&corev1.ServiceAccount{ObjectMeta: meta},
+		&rbacv1.Role{ObjectMeta: meta},
+		&rbacv1.RoleBinding{
+			ObjectMeta: meta,
+			RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: nn.Name},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: nn.Name, Namespace: nn.Namespace}},
+		},
+	).Build()
+
+	err := deployment.UninstallLegacy(ctx, kube, kube, nn, []schema.GroupVersionResource{gvr}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, obj := range []client.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "pets-v1alpha1-controller", Namespace: nn.Namespace}},
+		&corev1.ServiceAccount{ObjectMeta: meta},
+		&rbacv1.Role{ObjectMeta: meta},
+		&rbacv1.RoleBinding{ObjectMeta: meta},
+	} {
+		if err := kube.Get(ctx, client.ObjectKeyFromObject(obj), obj); !apierrors.IsNotFound(err) {
+			t.Errorf("expected legacy %T %s to be uninstalled, got %v", obj, obj.GetName(), err)
+		}
+	}
+	for _, name := range []string{"users-v1alpha1-controller", nn.Name} {
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: nn.Namespace, Name: name}, &appsv1.Deployment{}); err != nil {
+			t.Errorf("expected Deployment %s to be kept, got %v", name, err)
+		}
+	}
+
+	// A ServiceAccount and Role named after the RestDefinition but not bound together
+	// do not belong to a legacy dynamic controller.
+	kube = fake.NewClientBuilder().WithObjects(
+		&corev1.ServiceAccount{ObjectMeta: meta},
+		&rbacv1.Role{ObjectMeta: meta},
+	).Build()
+	if err := deployment.UninstallLegacy(ctx, kube, kube, nn, []schema.GroupVersionResource{gvr}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := kube.Get(ctx, nn, &corev1.ServiceAccount{}); err != nil {
+		t.Errorf("expected the ServiceAccount to be kept, got %v", err)
+	}
+}
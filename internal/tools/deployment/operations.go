@@ -0,0 +1,92 @@
+package deployment
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/naming"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// OperationsKey is the key of the operation map in the operations ConfigMap.
+	OperationsKey = "operations.json"
+	// OperationsMountPath is the directory where the operation maps are mounted
+	// in the dynamic controller, one '<configmap>.json' file per RestDefinition.
+	OperationsMountPath = "/etc/oasgen/operations"
+
+	operationsVolume = "operations"
+	operationsArg    = "-operations="
+)
+
+// OperationsNamespacedName returns the namespaced name of the ConfigMap holding the
+// operation map of the RestDefinition nn.
+func OperationsNamespacedName(nn types.NamespacedName) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: nn.Namespace,
+		Name:      naming.OperationsName(nn.Name),
+	}
+}
+
+// MountOperations mounts the operation maps held by the supplied ConfigMaps into
+// the containers of dep, replacing the ones already mounted. The dynamic controller
+// is pointed to them with the -operations argument only when extendedArgs is true:
+// the mount itself requires no support from the image.
+func MountOperations(dep *appsv1.Deployment, configMaps []string, extendedArgs bool) {
+	sources := make([]corev1.VolumeProjection, 0, len(configMaps))
+	for _, name := range configMaps {
+		sources = append(sources, corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Items:                []corev1.KeyToPath{{Key: OperationsKey, Path: name + ".json"}},
+			},
+		})
+	}
+
+	spec := &dep.Spec.Template.Spec
+	spec.Volumes = slices.DeleteFunc(spec.Volumes, func(v corev1.Volume) bool {
+		return v.Name == operationsVolume
+	})
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: operationsVolume,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{Sources: sources},
+		},
+	})
+
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		c.VolumeMounts = slices.DeleteFunc(c.VolumeMounts, func(m corev1.VolumeMount) bool {
+			return m.Name == operationsVolume
+		})
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      operationsVolume,
+			MountPath: OperationsMountPath,
+			ReadOnly:  true,
+		})
+		c.Args = slices.DeleteFunc(c.Args, func(arg string) bool {
+			return strings.HasPrefix(arg, operationsArg)
+		})
+		if extendedArgs {
+			c.Args = append(c.Args, operationsArg+OperationsMountPath)
+		}
+	}
+}
+
+// MountedOperations returns the ConfigMaps whose operation maps are mounted into dep.
+func MountedOperations(dep *appsv1.Deployment) []string {
+	res := []string{}
+	for _, v := range dep.Spec.Template.Spec.Volumes {
+		if v.Name != operationsVolume || v.Projected == nil {
+			continue
+		}
+		for _, src := range v.Projected.Sources {
+			if src.ConfigMap != nil {
+				res = append(res, src.ConfigMap.Name)
+			}
+		}
+	}
+	return res
+}
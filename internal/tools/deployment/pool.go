@@ -0,0 +1,242 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/naming"
+	rbactools "github.com/krateoplatformops/oasgen-provider/internal/tools/rbactools"
+	appsv1 "k8s.io/api/apps/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LabelControllerPool is set on the objects of a shared dynamic controller to the
+// name of its pool, in place of LabelRestDefinitionName.
+const LabelControllerPool = "swaggergen.krateo.io/controller-pool"
+
+// PoolLabels returns the labels identifying the objects of the controller pool nn.
+func PoolLabels(nn types.NamespacedName) map[string]string {
+	return map[string]string{
+		LabelControllerPool:          nn.Name,
+		LabelRestDefinitionNamespace: nn.Namespace,
+	}
+}
+
+// SetPoolLabels replaces the OwnerLabels of obj with the PoolLabels of the controller pool nn.
+func SetPoolLabels(obj client.Object, nn types.NamespacedName) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	delete(labels, LabelRestDefinitionName)
+	for k, v := range PoolLabels(nn) {
+		labels[k] = v
+	}
+	obj.SetLabels(labels)
+}
+
+// PoolOf returns the controller pool obj belongs to, as recorded by its PoolLabels.
+func PoolOf(obj client.Object) (types.NamespacedName, bool) {
+	labels := obj.GetLabels()
+	name := labels[LabelControllerPool]
+	namespace := labels[LabelRestDefinitionNamespace]
+	if len(name) == 0 || len(namespace) == 0 {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, true
+}
+
+// PoolMember describes what a RestDefinition needs from a shared dynamic controller.
+type PoolMember struct {
+	// NamespacedName of the RestDefinition.
+	NamespacedName types.NamespacedName
+	// GVRs of the resources of the RestDefinition.
+	GVRs []schema.GroupVersionResource
+	// Rules of the role of the dynamic controller of the RestDefinition.
+	Rules []rbacv1.PolicyRule
+	// WatchNamespaces as returned by WatchNamespaces.
+	WatchNamespaces []string
+	// Secrets read by the dynamic controller of the RestDefinition, by namespace.
+	Secrets map[string][]string
+	// ConnectionSecrets created by the dynamic controller of the RestDefinition, by namespace.
+	ConnectionSecrets map[string][]string
+}
+
+// Pool is the desired state of a shared dynamic controller.
+type Pool struct {
+	// NamespacedName of the pool: the namespace of its members and the name of the pool.
+	NamespacedName types.NamespacedName
+	// Members served by the shared dynamic controller.
+	Members []types.NamespacedName
+	// Conflicts are the members refused by the pool, with the reason.
+	Conflicts map[types.NamespacedName]string
+	// GVRs served by the shared dynamic controller.
+	GVRs []schema.GroupVersionResource
+	// Rules of the role of the shared dynamic controller.
+	Rules []rbacv1.PolicyRule
+	// WatchNamespaces of the shared dynamic controller.
+	WatchNamespaces []string
+	// Secrets read by the shared dynamic controller, by namespace.
+	Secrets map[string][]string
+	// ConnectionSecrets created by the shared dynamic controller, by namespace.
+	ConnectionSecrets map[string][]string
+	// ExtendedArgs is true when the dynamic controller image accepts one -resource
+	// argument per resource and the -operations argument.
+	ExtendedArgs bool
+}
+
+// NewPool merges the resources and rules of members, sorted by priority. The first
+// member sets the group and the watched namespaces of the pool: the dynamic
+// controller serves a single group, so the members with another group or other
+// watched namespaces are reported as conflicts.
+func NewPool(nn types.NamespacedName, members []PoolMember) Pool {
+	res := Pool{
+		NamespacedName: nn,
+		Conflicts:      map[types.NamespacedName]string{},
+		Secrets:        map[string][]string{},
+
+		ConnectionSecrets: map[string][]string{},
+	}
+	for _, m := range members {
+		if len(m.GVRs) == 0 {
+			continue
+		}
+		if len(res.Members) > 0 {
+			first := res.GVRs[0]
+			if m.GVRs[0].Group != first.Group || m.GVRs[0].Version != first.Version {
+				res.Conflicts[m.NamespacedName] = fmt.Sprintf("controller pool '%s' serves %s, not %s",
+					nn.Name, first.GroupVersion(), m.GVRs[0].GroupVersion())
+				continue
+			}
+			if !slices.Equal(m.WatchNamespaces, res.WatchNamespaces) {
+				res.Conflicts[m.NamespacedName] = fmt.Sprintf("controller pool '%s' watches other namespaces", nn.Name)
+				continue
+			}
+		} else {
+			res.WatchNamespaces = m.WatchNamespaces
+		}
+
+		res.Members = append(res.Members, m.NamespacedName)
+		for _, gvr := range m.GVRs {
+			if !slices.Contains(res.GVRs, gvr) {
+				res.GVRs = append(res.GVRs, gvr)
+			}
+		}
+		for _, rule := range m.Rules {
+			if !slices.ContainsFunc(res.Rules, func(r rbacv1.PolicyRule) bool {
+				return rbactools.RulesEqual([]rbacv1.PolicyRule{r}, []rbacv1.PolicyRule{rule})
+			}) {
+				res.Rules = append(res.Rules, rule)
+			}
+		}
+		mergeSecrets(res.Secrets, m.Secrets)
+		mergeSecrets(res.ConnectionSecrets, m.ConnectionSecrets)
+	}
+	return res
+}
+
+// mergeSecrets adds the secret names of src, by namespace, to dst.
+func mergeSecrets(dst, src map[string][]string) {
+	for ns, names := range src {
+		for _, name := range names {
+			if !slices.Contains(dst[ns], name) {
+				dst[ns] = append(dst[ns], name)
+			}
+		}
+	}
+}
+
+// owner returns the name standing for the pool in place of the one of a RestDefinition.
+func (p *Pool) owner() types.NamespacedName {
+	return types.NamespacedName{Namespace: p.NamespacedName.Namespace, Name: naming.PoolName(p.NamespacedName.Name)}
+}
+
+// ControllerNamespacedName returns the namespaced name of the ServiceAccount, Role,
+// RoleBinding and Deployment of the shared dynamic controller.
+func (p *Pool) ControllerNamespacedName() types.NamespacedName {
+	return ControllerNamespacedName(p.owner())
+}
+
+// Deployment renders the deployment of the shared dynamic controller, mounting the
+// operation maps of the members.
+func (p *Pool) Deployment() (appsv1.Deployment, error) {
+	res, err := CreateDeployment(p.GVRs, p.owner(), p.WatchNamespaces, p.ExtendedArgs)
+	if err != nil {
+		return res, err
+	}
+	SetPoolLabels(&res, p.NamespacedName)
+	configMaps := make([]string, 0, len(p.Members))
+	for _, m := range p.Members {
+		configMaps = append(configMaps, OperationsNamespacedName(m).Name)
+	}
+	MountOperations(&res, configMaps, p.ExtendedArgs)
+	return res, nil
+}
+
+// RBACOptions returns the options of the roles and bindings of the shared dynamic controller.
+func (p *Pool) RBACOptions(kube client.Client, log func(msg string, keysAndValues ...any)) RBACOptions {
+	return RBACOptions{
+		KubeClient:        kube,
+		NamespacedName:    p.owner(),
+		Rules:             p.Rules,
+		WatchNamespaces:   p.WatchNamespaces,
+		Secrets:           p.Secrets,
+		ConnectionSecrets: p.ConnectionSecrets,
+		Labels:            PoolLabels(p.NamespacedName),
+		Log:               log,
+	}
+}
+
+// ApplyPool installs or updates the ServiceAccount, the RBAC and the Deployment of
+// the shared dynamic controller.
+// Returns true if the deployment or the rules have been changed.
+func ApplyPool(ctx context.Context, kube client.Client, pool Pool, log func(msg string, keysAndValues ...any)) (bool, error) {
+	rbacChanged, err := ApplyRBAC(ctx, pool.RBACOptions(kube, log))
+	if err != nil {
+		return false, err
+	}
+
+	dep, err := pool.Deployment()
+	if err != nil {
+		return false, fmt.Errorf("failed to create deployment: %w", err)
+	}
+	depChanged, err := ApplyDeployment(ctx, kube, &dep)
+	if err != nil {
+		return false, fmt.Errorf("failed to install deployment: %w", err)
+	}
+	if depChanged && log != nil {
+		log("Shared Deployment successfully installed",
+			"pool", pool.NamespacedName.String(), "gvrs", pool.GVRs, "name", dep.Name, "namespace", dep.Namespace)
+	}
+
+	return rbacChanged || depChanged, nil
+}
+
+// UninstallPool removes the Deployment, the RBAC and the ServiceAccount of the
+// shared dynamic controller. The RoleBindings are looked up in the namespaces
+// watched by pool.
+func UninstallPool(ctx context.Context, kube client.Client, pool Pool, log func(msg string, keysAndValues ...any)) error {
+	controller := pool.ControllerNamespacedName()
+	err := UninstallDeployment(ctx, UninstallOptions{
+		KubeClient:     kube,
+		NamespacedName: controller,
+		Log:            log,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := UninstallRBAC(ctx, pool.RBACOptions(kube, log)); err != nil {
+		return err
+	}
+
+	return rbactools.UninstallServiceAccount(ctx, rbactools.UninstallOptions{
+		KubeClient:     kube,
+		NamespacedName: controller,
+		Log:            log,
+	})
+}
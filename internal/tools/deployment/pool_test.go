@@ -0,0 +1,123 @@
+package deployment_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/naming"
+	appsv1 "k8s.io/api/apps/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func poolMember(name, group, resource string) deployment.PoolMember {
+	return deployment.PoolMember{
+		NamespacedName:  types.NamespacedName{Namespace: "default", Name: name},
+		GVRs:            []schema.GroupVersionResource{{Group: group, Version: "v1alpha1", Resource: resource}},
+		Rules:           []rbacv1.PolicyRule{{APIGroups: []string{group}, Resources: []string{resource}, Verbs: []string{"get"}}},
+		WatchNamespaces: []string{"default"},
+	}
+}
+
+func TestNewPool(t *testing.T) {
+	nn := types.NamespacedName{Namespace: "default", Name: "github"}
+	other := poolMember("def-team", "gitlab.krateo.io", "teams")
+	elsewhere := poolMember("def-collaborator", "github.krateo.io", "collaborators")
+	elsewhere.WatchNamespaces = nil
+
+	pool := deployment.NewPool(nn, []deployment.PoolMember{
+		poolMember("def-repo", "github.krateo.io", "repoes"),
+		poolMember("def-team", "github.krateo.io", "teams"),
+		other,
+		elsewhere,
+	})
+
+	if len(pool.Members) != 2 || pool.Members[0].Name != "def-repo" || pool.Members[1].Name != "def-team" {
+		t.Errorf("expected def-repo and def-team as members, got %v", pool.Members)
+	}
+	if len(pool.GVRs) != 2 || len(pool.Rules) != 2 {
+		t.Errorf("expected the resources and rules of the members, got %v and %v", pool.GVRs, pool.Rules)
+	}
+	for _, m := range []deployment.PoolMember{other, elsewhere} {
+		if len(pool.Conflicts[m.NamespacedName]) == 0 {
+			t.Errorf("expected %s to conflict, got %v", m.NamespacedName, pool.Conflicts)
+		}
+	}
+	if got := pool.ControllerNamespacedName(); got.Name != naming.ControllerName(naming.PoolName("github")) || got.Namespace != "default" {
+		t.Errorf("unexpected controller name %s", got)
+	}
+}
+
+func TestApplyPool(t *testing.T) {
+	ctx := context.TODO()
+	kube := fake.NewFakeClient()
+	nn := types.NamespacedName{Namespace: "default", Name: "github"}
+
+	pool := deployment.NewPool(nn, []deployment.PoolMember{
+		poolMember("def-repo", "github.krateo.io", "repoes"),
+		poolMember("def-team", "github.krateo.io", "teams"),
+	})
+	pool.ExtendedArgs = true
+	changed, err := deployment.ApplyPool(ctx, kube, pool, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changed {
+		t.Errorf("expected the pool to be installed")
+	}
+
+	dep := appsv1.Deployment{}
+	if err := kube.Get(ctx, pool.ControllerNamespacedName(), &dep); err != nil {
+		t.Fatalf("expected the shared deployment, got %v", err)
+	}
+	args := dep.Spec.Template.Spec.Containers[0].Args
+	for _, arg := range []string{"-resource=repoes", "-resource=teams"} {
+		if !slices.Contains(args, arg) {
+			t.Errorf("expected %s in %v", arg, args)
+		}
+	}
+	if got := deployment.MountedOperations(&dep); !slices.Equal(got, []string{"def-repo-operations", "def-team-operations"}) {
+		t.Errorf("expected the operation maps of the members, got %v", got)
+	}
+	if got, ok := deployment.PoolOf(&dep); !ok || got != nn {
+		t.Errorf("expected pool labels, got %v", dep.Labels)
+	}
+	if _, ok := deployment.OwnerOf(&dep); ok {
+		t.Errorf("expected no owner labels, got %v", dep.Labels)
+	}
+	exists, upToDate, err := deployment.LookupRBAC(ctx, pool.RBACOptions(kube, nil))
+	if err != nil || !exists || !upToDate {
+		t.Errorf("expected the role to be up to date, got %v, %v, %v", exists, upToDate, err)
+	}
+
+	// A member leaves the pool
+	pool = deployment.NewPool(nn, []deployment.PoolMember{
+		poolMember("def-repo", "github.krateo.io", "repoes"),
+	})
+	pool.ExtendedArgs = true
+	changed, err = deployment.ApplyPool(ctx, kube, pool, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changed {
+		t.Errorf("expected the pool to be updated")
+	}
+	if err := kube.Get(ctx, pool.ControllerNamespacedName(), &dep); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slices.Contains(dep.Spec.Template.Spec.Containers[0].Args, "-resource=teams") {
+		t.Errorf("expected teams to be removed from %v", dep.Spec.Template.Spec.Containers[0].Args)
+	}
+
+	if err := deployment.UninstallPool(ctx, kube, pool, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := kube.Get(ctx, pool.ControllerNamespacedName(), &dep); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the shared deployment to be removed, got %v", err)
+	}
+}
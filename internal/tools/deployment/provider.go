@@ -0,0 +1,33 @@
+package deployment
+
+import (
+	"context"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/naming"
+	rbactools "github.com/krateoplatformops/oasgen-provider/internal/tools/rbactools"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LabelAggregateToProvider is set on the ClusterRoles aggregated into the role of the
+// provider (see manifests/rbac.yaml), which has no access to custom resources of its own.
+const LabelAggregateToProvider = "swaggergen.krateo.io/aggregate-to-provider"
+
+// ProviderClusterRole returns the ClusterRole granting the provider the rules on the
+// kinds generated for the RestDefinition nn.
+func ProviderClusterRole(nn types.NamespacedName, rules []rbacv1.PolicyRule) rbacv1.ClusterRole {
+	res := rbactools.CreateClusterRole(types.NamespacedName{Name: naming.ProviderRoleName(nn)}, rules)
+	SetOwnerLabels(&res, nn)
+	res.Labels[LabelAggregateToProvider] = "true"
+	return res
+}
+
+// UninstallProviderClusterRole removes the ClusterRole returned by ProviderClusterRole.
+func UninstallProviderClusterRole(ctx context.Context, kube client.Client, nn types.NamespacedName, log func(msg string, keysAndValues ...any)) error {
+	return rbactools.UninstallClusterRole(ctx, rbactools.UninstallOptions{
+		KubeClient:     kube,
+		NamespacedName: types.NamespacedName{Name: naming.ProviderRoleName(nn)},
+		Log:            log,
+	})
+}
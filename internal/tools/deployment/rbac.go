@@ -0,0 +1,366 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	definitionsv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/naming"
+	rbactools "github.com/krateoplatformops/oasgen-provider/internal/tools/rbactools"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WatchNamespaces returns the namespaces watched by the dynamic controller of the
+// RestDefinition living in namespace. A nil slice means all namespaces.
+func WatchNamespaces(spec *definitionsv1alpha1.RestDefinitionSpec, namespace string) []string {
+	if spec.Scope == definitionsv1alpha1.ScopeCluster {
+		return nil
+	}
+	if len(spec.WatchNamespaces) == 0 {
+		return []string{namespace}
+	}
+
+	seen := map[string]struct{}{}
+	res := []string{}
+	for _, ns := range spec.WatchNamespaces {
+		if ns == definitionsv1alpha1.AllNamespaces {
+			return nil
+		}
+		if _, ok := seen[ns]; ok || len(ns) == 0 {
+			continue
+		}
+		seen[ns] = struct{}{}
+		res = append(res, ns)
+	}
+	return res
+}
+
+// ControllerNamespacedName returns the namespaced name of the ServiceAccount, Role,
+// RoleBinding and Deployment of the dynamic controller of the RestDefinition nn.
+func ControllerNamespacedName(nn types.NamespacedName) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: nn.Namespace,
+		Name:      naming.ControllerName(nn.Name),
+	}
+}
+
+// ClusterRoleName returns the name of the cluster role (and cluster role binding)
+// granted to the dynamic controller of the RestDefinition nn when it watches
+// namespaces other than its own.
+func ClusterRoleName(nn types.NamespacedName) string {
+	return naming.ClusterScopedName(nn)
+}
+
+// RBACOptions describes the permissions of a dynamic controller.
+type RBACOptions struct {
+	KubeClient client.Client
+	// NamespacedName of the RestDefinition.
+	NamespacedName types.NamespacedName
+	Rules          []rbacv1.PolicyRule
+	// WatchNamespaces as returned by WatchNamespaces.
+	WatchNamespaces []string
+	// Secrets the dynamic controller reads, by namespace.
+	Secrets map[string][]string
+	// ConnectionSecrets the dynamic controller has created and updates, by namespace.
+	ConnectionSecrets map[string][]string
+	// Labels of the roles and bindings, the OwnerLabels of NamespacedName when nil.
+	Labels map[string]string
+	Log    func(msg string, keysAndValues ...any)
+}
+
+func setLabels(obj client.Object, opts RBACOptions) {
+	if opts.Labels == nil {
+		SetOwnerLabels(obj, opts.NamespacedName)
+		return
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range opts.Labels {
+		labels[k] = v
+	}
+	obj.SetLabels(labels)
+}
+
+func namespacedOnly(opts RBACOptions) bool {
+	return len(opts.WatchNamespaces) == 1 && opts.WatchNamespaces[0] == opts.NamespacedName.Namespace
+}
+
+// DesiredRBAC returns the service account of the dynamic controller, followed by
+// the roles and bindings granting it the rules:
+//   - a Role and a RoleBinding when it watches its own namespace only;
+//   - a ClusterRole and a RoleBinding per namespace when it watches a list of namespaces;
+//   - a ClusterRole and a ClusterRoleBinding when it watches all namespaces.
+//
+// Access to the secrets is always granted by namespace, since the resourceNames of a
+// ClusterRole would match the secrets of every namespace: in the Role in the first
+// case, otherwise in a Role and a RoleBinding per namespace holding referenced or
+// connection secrets.
+func DesiredRBAC(opts RBACOptions) []client.Object {
+	sa := ControllerNamespacedName(opts.NamespacedName)
+	account := rbactools.CreateServiceAccount(sa)
+	setLabels(&account, opts)
+	if namespacedOnly(opts) {
+		role, _ := rbactools.InitRole(sa)
+		role.Rules = slices.Clone(opts.Rules)
+		rbactools.PopulateSecretsRole(opts.Secrets[sa.Namespace], &role)
+		rbactools.PopulateOwnedSecretsRole(opts.ConnectionSecrets[sa.Namespace], &role)
+		setLabels(&role, opts)
+
+		rb := rbactools.CreateRoleBinding(sa)
+		setLabels(&rb, opts)
+		return []client.Object{&account, &role, &rb}
+	}
+
+	crName := types.NamespacedName{Name: ClusterRoleName(opts.NamespacedName)}
+	cr := rbactools.CreateClusterRole(crName, opts.Rules)
+	setLabels(&cr, opts)
+	res := []client.Object{&account, &cr}
+
+	if opts.WatchNamespaces == nil {
+		crb := rbactools.CreateClusterRoleBinding(crName, sa)
+		setLabels(&crb, opts)
+		res = append(res, &crb)
+	}
+
+	for _, ns := range opts.WatchNamespaces {
+		rb := rbactools.CreateClusterRoleRoleBinding(types.NamespacedName{
+			Namespace: ns,
+			Name:      crName.Name,
+		}, crName.Name, sa)
+		setLabels(&rb, opts)
+		res = append(res, &rb)
+	}
+
+	namespaces := slices.Collect(maps.Keys(opts.Secrets))
+	for ns := range opts.ConnectionSecrets {
+		if !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	slices.Sort(namespaces)
+	for _, ns := range namespaces {
+		if len(opts.Secrets[ns]) == 0 && len(opts.ConnectionSecrets[ns]) == 0 {
+			continue
+		}
+		nn := types.NamespacedName{Namespace: ns, Name: naming.SecretsRoleName(opts.NamespacedName)}
+		role := rbacv1.Role{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "rbac.authorization.k8s.io/v1",
+				Kind:       "Role",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      nn.Name,
+				Namespace: nn.Namespace,
+			},
+		}
+		rbactools.PopulateSecretsRole(opts.Secrets[ns], &role)
+		rbactools.PopulateOwnedSecretsRole(opts.ConnectionSecrets[ns], &role)
+		setLabels(&role, opts)
+
+		rb := rbactools.CreateRoleBinding(nn)
+		rb.Subjects[0].Name = sa.Name
+		rb.Subjects[0].Namespace = sa.Namespace
+		setLabels(&rb, opts)
+		res = append(res, &role, &rb)
+	}
+	return res
+}
+
+// ApplyRBAC installs the service account, roles and bindings returned by DesiredRBAC
+// and removes the roles and bindings no longer desired, i.e. the bindings of the
+// namespaces no longer watched.
+// Returns true if the RBAC has been changed.
+func ApplyRBAC(ctx context.Context, opts RBACOptions) (bool, error) {
+	desired := DesiredRBAC(opts)
+	changed := false
+	for _, obj := range desired {
+		var ok bool
+		var err error
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		switch o := obj.(type) {
+		case *corev1.ServiceAccount:
+			ok, err = rbactools.ApplyServiceAccount(ctx, opts.KubeClient, o)
+		case *rbacv1.Role:
+			ok, err = rbactools.ApplyRole(ctx, opts.KubeClient, o)
+		case *rbacv1.ClusterRole:
+			ok, err = rbactools.ApplyClusterRole(ctx, opts.KubeClient, o)
+		case *rbacv1.RoleBinding:
+			ok, err = rbactools.ApplyRoleBinding(ctx, opts.KubeClient, o)
+		case *rbacv1.ClusterRoleBinding:
+			ok, err = rbactools.ApplyClusterRoleBinding(ctx, opts.KubeClient, o)
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to install %s: %w", kind, err)
+		}
+		changed = changed || ok
+		if ok && opts.Log != nil {
+			opts.Log(fmt.Sprintf("%s successfully installed", kind),
+				"name", obj.GetName(), "namespace", obj.GetNamespace())
+		}
+	}
+
+	stale, err := staleRBAC(ctx, opts, desired)
+	if err != nil {
+		return false, err
+	}
+	if err := deleteAll(ctx, opts, stale); err != nil {
+		return false, err
+	}
+
+	return changed || len(stale) > 0, nil
+}
+
+// LookupRBAC returns true if the role (or cluster role) of the dynamic controller
+// exists and whether the service account, roles and bindings returned by DesiredRBAC
+// all exist as desired (rules, subjects and labels) and no role or binding is left
+// from a previous configuration.
+func LookupRBAC(ctx context.Context, opts RBACOptions) (exists bool, upToDate bool, err error) {
+	desired := DesiredRBAC(opts)
+	upToDate = true
+	roleChecked := false
+	for _, obj := range desired {
+		var found, ok bool
+		switch o := obj.(type) {
+		case *corev1.ServiceAccount:
+			found, ok, err = rbactools.LookupServiceAccount(ctx, opts.KubeClient, o)
+		case *rbacv1.Role:
+			found, ok, err = rbactools.LookupRole(ctx, opts.KubeClient, o)
+		case *rbacv1.ClusterRole:
+			found, ok, err = rbactools.LookupClusterRole(ctx, opts.KubeClient, o)
+		case *rbacv1.RoleBinding:
+			found, ok, err = rbactools.LookupRoleBinding(ctx, opts.KubeClient, o)
+		case *rbacv1.ClusterRoleBinding:
+			found, ok, err = rbactools.LookupClusterRoleBinding(ctx, opts.KubeClient, o)
+		}
+		if err != nil {
+			return false, false, err
+		}
+		switch obj.(type) {
+		case *rbacv1.Role, *rbacv1.ClusterRole:
+			// The first role is the one of the dynamic controller.
+			if !roleChecked && !found {
+				return false, false, nil
+			}
+			roleChecked = true
+		}
+		upToDate = upToDate && found && ok
+	}
+
+	stale, err := staleRBAC(ctx, opts, desired)
+	if err != nil {
+		return false, false, err
+	}
+	return true, upToDate && len(stale) == 0, nil
+}
+
+// staleRBAC lists the roles and bindings labeled for opts missing from desired.
+// The ClusterRole aggregated into the role of the provider shares the labels of
+// the RestDefinition but is not part of the dynamic controller RBAC.
+func staleRBAC(ctx context.Context, opts RBACOptions, desired []client.Object) ([]client.Object, error) {
+	set := opts.Labels
+	if set == nil {
+		set = OwnerLabels(opts.NamespacedName)
+	}
+	notProvider, err := labels.NewRequirement(LabelAggregateToProvider, selection.DoesNotExist, nil)
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.SelectorFromSet(set).Add(*notProvider)
+
+	key := func(obj client.Object) string {
+		return fmt.Sprintf("%T/%s/%s", obj, obj.GetNamespace(), obj.GetName())
+	}
+	keep := map[string]struct{}{}
+	for _, obj := range desired {
+		keep[key(obj)] = struct{}{}
+	}
+
+	res := []client.Object{}
+	lists := []client.ObjectList{
+		&rbacv1.RoleBindingList{},
+		&rbacv1.ClusterRoleBindingList{},
+		&rbacv1.RoleList{},
+		&rbacv1.ClusterRoleList{},
+	}
+	for _, list := range lists {
+		if err := opts.KubeClient.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, fmt.Errorf("failed to list %T: %w", list, err)
+		}
+		items, err := apimeta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			if _, ok := keep[key(obj)]; !ok {
+				res = append(res, obj)
+			}
+		}
+	}
+	return res, nil
+}
+
+func deleteAll(ctx context.Context, opts RBACOptions, objs []client.Object) error {
+	for _, obj := range objs {
+		if err := opts.KubeClient.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete %T %s: %w", obj, client.ObjectKeyFromObject(obj), err)
+		}
+		if opts.Log != nil {
+			opts.Log(fmt.Sprintf("%T successfully uninstalled", obj),
+				"name", obj.GetName(), "namespace", obj.GetNamespace())
+		}
+	}
+	return nil
+}
+
+// UninstallRBAC removes every role and binding ApplyRBAC may have installed.
+func UninstallRBAC(ctx context.Context, opts RBACOptions) error {
+	stale, err := staleRBAC(ctx, opts, nil)
+	if err != nil {
+		return err
+	}
+	if err := deleteAll(ctx, opts, stale); err != nil {
+		return err
+	}
+
+	uninstall := func(fn func(context.Context, rbactools.UninstallOptions) error, nn types.NamespacedName) error {
+		return fn(ctx, rbactools.UninstallOptions{
+			KubeClient:     opts.KubeClient,
+			NamespacedName: nn,
+			Log:            opts.Log,
+		})
+	}
+
+	sa := ControllerNamespacedName(opts.NamespacedName)
+	if err := uninstall(rbactools.UninstallRoleBinding, sa); err != nil {
+		return err
+	}
+	if err := uninstall(rbactools.UninstallRole, sa); err != nil {
+		return err
+	}
+
+	crName := ClusterRoleName(opts.NamespacedName)
+	for _, ns := range opts.WatchNamespaces {
+		if err := uninstall(rbactools.UninstallRoleBinding, types.NamespacedName{Namespace: ns, Name: crName}); err != nil {
+			return err
+		}
+	}
+	if err := uninstall(rbactools.UninstallClusterRoleBinding, types.NamespacedName{Name: crName}); err != nil {
+		return err
+	}
+	return uninstall(rbactools.UninstallClusterRole, types.NamespacedName{Name: crName})
+}
@@ -0,0 +1,342 @@
+package deployment_test
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	definitionsv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/naming"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWatchNamespaces(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     definitionsv1alpha1.RestDefinitionSpec
+		expected []string
+	}{
+		{
+			name:     "Default",
+			spec:     definitionsv1alpha1.RestDefinitionSpec{},
+			expected: []string{"default"},
+		},
+		{
+			name: "List",
+			spec: definitionsv1alpha1.RestDefinitionSpec{
+				WatchNamespaces: []string{"ns-1", "ns-2", "ns-1"},
+			},
+			expected: []string{"ns-1", "ns-2"},
+		},
+		{
+			name: "All namespaces",
+			spec: definitionsv1alpha1.RestDefinitionSpec{
+				WatchNamespaces: []string{"ns-1", definitionsv1alpha1.AllNamespaces},
+			},
+		},
+		{
+			name: "Cluster scope",
+			spec: definitionsv1alpha1.RestDefinitionSpec{
+				Scope:           definitionsv1alpha1.ScopeCluster,
+				WatchNamespaces: []string{"ns-1"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := deployment.WatchNamespaces(&tc.spec, "default")
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestApplyRBAC(t *testing.T) {
+	ctx := context.TODO()
+	nn := types.NamespacedName{Namespace: "default", Name: "def-pet"}
+	rules := []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{"petstore.swagger.io"},
+			Resources: []string{"pets"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		watchNamespaces []string
+		verify          func(t *testing.T, opts deployment.RBACOptions)
+	}{
+		{
+			name:            "Own namespace",
+			watchNamespaces: []string{"default"},
+			verify: func(t *testing.T, opts deployment.RBACOptions) {
+				controller := deployment.ControllerNamespacedName(nn)
+				role := rbacv1.Role{}
+				if err := opts.KubeClient.Get(ctx, controller, &role); err != nil {
+					t.Errorf("expected role to be installed: %v", err)
+				}
+				if owner, ok := deployment.OwnerOf(&role); !ok || owner != nn {
+					t.Errorf("expected role to be labeled with its RestDefinition, got %v", role.Labels)
+				}
+				if err := opts.KubeClient.Get(ctx, controller, &rbacv1.RoleBinding{}); err != nil {
+					t.Errorf("expected role binding to be installed: %v", err)
+				}
+			},
+		},
+		{
+			name:            "List of namespaces",
+			watchNamespaces: []string{"ns-1", "ns-2"},
+			verify: func(t *testing.T, opts deployment.RBACOptions) {
+				name := deployment.ClusterRoleName(nn)
+				if err := opts.KubeClient.Get(ctx, types.NamespacedName{Name: name}, &rbacv1.ClusterRole{}); err != nil {
+					t.Errorf("expected cluster role to be installed: %v", err)
+				}
+				for _, ns := range []string{"ns-1", "ns-2"} {
+					rb := rbacv1.RoleBinding{}
+					if err := opts.KubeClient.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, &rb); err != nil {
+						t.Fatalf("expected role binding in %s: %v", ns, err)
+					}
+					if rb.RoleRef.Kind != "ClusterRole" || rb.Subjects[0].Namespace != nn.Namespace ||
+						rb.Subjects[0].Name != deployment.ControllerNamespacedName(nn).Name {
+						t.Errorf("unexpected role binding %v", rb)
+					}
+				}
+			},
+		},
+		{
+			name: "All namespaces",
+			verify: func(t *testing.T, opts deployment.RBACOptions) {
+				name := deployment.ClusterRoleName(nn)
+				if err := opts.KubeClient.Get(ctx, types.NamespacedName{Name: name}, &rbacv1.ClusterRoleBinding{}); err != nil {
+					t.Errorf("expected cluster role binding to be installed: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := deployment.RBACOptions{
+				KubeClient:      fake.NewFakeClient(),
+				NamespacedName:  nn,
+				Rules:           rules,
+				WatchNamespaces: tc.watchNamespaces,
+			}
+
+			if _, err := deployment.ApplyRBAC(ctx, opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tc.verify(t, opts)
+
+			exists, upToDate, err := deployment.LookupRBAC(ctx, opts)
+			if err != nil || !exists || !upToDate {
+				t.Errorf("expected up to date RBAC, got exists=%v upToDate=%v err=%v", exists, upToDate, err)
+			}
+
+			if err := deployment.UninstallRBAC(ctx, opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = opts.KubeClient.Get(ctx, types.NamespacedName{Name: deployment.ClusterRoleName(nn)}, &rbacv1.ClusterRole{})
+			if !apierrors.IsNotFound(err) {
+				t.Errorf("expected cluster role to be removed, got %v", err)
+			}
+			err = opts.KubeClient.Get(ctx, deployment.ControllerNamespacedName(nn), &rbacv1.Role{})
+			if !apierrors.IsNotFound(err) {
+				t.Errorf("expected role to be removed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestApplyRBACSecrets(t *testing.T) {
+	ctx := context.TODO()
+	nn := types.NamespacedName{Namespace: "default", Name: "def-pet"}
+	opts := deployment.RBACOptions{
+		KubeClient:      fake.NewFakeClient(),
+		NamespacedName:  nn,
+		WatchNamespaces: []string{"ns-1", "ns-2"},
+		Secrets: map[string][]string{
+			"ns-1": {"token"},
+		},
+		ConnectionSecrets: map[string][]string{
+			"ns-1": {"connection"},
+			"ns-2": {"connection"},
+		},
+	}
+
+	if _, err := deployment.ApplyRBAC(ctx, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cr := rbacv1.ClusterRole{}
+	if err := opts.KubeClient.Get(ctx, types.NamespacedName{Name: deployment.ClusterRoleName(nn)}, &cr); err != nil {
+		t.Fatalf("expected cluster role to be installed: %v", err)
+	}
+	for _, rule := range cr.Rules {
+		if len(rule.ResourceNames) > 0 {
+			t.Errorf("expected no secret access in the cluster role, got %v", rule)
+		}
+	}
+
+	secrets := types.NamespacedName{Namespace: "ns-1", Name: naming.SecretsRoleName(nn)}
+	role := rbacv1.Role{}
+	if err := opts.KubeClient.Get(ctx, secrets, &role); err != nil {
+		t.Fatalf("expected secrets role in ns-1: %v", err)
+	}
+	if len(role.Rules) != 2 || !reflect.DeepEqual(role.Rules[0].ResourceNames, []string{"token"}) {
+		t.Fatalf("unexpected secrets role rules %v", role.Rules)
+	}
+	if !reflect.DeepEqual(role.Rules[1].ResourceNames, []string{"connection"}) || !slices.Contains(role.Rules[1].Verbs, "update") {
+		t.Errorf("expected write access to the connection secret, got %v", role.Rules[1])
+	}
+	if err := opts.KubeClient.Get(ctx, secrets, &rbacv1.RoleBinding{}); err != nil {
+		t.Errorf("expected secrets role binding in ns-1: %v", err)
+	}
+	role = rbacv1.Role{}
+	if err := opts.KubeClient.Get(ctx, types.NamespacedName{Namespace: "ns-2", Name: secrets.Name}, &role); err != nil {
+		t.Fatalf("expected secrets role in ns-2: %v", err)
+	}
+	if len(role.Rules) != 1 || slices.Contains(role.Rules[0].Verbs, "create") {
+		t.Errorf("unexpected secrets role rules %v", role.Rules)
+	}
+}
+
+func TestApplyRBACPrune(t *testing.T) {
+	ctx := context.TODO()
+	nn := types.NamespacedName{Namespace: "default", Name: "def-pet"}
+	provider := deployment.ProviderClusterRole(nn, nil)
+	opts := deployment.RBACOptions{
+		KubeClient:      fake.NewFakeClient(&provider),
+		NamespacedName:  nn,
+		WatchNamespaces: []string{"ns-1", "ns-2"},
+		Secrets: map[string][]string{
+			"ns-2": {"token"},
+		},
+	}
+	if _, err := deployment.ApplyRBAC(ctx, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	opts.WatchNamespaces = []string{"ns-1"}
+	opts.Secrets = nil
+	_, upToDate, err := deployment.LookupRBAC(ctx, opts)
+	if err != nil || upToDate {
+		t.Errorf("expected stale RBAC to be detected, got upToDate=%v err=%v", upToDate, err)
+	}
+
+	changed, err := deployment.ApplyRBAC(ctx, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changed {
+		t.Errorf("expected pruning to be reported as a change")
+	}
+
+	name := deployment.ClusterRoleName(nn)
+	if err := opts.KubeClient.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: name}, &rbacv1.RoleBinding{}); err != nil {
+		t.Errorf("expected role binding in ns-1 to be kept: %v", err)
+	}
+	err = opts.KubeClient.Get(ctx, types.NamespacedName{Namespace: "ns-2", Name: name}, &rbacv1.RoleBinding{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected role binding in ns-2 to be removed, got %v", err)
+	}
+	err = opts.KubeClient.Get(ctx, types.NamespacedName{Namespace: "ns-2", Name: naming.SecretsRoleName(nn)}, &rbacv1.Role{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected secrets role in ns-2 to be removed, got %v", err)
+	}
+	if err := opts.KubeClient.Get(ctx, client.ObjectKeyFromObject(&provider), &rbacv1.ClusterRole{}); err != nil {
+		t.Errorf("expected provider role to be kept: %v", err)
+	}
+
+	_, upToDate, err = deployment.LookupRBAC(ctx, opts)
+	if err != nil || !upToDate {
+		t.Errorf("expected up to date RBAC, got upToDate=%v err=%v", upToDate, err)
+	}
+}
+
+func TestLookupRBACBindings(t *testing.T) {
+	ctx := context.TODO()
+	nn := types.NamespacedName{Namespace: "default", Name: "def-pet"}
+	controller := deployment.ControllerNamespacedName(nn)
+	opts := deployment.RBACOptions{
+		KubeClient:      fake.NewFakeClient(),
+		NamespacedName:  nn,
+		WatchNamespaces: []string{"default"},
+	}
+
+	testCases := []struct {
+		name  string
+		drift func(t *testing.T)
+	}{
+		{
+			name: "Role binding subjects",
+			drift: func(t *testing.T) {
+				rb := rbacv1.RoleBinding{}
+				if err := opts.KubeClient.Get(ctx, controller, &rb); err != nil {
+					t.Fatal(err)
+				}
+				rb.Subjects[0].Name = "intruder"
+				if err := opts.KubeClient.Update(ctx, &rb); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "Role binding role",
+			drift: func(t *testing.T) {
+				rb := rbacv1.RoleBinding{}
+				if err := opts.KubeClient.Get(ctx, controller, &rb); err != nil {
+					t.Fatal(err)
+				}
+				rb.RoleRef.Kind = "ClusterRole"
+				if err := opts.KubeClient.Update(ctx, &rb); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "Service account",
+			drift: func(t *testing.T) {
+				sa := corev1.ServiceAccount{}
+				if err := opts.KubeClient.Get(ctx, controller, &sa); err != nil {
+					t.Fatal(err)
+				}
+				if err := opts.KubeClient.Delete(ctx, &sa); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := deployment.ApplyRBAC(ctx, opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tc.drift(t)
+
+			exists, upToDate, err := deployment.LookupRBAC(ctx, opts)
+			if err != nil || !exists || upToDate {
+				t.Errorf("expected drift to be detected, got exists=%v upToDate=%v err=%v", exists, upToDate, err)
+			}
+
+			if _, err := deployment.ApplyRBAC(ctx, opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, upToDate, err = deployment.LookupRBAC(ctx, opts)
+			if err != nil || !upToDate {
+				t.Errorf("expected RBAC to be restored, got upToDate=%v err=%v", upToDate, err)
+			}
+		})
+	}
+}
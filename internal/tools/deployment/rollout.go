@@ -0,0 +1,126 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ReasonProgressDeadlineExceeded is the reason set by the deployment
+	// controller when a rollout does not progress within progressDeadlineSeconds.
+	ReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+	// ReasonCrashLoopBackOff is the waiting reason of a container that keeps crashing.
+	ReasonCrashLoopBackOff = "CrashLoopBackOff"
+	// ReasonRolloutInProgress reports a rollout that is still progressing.
+	ReasonRolloutInProgress = "RolloutInProgress"
+)
+
+// Rollout describes the rollout of a Deployment.
+type Rollout struct {
+	// Done is true when the rollout completed.
+	Done bool
+	// Failed is true when the rollout will not complete without intervention.
+	Failed bool
+	// Reason is a CamelCase reason for a rollout that is not done.
+	Reason string
+	// Message is a human readable description of the rollout.
+	Message string
+}
+
+// RolloutStatus returns the rollout status of obj, using the same checks as
+// `kubectl rollout status`.
+func RolloutStatus(obj *appsv1.Deployment) Rollout {
+	if obj.Generation > obj.Status.ObservedGeneration {
+		return Rollout{
+			Reason:  ReasonRolloutInProgress,
+			Message: fmt.Sprintf("waiting for deployment %q spec update to be observed", obj.Name),
+		}
+	}
+
+	for _, c := range obj.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Reason == ReasonProgressDeadlineExceeded {
+			return Rollout{
+				Failed:  true,
+				Reason:  ReasonProgressDeadlineExceeded,
+				Message: fmt.Sprintf("deployment %q exceeded its progress deadline", obj.Name),
+			}
+		}
+	}
+
+	if obj.Spec.Replicas != nil && obj.Status.UpdatedReplicas < *obj.Spec.Replicas {
+		return Rollout{
+			Reason: ReasonRolloutInProgress,
+			Message: fmt.Sprintf("waiting for deployment %q rollout to finish: %d out of %d new replicas have been updated",
+				obj.Name, obj.Status.UpdatedReplicas, *obj.Spec.Replicas),
+		}
+	}
+	if obj.Status.Replicas > obj.Status.UpdatedReplicas {
+		return Rollout{
+			Reason: ReasonRolloutInProgress,
+			Message: fmt.Sprintf("waiting for deployment %q rollout to finish: %d old replicas are pending termination",
+				obj.Name, obj.Status.Replicas-obj.Status.UpdatedReplicas),
+		}
+	}
+	if obj.Status.AvailableReplicas < obj.Status.UpdatedReplicas {
+		return Rollout{
+			Reason: ReasonRolloutInProgress,
+			Message: fmt.Sprintf("waiting for deployment %q rollout to finish: %d of %d updated replicas are available",
+				obj.Name, obj.Status.AvailableReplicas, obj.Status.UpdatedReplicas),
+		}
+	}
+
+	return Rollout{Done: true, Message: fmt.Sprintf("deployment %q successfully rolled out", obj.Name)}
+}
+
+// LookupCrashLoop returns a failed Rollout when a container of the pods of obj
+// is in CrashLoopBackOff, nil otherwise. The message includes the termination
+// message of the last run of the container.
+func LookupCrashLoop(ctx context.Context, kube client.Reader, obj *appsv1.Deployment) (*Rollout, error) {
+	if obj.Spec.Selector == nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(obj.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("parsing deployment selector: %w", err)
+	}
+
+	pods := corev1.PodList{}
+	err = kube.List(ctx, &pods, client.InNamespace(obj.Namespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting == nil || cs.State.Waiting.Reason != ReasonCrashLoopBackOff {
+				continue
+			}
+
+			msg := fmt.Sprintf("container %q of pod %q is in CrashLoopBackOff (restarts: %d)", cs.Name, pod.Name, cs.RestartCount)
+			if term := cs.LastTerminationState.Terminated; term != nil {
+				details := []string{fmt.Sprintf("exit code %d", term.ExitCode)}
+				if len(term.Reason) > 0 {
+					details = append(details, term.Reason)
+				}
+				if len(term.Message) > 0 {
+					details = append(details, strings.TrimSpace(term.Message))
+				}
+				msg = fmt.Sprintf("%s: %s", msg, strings.Join(details, ", "))
+			}
+
+			return &Rollout{
+				Failed:  true,
+				Reason:  ReasonCrashLoopBackOff,
+				Message: msg,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,154 @@
+package deployment_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRolloutStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		obj    appsv1.Deployment
+		done   bool
+		failed bool
+		reason string
+	}{
+		{
+			name: "spec update not observed",
+			obj: appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1},
+			},
+			reason: deployment.ReasonRolloutInProgress,
+		},
+		{
+			name: "progress deadline exceeded",
+			obj: appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(1))},
+				Status: appsv1.DeploymentStatus{
+					Conditions: []appsv1.DeploymentCondition{
+						{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: deployment.ReasonProgressDeadlineExceeded},
+					},
+				},
+			},
+			failed: true,
+			reason: deployment.ReasonProgressDeadlineExceeded,
+		},
+		{
+			name: "old replicas still ready",
+			obj: appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: ptr.To(int32(1))},
+				Status: appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 1, ReadyReplicas: 1, AvailableReplicas: 1},
+			},
+			reason: deployment.ReasonRolloutInProgress,
+		},
+		{
+			name: "updated replicas not available",
+			obj: appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: ptr.To(int32(1))},
+				Status: appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1},
+			},
+			reason: deployment.ReasonRolloutInProgress,
+		},
+		{
+			name: "rolled out",
+			obj: appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 1},
+				Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(1))},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1, ReadyReplicas: 1, AvailableReplicas: 1},
+			},
+			done: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := deployment.RolloutStatus(&tc.obj)
+			if got.Done != tc.done || got.Failed != tc.failed || got.Reason != tc.reason {
+				t.Errorf("unexpected rollout status: %+v", got)
+			}
+		})
+	}
+}
+
+func TestLookupCrashLoop(t *testing.T) {
+	ctx := context.TODO()
+
+	obj := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-controller", Namespace: "demo"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test-controller"}},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-controller-abc", Namespace: "demo", Labels: map[string]string{"app": "test-controller"}},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:         "test-controller",
+					RestartCount: 4,
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: deployment.ReasonCrashLoopBackOff},
+					},
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error", Message: "unable to load kubeconfig\n"},
+					},
+				},
+			},
+		},
+	}
+	other := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "demo", Labels: map[string]string{"app": "other"}},
+	}
+
+	kube := fake.NewClientBuilder().WithObjects(pod, other).Build()
+
+	got, err := deployment.LookupCrashLoop(ctx, kube, obj)
+	if err != nil {
+		t.Fatalf("failed to lookup crash loop: %v", err)
+	}
+	if got == nil || !got.Failed || got.Reason != deployment.ReasonCrashLoopBackOff {
+		t.Fatalf("expected a crash loop, got %+v", got)
+	}
+	if !strings.Contains(got.Message, "unable to load kubeconfig") || !strings.Contains(got.Message, "exit code 1") {
+		t.Errorf("expected termination details in message, got %q", got.Message)
+	}
+
+	kube = fake.NewClientBuilder().WithObjects(other).Build()
+	got, err = deployment.LookupCrashLoop(ctx, kube, obj)
+	if err != nil {
+		t.Fatalf("failed to lookup crash loop: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected no crash loop, got %+v", got)
+	}
+}
+
+func TestCRDEstablished(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if deployment.CRDEstablished(crd) {
+		t.Errorf("expected CRD without conditions not to be established")
+	}
+
+	crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{
+		{Type: apiextensionsv1.NamesAccepted, Status: apiextensionsv1.ConditionTrue},
+		{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionFalse, Reason: "Installing"},
+	}
+	if deployment.CRDEstablished(crd) {
+		t.Errorf("expected CRD not to be established")
+	}
+
+	crd.Status.Conditions[1].Status = apiextensionsv1.ConditionTrue
+	if !deployment.CRDEstablished(crd) {
+		t.Errorf("expected CRD to be established")
+	}
+}
@@ -27,8 +27,16 @@ type AuthConfig struct {
 
 // GetFile gets a file from a source and writes it to a destination.
 func GetFile(dst string, src string, auth *AuthConfig) error {
+	_, _, err := GetFileIfModified(dst, src, auth, "")
+	return err
+}
+
+// GetFileIfModified gets a file from a source and writes it to a destination.
+// When the source is a URL and etag is not empty, the request is conditional: if the
+// server replies 304 Not Modified, dst is not written and modified is false.
+// The returned newETag is the entity tag of the source, if any.
+func GetFileIfModified(dst string, src string, auth *AuthConfig, etag string) (newETag string, modified bool, err error) {
 	var reader io.Reader
-	var err error
 
 	// Check if the source is a URL or a local file
 	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
@@ -38,7 +46,10 @@ func GetFile(dst string, src string, auth *AuthConfig) error {
 		// Create a new request
 		req, err := http.NewRequest("GET", src, nil)
 		if err != nil {
-			return fmt.Errorf("error creating request: %v", err)
+			return "", false, fmt.Errorf("error creating request: %v", err)
+		}
+		if len(etag) > 0 {
+			req.Header.Set("If-None-Match", etag)
 		}
 
 		// Add authentication if provided
@@ -54,20 +65,24 @@ func GetFile(dst string, src string, auth *AuthConfig) error {
 		// Send the request
 		resp, err := client.Do(req)
 		if err != nil {
-			return fmt.Errorf("error downloading file: %v", err)
+			return "", false, fmt.Errorf("error downloading file: %v", err)
 		}
 		defer resp.Body.Close()
 
+		if len(etag) > 0 && resp.StatusCode == http.StatusNotModified {
+			return etag, false, nil
+		}
 		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+			return "", false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 		}
 
+		newETag = resp.Header.Get("ETag")
 		reader = resp.Body
 	} else {
 		// Open local file
 		file, err := os.Open(src)
 		if err != nil {
-			return fmt.Errorf("error opening local file: %v - %s", err, src)
+			return "", false, fmt.Errorf("error opening local file: %v - %s", err, src)
 		}
 		defer file.Close()
 		reader = file
@@ -76,15 +91,15 @@ func GetFile(dst string, src string, auth *AuthConfig) error {
 	// Create the destination file
 	dstFile, err := os.Create(dst)
 	if err != nil {
-		return fmt.Errorf("error creating destination file: %v", err)
+		return "", false, fmt.Errorf("error creating destination file: %v", err)
 	}
 	defer dstFile.Close()
 
 	// Copy the contents
 	_, err = io.Copy(dstFile, reader)
 	if err != nil {
-		return fmt.Errorf("error writing to destination file: %v", err)
+		return "", false, fmt.Errorf("error writing to destination file: %v", err)
 	}
 
-	return nil
+	return newETag, true, nil
 }
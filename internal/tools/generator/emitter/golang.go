@@ -0,0 +1,339 @@
+// Package emitter generates source code for the kinds generated from a RestDefinition,
+// on top of the structs produced by the transpiler.
+package emitter
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/transpiler"
+)
+
+// File is a generated source file.
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// EmitGo returns the Go source files of the kind: the types and their scheme
+// registration, the deepcopy functions and a typed controller-runtime client.
+func EmitGo(k *Kind, pkg string) ([]File, error) {
+	base := strings.ToLower(k.GVK.Kind)
+
+	emitters := []struct {
+		name string
+		fn   func(w *writer, k *Kind)
+	}{
+		{name: base + "_types.go", fn: emitTypes},
+		{name: "zz_generated." + base + ".deepcopy.go", fn: emitDeepCopy},
+		{name: base + "_client.go", fn: emitClient},
+	}
+
+	res := make([]File, 0, len(emitters))
+	for _, e := range emitters {
+		w := &writer{}
+		w.line("// Code generated by oasgen. DO NOT EDIT.")
+		w.line("")
+		w.line("package %s", pkg)
+		w.line("")
+		e.fn(w, k)
+
+		src, err := format.Source(w.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("formatting %s: %w", e.name, err)
+		}
+		res = append(res, File{Name: e.name, Content: src})
+	}
+	return res, nil
+}
+
+type writer struct {
+	bytes.Buffer
+}
+
+func (w *writer) line(format string, a ...any) {
+	fmt.Fprintf(w, format, a...)
+	w.WriteByte('\n')
+}
+
+func (w *writer) comment(text string) {
+	for _, l := range strings.Split(strings.TrimSpace(text), "\n") {
+		w.line("// %s", strings.TrimSpace(l))
+	}
+}
+
+func sortedFields(s transpiler.Struct) []transpiler.Field {
+	res := make([]transpiler.Field, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		res = append(res, f)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].JSONName < res[j].JSONName
+	})
+	return res
+}
+
+func emitTypes(w *writer, k *Kind) {
+	w.line("import (")
+	w.line("\tmetav1 \"k8s.io/apimachinery/pkg/apis/meta/v1\"")
+	w.line("\t\"k8s.io/apimachinery/pkg/runtime\"")
+	w.line("\t\"k8s.io/apimachinery/pkg/runtime/schema\"")
+	w.line(")")
+	w.line("")
+
+	kind := k.GVK.Kind
+	w.line("// %sGroupVersionKind is the GroupVersionKind of %s.", kind, kind)
+	w.line("var %sGroupVersionKind = schema.GroupVersionKind{Group: %q, Version: %q, Kind: %q}", kind, k.GVK.Group, k.GVK.Version, kind)
+	w.line("")
+	w.line("// %sGroupVersionResource is the GroupVersionResource of %s.", kind, kind)
+	w.line("var %sGroupVersionResource = schema.GroupVersionResource{Group: %q, Version: %q, Resource: %q}", kind, k.GVK.Group, k.GVK.Version, k.Plural)
+	w.line("")
+	w.line("// Add%sToScheme registers %s and %s in the scheme.", kind, kind, k.ListName())
+	w.line("func Add%sToScheme(s *runtime.Scheme) error {", kind)
+	w.line("\tgv := %sGroupVersionKind.GroupVersion()", kind)
+	w.line("\ts.AddKnownTypes(gv, &%s{}, &%s{})", kind, k.ListName())
+	w.line("\tmetav1.AddToGroupVersion(s, gv)")
+	w.line("\treturn nil")
+	w.line("}")
+	w.line("")
+
+	w.line("// %s is a %s resource.", kind, k.Plural)
+	w.line("type %s struct {", kind)
+	w.line("\tmetav1.TypeMeta   `json:\",inline\"`")
+	w.line("\tmetav1.ObjectMeta `json:\"metadata,omitempty\"`")
+	w.line("")
+	w.line("\tSpec %s `json:\"spec,omitempty\"`", k.Spec)
+	if len(k.Status) > 0 {
+		w.line("\tStatus *%s `json:\"status,omitempty\"`", k.Status)
+	}
+	w.line("}")
+	w.line("")
+	w.line("// %s is a list of %s.", k.ListName(), kind)
+	w.line("type %s struct {", k.ListName())
+	w.line("\tmetav1.TypeMeta `json:\",inline\"`")
+	w.line("\tmetav1.ListMeta `json:\"metadata,omitempty\"`")
+	w.line("\tItems []%s `json:\"items\"`", kind)
+	w.line("}")
+
+	for _, s := range k.SortedStructs() {
+		w.line("")
+		if len(s.Description) > 0 {
+			w.comment(s.Description)
+		} else {
+			w.line("// %s is generated from the CRD schema.", s.Name)
+		}
+		w.line("type %s struct {", s.Name)
+		for _, f := range sortedFields(s) {
+			if len(f.Description) > 0 {
+				w.comment(f.Description)
+			}
+			tag := f.JSONName
+			if !f.Required {
+				tag += ",omitempty"
+			}
+			w.line("\t%s %s `json:%q`", f.Name, f.Type, tag)
+		}
+		w.line("}")
+	}
+}
+
+func emitDeepCopy(w *writer, k *Kind) {
+	w.line("import \"k8s.io/apimachinery/pkg/runtime\"")
+	w.line("")
+
+	kind := k.GVK.Kind
+	w.line("// DeepCopyInto copies the receiver into out.")
+	w.line("func (in *%s) DeepCopyInto(out *%s) {", kind, kind)
+	w.line("\t*out = *in")
+	w.line("\tout.TypeMeta = in.TypeMeta")
+	w.line("\tin.ObjectMeta.DeepCopyInto(&out.ObjectMeta)")
+	w.line("\tin.Spec.DeepCopyInto(&out.Spec)")
+	if len(k.Status) > 0 {
+		copyValue(w, "*"+k.Status, "in.Status", "out.Status", 0)
+	}
+	w.line("}")
+	emitDeepCopyFuncs(w, kind, true)
+
+	w.line("// DeepCopyInto copies the receiver into out.")
+	w.line("func (in *%s) DeepCopyInto(out *%s) {", k.ListName(), k.ListName())
+	w.line("\t*out = *in")
+	w.line("\tout.TypeMeta = in.TypeMeta")
+	w.line("\tin.ListMeta.DeepCopyInto(&out.ListMeta)")
+	copyValue(w, "[]"+kind, "in.Items", "out.Items", 0)
+	w.line("}")
+	emitDeepCopyFuncs(w, k.ListName(), true)
+
+	for _, s := range k.SortedStructs() {
+		w.line("// DeepCopyInto copies the receiver into out.")
+		w.line("func (in *%s) DeepCopyInto(out *%s) {", s.Name, s.Name)
+		w.line("\t*out = *in")
+		for _, f := range sortedFields(s) {
+			if isPrimitive(f.Type) {
+				continue
+			}
+			copyValue(w, f.Type, "in."+f.Name, "out."+f.Name, 0)
+		}
+		w.line("}")
+		emitDeepCopyFuncs(w, s.Name, false)
+	}
+}
+
+func emitDeepCopyFuncs(w *writer, name string, object bool) {
+	w.line("")
+	w.line("// DeepCopy returns a deep copy of the receiver.")
+	w.line("func (in *%s) DeepCopy() *%s {", name, name)
+	w.line("\tif in == nil {")
+	w.line("\t\treturn nil")
+	w.line("\t}")
+	w.line("\tout := new(%s)", name)
+	w.line("\tin.DeepCopyInto(out)")
+	w.line("\treturn out")
+	w.line("}")
+	w.line("")
+	if object {
+		w.line("// DeepCopyObject returns a deep copy of the receiver as a runtime.Object.")
+		w.line("func (in *%s) DeepCopyObject() runtime.Object {", name)
+		w.line("\tif c := in.DeepCopy(); c != nil {")
+		w.line("\t\treturn c")
+		w.line("\t}")
+		w.line("\treturn nil")
+		w.line("}")
+		w.line("")
+	}
+}
+
+func isPrimitive(t string) bool {
+	switch t {
+	case "string", "bool", "int64", "float64":
+		return true
+	}
+	return false
+}
+
+// copyValue emits the statements assigning a deep copy of in, of type t, to out.
+func copyValue(w *writer, t string, in, out string, depth int) {
+	switch {
+	case isPrimitive(t):
+		w.line("%s = %s", out, in)
+	case strings.HasPrefix(t, "*"):
+		w.line("if %s != nil {", in)
+		w.line("%s = new(%s)", out, t[1:])
+		w.line("%s.DeepCopyInto(%s)", in, out)
+		w.line("}")
+	case strings.HasPrefix(t, "[]"):
+		elem := t[2:]
+		w.line("if %s != nil {", in)
+		w.line("%s = make(%s, len(%s))", out, t, in)
+		if isPrimitive(elem) {
+			w.line("copy(%s, %s)", out, in)
+		} else {
+			i := fmt.Sprintf("i%d", depth)
+			w.line("for %s := range %s {", i, in)
+			copyValue(w, elem, fmt.Sprintf("%s[%s]", in, i), fmt.Sprintf("%s[%s]", out, i), depth+1)
+			w.line("}")
+		}
+		w.line("}")
+	case strings.HasPrefix(t, "map[string]"):
+		elem := strings.TrimPrefix(t, "map[string]")
+		key, val := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth)
+		w.line("if %s != nil {", in)
+		w.line("%s = make(%s, len(%s))", out, t, in)
+		w.line("for %s, %s := range %s {", key, val, in)
+		if isPrimitive(elem) {
+			w.line("%s[%s] = %s", out, key, val)
+		} else {
+			tmp := fmt.Sprintf("c%d", depth)
+			w.line("var %s %s", tmp, elem)
+			copyValue(w, elem, val, tmp, depth+1)
+			w.line("%s[%s] = %s", out, key, tmp)
+		}
+		w.line("}")
+		w.line("}")
+	default:
+		// Structs and runtime.RawExtension values.
+		w.line("%s.DeepCopyInto(&%s)", in, out)
+	}
+}
+
+func emitClient(w *writer, k *Kind) {
+	w.line("import (")
+	w.line("\t\"context\"")
+	w.line("")
+	w.line("\t\"k8s.io/apimachinery/pkg/types\"")
+	w.line("\t\"sigs.k8s.io/controller-runtime/pkg/client\"")
+	w.line(")")
+	w.line("")
+
+	kind, list, name := k.GVK.Kind, k.ListName(), k.ClientName()
+	w.line("// %s reads and writes %s resources.", name, kind)
+	w.line("// The scheme of the underlying client must include Add%sToScheme.", kind)
+	w.line("type %s struct {", name)
+	w.line("\tclient    client.Client")
+	w.line("\tnamespace string")
+	w.line("}")
+	w.line("")
+	if k.Namespaced {
+		w.line("// New%s returns a client of the %s resources of namespace.", name, kind)
+		w.line("func New%s(c client.Client, namespace string) *%s {", name, name)
+		w.line("\treturn &%s{client: c, namespace: namespace}", name)
+	} else {
+		w.line("// New%s returns a client of the %s resources.", name, kind)
+		w.line("func New%s(c client.Client) *%s {", name, name)
+		w.line("\treturn &%s{client: c}", name)
+	}
+	w.line("}")
+	w.line("")
+
+	w.line("// Get returns the %s named name.", kind)
+	w.line("func (c *%s) Get(ctx context.Context, name string) (*%s, error) {", name, kind)
+	w.line("\tobj := &%s{}", kind)
+	w.line("\terr := c.client.Get(ctx, types.NamespacedName{Namespace: c.namespace, Name: name}, obj)")
+	w.line("\treturn obj, err")
+	w.line("}")
+	w.line("")
+	w.line("// List returns the %s resources.", kind)
+	w.line("func (c *%s) List(ctx context.Context, opts ...client.ListOption) (*%s, error) {", name, list)
+	w.line("\tobj := &%s{}", list)
+	w.line("\tif len(c.namespace) > 0 {")
+	w.line("\t\topts = append(opts, client.InNamespace(c.namespace))")
+	w.line("\t}")
+	w.line("\terr := c.client.List(ctx, obj, opts...)")
+	w.line("\treturn obj, err")
+	w.line("}")
+	w.line("")
+	w.line("// Create creates obj.")
+	w.line("func (c *%s) Create(ctx context.Context, obj *%s, opts ...client.CreateOption) error {", name, kind)
+	w.line("\tc.prepare(obj)")
+	w.line("\treturn c.client.Create(ctx, obj, opts...)")
+	w.line("}")
+	w.line("")
+	w.line("// Update updates obj.")
+	w.line("func (c *%s) Update(ctx context.Context, obj *%s, opts ...client.UpdateOption) error {", name, kind)
+	w.line("\tc.prepare(obj)")
+	w.line("\treturn c.client.Update(ctx, obj, opts...)")
+	w.line("}")
+	w.line("")
+	if len(k.Status) > 0 {
+		w.line("// UpdateStatus updates the status of obj.")
+		w.line("func (c *%s) UpdateStatus(ctx context.Context, obj *%s, opts ...client.SubResourceUpdateOption) error {", name, kind)
+		w.line("\tc.prepare(obj)")
+		w.line("\treturn c.client.Status().Update(ctx, obj, opts...)")
+		w.line("}")
+		w.line("")
+	}
+	w.line("// Delete deletes obj.")
+	w.line("func (c *%s) Delete(ctx context.Context, obj *%s, opts ...client.DeleteOption) error {", name, kind)
+	w.line("\tc.prepare(obj)")
+	w.line("\treturn c.client.Delete(ctx, obj, opts...)")
+	w.line("}")
+	w.line("")
+	w.line("func (c *%s) prepare(obj *%s) {", name, kind)
+	w.line("\tobj.SetGroupVersionKind(%sGroupVersionKind)", kind)
+	w.line("\tif len(obj.Namespace) == 0 {")
+	w.line("\t\tobj.Namespace = c.namespace")
+	w.line("\t}")
+	w.line("}")
+}
@@ -0,0 +1,169 @@
+package emitter_test
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/crds"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/emitter"
+)
+
+func loadKind(t *testing.T) *emitter.Kind {
+	t.Helper()
+	return loadKindFile(t, "testdata/pets.yaml")
+}
+
+func loadKindFile(t *testing.T, path string) *emitter.Kind {
+	t.Helper()
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	crd, err := crds.UnmarshalCRD(dat)
+	if err != nil {
+		t.Fatalf("failed to unmarshal CRD: %v", err)
+	}
+	k, err := emitter.FromCRD(crd, "v1alpha1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return k
+}
+
+func TestFromCRD(t *testing.T) {
+	k := loadKind(t)
+
+	if k.Spec != "PetSpec" || k.Status != "PetStatus" || !k.Namespaced || k.Plural != "pets" {
+		t.Errorf("unexpected kind: %+v", k)
+	}
+
+	expected := map[string]map[string]string{
+		"PetSpec": {
+			"name": "string", "age": "int64", "weight": "float64", "vaccinated": "bool",
+			"photoUrls": "[]string", "category": "*PetCategory", "tags": "[]*PetTagsItems",
+			"labels": "map[string]string", "extra": "*runtime.RawExtension",
+			"authenticationRefs": "*PetAuthenticationRefs",
+		},
+		// category has the same schema in spec and status: it is shared.
+		"PetStatus": {
+			"id": "int64", "category": "*PetCategory", "tags": "[]*PetStatusTagsItems",
+			"conditions": "[]*PetConditionsItems",
+		},
+	}
+	for name, fields := range expected {
+		s, ok := k.Structs[name]
+		if !ok {
+			t.Fatalf("expected struct %s, got %v", name, k.Structs)
+		}
+		for json, typ := range fields {
+			found := false
+			for _, f := range s.Fields {
+				if f.JSONName == json {
+					found = true
+					if f.Type != typ {
+						t.Errorf("%s.%s: expected type %s, got %s", name, json, typ, f.Type)
+					}
+				}
+			}
+			if !found {
+				t.Errorf("%s: expected field %s", name, json)
+			}
+		}
+	}
+	for _, name := range []string{"PetCategory", "PetTagsItems", "PetStatusTagsItems", "PetConditionsItems"} {
+		if _, ok := k.Structs[name]; !ok {
+			t.Errorf("expected struct %s", name)
+		}
+	}
+}
+
+func TestEmitGo(t *testing.T) {
+	k := loadKind(t)
+
+	files, err := emitter.EmitGo(k, "petstore")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(files))
+	}
+
+	fset := token.NewFileSet()
+	content := map[string]string{}
+	for _, f := range files {
+		if _, err := parser.ParseFile(fset, filepath.Join("petstore", f.Name), f.Content, parser.AllErrors); err != nil {
+			t.Errorf("%s does not parse: %v", f.Name, err)
+		}
+		content[f.Name] = string(f.Content)
+	}
+
+	checks := map[string][]string{
+		"pet_types.go": {
+			"package petstore",
+			"type Pet struct {",
+			"Status *PetStatus `json:\"status,omitempty\"`",
+			"`json:\"name\"`",
+			"// The name of the pet.",
+			"func AddPetToScheme(s *runtime.Scheme) error {",
+		},
+		"zz_generated.pet.deepcopy.go": {
+			"func (in *Pet) DeepCopyObject() runtime.Object {",
+			"func (in *PetList) DeepCopyObject() runtime.Object {",
+			"func (in *PetCategory) DeepCopy() *PetCategory {",
+			"copy(out.PhotoUrls, in.PhotoUrls)",
+		},
+		"pet_client.go": {
+			"func NewPetClient(c client.Client, namespace string) *PetClient {",
+			"func (c *PetClient) UpdateStatus(",
+		},
+	}
+	for name, snippets := range checks {
+		for _, s := range snippets {
+			if !strings.Contains(content[name], s) {
+				t.Errorf("expected %s to contain %q", name, s)
+			}
+		}
+	}
+}
+
+// buildPackage writes files in a new package of the module and builds it with go
+// vet, which also type-checks it. The directory name starts with '_', so that
+// the package is ignored by the patterns of the go tool.
+func buildPackage(t *testing.T, files []emitter.File) {
+	t.Helper()
+	dir, err := os.MkdirTemp(".", "_emitted")
+	if err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f.Name), f.Content, 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", f.Name, err)
+		}
+	}
+	out, err := exec.Command("go", "vet", "./"+dir).CombinedOutput()
+	if err != nil {
+		t.Errorf("emitted package does not build: %v\n%s", err, out)
+	}
+}
+
+func TestEmitGoBuilds(t *testing.T) {
+	files := []emitter.File{}
+	for _, path := range []string{"testdata/pets.yaml", "testdata/stores.yaml"} {
+		res, err := emitter.EmitGo(loadKindFile(t, path), "petstore")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		files = append(files, res...)
+	}
+	if len(files) != 6 {
+		t.Fatalf("expected 6 files, got %d", len(files))
+	}
+	buildPackage(t, files)
+}
@@ -0,0 +1,256 @@
+package emitter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/transpiler"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/transpiler/jsonschema"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Kind is the model of a generated kind: its spec and status structs, and the
+// nested structs they reference.
+type Kind struct {
+	GVK        schema.GroupVersionKind
+	Plural     string
+	Namespaced bool
+	// Spec and Status are the names of the root structs. Status is empty when
+	// the CRD has no status.
+	Spec   string
+	Status string
+	// Structs by name, with the types of their fields rewritten for Go.
+	Structs map[string]transpiler.Struct
+}
+
+// ListName returns the name of the list type of the kind.
+func (k *Kind) ListName() string {
+	return k.GVK.Kind + "List"
+}
+
+// ClientName returns the name of the typed client of the kind.
+func (k *Kind) ClientName() string {
+	return k.GVK.Kind + "Client"
+}
+
+// SortedStructs returns the structs sorted by name.
+func (k *Kind) SortedStructs() []transpiler.Struct {
+	res := make([]transpiler.Struct, 0, len(k.Structs))
+	for _, s := range k.Structs {
+		res = append(res, s)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// FromCRD transpiles the spec and status schemas of version of the CRD.
+func FromCRD(crd *apiextensionsv1.CustomResourceDefinition, version string) (*Kind, error) {
+	var root *apiextensionsv1.JSONSchemaProps
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version && v.Schema != nil {
+			root = v.Schema.OpenAPIV3Schema
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("version %s not found in CRD %s", version, crd.Name)
+	}
+
+	kind := crd.Spec.Names.Kind
+	res := &Kind{
+		GVK: schema.GroupVersionKind{
+			Group:   crd.Spec.Group,
+			Version: version,
+			Kind:    kind,
+		},
+		Plural:     crd.Spec.Names.Plural,
+		Namespaced: crd.Spec.Scope != apiextensionsv1.ClusterScoped,
+		Spec:       kind + "Spec",
+		Structs:    map[string]transpiler.Struct{},
+	}
+
+	spec := root.Properties["spec"]
+	specStructs, err := transpile(res.Spec, &spec)
+	if err != nil {
+		return nil, fmt.Errorf("transpiling spec: %w", err)
+	}
+
+	statusStructs := map[string]transpiler.Struct{}
+	if status, ok := root.Properties["status"]; ok {
+		res.Status = kind + "Status"
+		statusStructs, err = transpile(res.Status, &status)
+		if err != nil {
+			return nil, fmt.Errorf("transpiling status: %w", err)
+		}
+	}
+
+	reserved := map[string]bool{
+		kind: true, res.ListName(): true, res.ClientName(): true, res.Spec: true, res.Status: true,
+	}
+
+	specNames := names(specStructs, res.Spec, kind, res.Spec, func(candidate string, _ transpiler.Struct) bool {
+		return reserved[candidate]
+	})
+	specByName := map[string]transpiler.Struct{}
+	for old, s := range specStructs {
+		specByName[specNames[old]] = s
+		s.Name = specNames[old]
+		s.Fields = rewriteFields(s.Fields, specNames)
+		res.Structs[s.Name] = s
+	}
+
+	// Nested status structs equal to a spec one are shared with the spec.
+	statusNames := names(statusStructs, res.Status, kind, res.Status, func(candidate string, s transpiler.Struct) bool {
+		if reserved[candidate] {
+			return true
+		}
+		other, ok := specByName[candidate]
+		return ok && !sameFields(other.Fields, s.Fields)
+	})
+	for old, s := range statusStructs {
+		name := statusNames[old]
+		if _, ok := res.Structs[name]; ok {
+			continue
+		}
+		s.Name = name
+		s.Fields = rewriteFields(s.Fields, statusNames)
+		res.Structs[name] = s
+	}
+
+	if _, ok := res.Structs[res.Spec]; !ok {
+		return nil, fmt.Errorf("spec of %s is not an object", kind)
+	}
+	if len(res.Status) > 0 {
+		if _, ok := res.Structs[res.Status]; !ok {
+			return nil, fmt.Errorf("status of %s is not an object", kind)
+		}
+	}
+
+	return res, nil
+}
+
+// transpile converts the schema to the transpiler model. The root struct is named root.
+func transpile(root string, props *apiextensionsv1.JSONSchemaProps) (map[string]transpiler.Struct, error) {
+	return transpileSchema(root, fromJSONSchemaProps(props))
+}
+
+func transpileSchema(root string, sch *jsonschema.Schema) (map[string]transpiler.Struct, error) {
+	sch.Title = root
+	sch.Init()
+	return transpiler.Transpile(sch)
+}
+
+// names returns the Go name of each struct but root: nested structs are prefixed
+// with the kind, or with prefix when the name conflicts.
+func names(structs map[string]transpiler.Struct, root, kind, prefix string, conflict func(string, transpiler.Struct) bool) map[string]string {
+	res := map[string]string{}
+	for name, s := range structs {
+		if name == root {
+			res[name] = name
+			continue
+		}
+
+		candidate := name
+		if !strings.HasPrefix(candidate, kind) {
+			candidate = kind + candidate
+		}
+		if conflict(candidate, s) {
+			candidate = prefix + strings.TrimPrefix(candidate, kind)
+		}
+		res[name] = candidate
+	}
+	return res
+}
+
+func sameFields(a, b map[string]transpiler.Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, fa := range a {
+		fb, ok := b[k]
+		if !ok || fa.JSONName != fb.JSONName || fa.Type != fb.Type || fa.Required != fb.Required {
+			return false
+		}
+	}
+	return true
+}
+
+func rewriteFields(fields map[string]transpiler.Field, names map[string]string) map[string]transpiler.Field {
+	res := make(map[string]transpiler.Field, len(fields))
+	for k, f := range fields {
+		// Additional properties of objects having properties too are not supported.
+		if f.JSONName == "-" {
+			continue
+		}
+		f.Type = goType(f.Type, names, true)
+		res[k] = f
+	}
+	return res
+}
+
+// goType rewrites a transpiler type: structs are renamed, integers are int64 and
+// untyped values are kept as raw JSON.
+func goType(t string, names map[string]string, field bool) string {
+	switch {
+	case strings.HasPrefix(t, "[]"):
+		return "[]" + goType(strings.TrimPrefix(t, "[]"), names, false)
+	case strings.HasPrefix(t, "map[string]"):
+		return "map[string]" + goType(strings.TrimPrefix(t, "map[string]"), names, false)
+	case strings.HasPrefix(t, "*"):
+		return "*" + goType(strings.TrimPrefix(t, "*"), names, false)
+	case t == "int":
+		return "int64"
+	case t == "interface{}" || t == "nil":
+		if field {
+			return "*runtime.RawExtension"
+		}
+		return "runtime.RawExtension"
+	}
+	if n, ok := names[t]; ok {
+		return n
+	}
+	switch t {
+	case "string", "bool", "float64":
+		return t
+	}
+	// Objects allowing any additional property are not emitted as structs.
+	return "runtime.RawExtension"
+}
+
+// fromJSONSchemaProps converts a CRD schema to the JSON schema model of the transpiler.
+// Objects preserving unknown fields without properties are left untyped.
+func fromJSONSchemaProps(props *apiextensionsv1.JSONSchemaProps) *jsonschema.Schema {
+	res := &jsonschema.Schema{
+		Title:       props.Title,
+		Description: props.Description,
+		Required:    props.Required,
+	}
+	if len(props.Type) > 0 {
+		res.TypeValue = props.Type
+	}
+	if props.XPreserveUnknownFields != nil && *props.XPreserveUnknownFields && len(props.Properties) == 0 {
+		res.TypeValue = nil
+	}
+
+	if len(props.Properties) > 0 {
+		res.Properties = make(map[string]*jsonschema.Schema, len(props.Properties))
+		for k, p := range props.Properties {
+			res.Properties[k] = fromJSONSchemaProps(&p)
+		}
+	}
+	if props.Items != nil && props.Items.Schema != nil {
+		res.Items = fromJSONSchemaProps(props.Items.Schema)
+	}
+	if props.AdditionalProperties != nil {
+		if props.AdditionalProperties.Schema != nil {
+			res.AdditionalProperties = (*jsonschema.AdditionalProperties)(fromJSONSchemaProps(props.AdditionalProperties.Schema))
+		} else {
+			allows := props.AdditionalProperties.Allows
+			res.AdditionalProperties = &jsonschema.AdditionalProperties{AdditionalPropertiesBool: &allows}
+		}
+	}
+	return res
+}
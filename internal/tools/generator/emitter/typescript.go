@@ -0,0 +1,173 @@
+package emitter
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/transpiler"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/transpiler/jsonschema"
+)
+
+var identifierRE = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypeScriptRoot is a JSON schema emitted as a root TypeScript interface.
+type TypeScriptRoot struct {
+	// Kind prefixes the names of the nested interfaces.
+	Kind string
+	// Name of the root interface, i.e. PetSpec.
+	Name   string
+	Schema []byte
+}
+
+// EmitTypeScript returns the TypeScript definitions of the roots: an interface
+// for each root, followed by the nested interfaces. Nested interfaces equal
+// across roots of the same kind are shared.
+func EmitTypeScript(roots ...TypeScriptRoot) ([]byte, error) {
+	reserved := map[string]bool{}
+	for _, root := range roots {
+		reserved[root.Name] = true
+	}
+
+	// Structs by name, before and after the rewrite of the field types.
+	raw := map[string]transpiler.Struct{}
+	all := map[string]transpiler.Struct{}
+	for _, root := range roots {
+		sch, err := jsonschema.Parse(root.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("parsing schema of %s: %w", root.Name, err)
+		}
+		structs, err := transpileSchema(root.Name, sch)
+		if err != nil {
+			return nil, fmt.Errorf("transpiling %s: %w", root.Name, err)
+		}
+		if _, ok := structs[root.Name]; !ok {
+			return nil, fmt.Errorf("%s is not an object", root.Name)
+		}
+
+		names := names(structs, root.Name, root.Kind, root.Name, func(candidate string, s transpiler.Struct) bool {
+			if candidate != root.Name && reserved[candidate] {
+				return true
+			}
+			other, ok := raw[candidate]
+			return ok && !sameFields(other.Fields, s.Fields)
+		})
+		for old, s := range structs {
+			name := names[old]
+			if _, ok := all[name]; ok {
+				continue
+			}
+			raw[name] = s
+			s.Name = name
+			s.Fields = tsFields(s.Fields, names)
+			all[name] = s
+		}
+	}
+
+	nested := make([]string, 0, len(all))
+	for name := range all {
+		if !reserved[name] {
+			nested = append(nested, name)
+		}
+	}
+	sort.Strings(nested)
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by oasgen. DO NOT EDIT.\n")
+	for _, root := range roots {
+		writeInterface(buf, all[root.Name])
+	}
+	for _, name := range nested {
+		writeInterface(buf, all[name])
+	}
+	return buf.Bytes(), nil
+}
+
+func tsFields(fields map[string]transpiler.Field, names map[string]string) map[string]transpiler.Field {
+	res := make(map[string]transpiler.Field, len(fields))
+	for k, f := range fields {
+		if f.JSONName == "-" {
+			continue
+		}
+		f.Type = tsType(f.Type, names)
+		res[k] = f
+	}
+	return res
+}
+
+// tsType converts a transpiler type to TypeScript.
+func tsType(t string, names map[string]string) string {
+	switch {
+	case strings.HasPrefix(t, "[]"):
+		elem := tsType(strings.TrimPrefix(t, "[]"), names)
+		if strings.ContainsAny(elem, " |") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case strings.HasPrefix(t, "map[string]"):
+		return "Record<string, " + tsType(strings.TrimPrefix(t, "map[string]"), names) + ">"
+	case strings.HasPrefix(t, "*"):
+		return tsType(strings.TrimPrefix(t, "*"), names)
+	}
+	switch t {
+	case "int", "int64", "float64":
+		return "number"
+	case "string":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "interface{}", "nil":
+		return "unknown"
+	}
+	if n, ok := names[t]; ok {
+		return n
+	}
+	// Objects allowing any additional property are not emitted as interfaces.
+	return "Record<string, unknown>"
+}
+
+func writeInterface(buf *bytes.Buffer, s transpiler.Struct) {
+	buf.WriteString("\n")
+	writeDoc(buf, "", s.Description)
+	fmt.Fprintf(buf, "export interface %s {\n", s.Name)
+
+	fields := make([]transpiler.Field, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].JSONName < fields[j].JSONName
+	})
+	for _, f := range fields {
+		writeDoc(buf, "  ", f.Description)
+		name := f.JSONName
+		if !identifierRE.MatchString(name) {
+			name = fmt.Sprintf("%q", name)
+		}
+		optional := "?"
+		if f.Required {
+			optional = ""
+		}
+		fmt.Fprintf(buf, "  %s%s: %s;\n", name, optional, f.Type)
+	}
+	buf.WriteString("}\n")
+}
+
+func writeDoc(buf *bytes.Buffer, indent, doc string) {
+	doc = strings.TrimSpace(strings.ReplaceAll(doc, "*/", "*\\/"))
+	if len(doc) == 0 {
+		return
+	}
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, doc)
+		return
+	}
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, l := range lines {
+		fmt.Fprintf(buf, "%s * %s\n", indent, strings.TrimRight(l, " "))
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
@@ -0,0 +1,64 @@
+package emitter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/generator/emitter"
+)
+
+func TestEmitTypeScript(t *testing.T) {
+	spec := `{"type":"object","required":["name"],"properties":{
+		"name":{"type":"string","description":"The name of the pet."},
+		"age":{"type":"integer"},
+		"photoUrls":{"type":"array","items":{"type":"string"}},
+		"labels":{"type":"object","additionalProperties":{"type":"string"}},
+		"category":{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string"}}},
+		"x-extra":{"type":"boolean"}}}`
+	status := `{"type":"object","properties":{
+		"id":{"type":"string"},
+		"category":{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string"}}}}}`
+	auth := `{"type":"object","required":["username"],"properties":{
+		"username":{"type":"string"},
+		"passwordRef":{"type":"object","properties":{"key":{"type":"string"},"name":{"type":"string"}}}}}`
+
+	got, err := emitter.EmitTypeScript(
+		emitter.TypeScriptRoot{Kind: "Pet", Name: "PetSpec", Schema: []byte(spec)},
+		emitter.TypeScriptRoot{Kind: "Pet", Name: "PetStatus", Schema: []byte(status)},
+		emitter.TypeScriptRoot{Kind: "BasicAuth", Name: "BasicAuthSpec", Schema: []byte(auth)},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ts := string(got)
+
+	for _, s := range []string{
+		"export interface PetSpec {",
+		"  /** The name of the pet. */\n  name: string;",
+		"  age?: number;",
+		"  photoUrls?: string[];",
+		"  labels?: Record<string, string>;",
+		"  category?: PetCategory;",
+		`  "x-extra"?: boolean;`,
+		"export interface PetStatus {",
+		"export interface PetCategory {",
+		"export interface BasicAuthSpec {",
+		"  username: string;",
+		"  passwordRef?: BasicAuthPasswordRef;",
+		"export interface BasicAuthPasswordRef {",
+	} {
+		if !strings.Contains(ts, s) {
+			t.Errorf("expected definitions to contain %q, got:\n%s", s, ts)
+		}
+	}
+	if strings.Count(ts, "export interface PetCategory {") != 1 {
+		t.Errorf("expected PetCategory to be shared by spec and status, got:\n%s", ts)
+	}
+	if strings.Index(ts, "export interface BasicAuthSpec {") > strings.Index(ts, "export interface BasicAuthPasswordRef {") {
+		t.Errorf("expected root interfaces first, got:\n%s", ts)
+	}
+
+	if _, err := emitter.EmitTypeScript(emitter.TypeScriptRoot{Kind: "Pet", Name: "PetSpec", Schema: []byte(`{"type":"string"}`)}); err == nil {
+		t.Errorf("expected error for a non object schema")
+	}
+}
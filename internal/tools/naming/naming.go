@@ -0,0 +1,94 @@
+package naming
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// MaxLength is the maximum length of the names of generated objects
+	// (DNS-1123 label), so that names can also be used as label values.
+	MaxLength = 63
+
+	hashLength = 8
+)
+
+// ControllerName returns the name of the ServiceAccount, Role, RoleBinding and
+// Deployment of the dynamic controller of the RestDefinition named restDefinition.
+// These objects live in the namespace of the RestDefinition, where its name is unique.
+func ControllerName(restDefinition string) string {
+	return SafeName(fmt.Sprintf("%s-controller", restDefinition))
+}
+
+// ArtifactsName returns the name of the ConfigMap holding the artifacts (JSON
+// schemas, TypeScript definitions) of the kinds generated for the RestDefinition
+// named restDefinition.
+func ArtifactsName(restDefinition string) string {
+	return SafeName(fmt.Sprintf("%s-artifacts", restDefinition))
+}
+
+// OperationsName returns the name of the ConfigMap holding the operation map of
+// the RestDefinition named restDefinition, mounted into its dynamic controller.
+func OperationsName(restDefinition string) string {
+	return SafeName(fmt.Sprintf("%s-operations", restDefinition))
+}
+
+// PoolName returns the name standing for the controller pool named pool in place
+// of the name of a RestDefinition: the objects of the shared dynamic controller
+// are named after it, i.e. ControllerName(PoolName("github")) is
+// 'pool-github-<hash>-controller'. A hash of the pool name is always appended,
+// so that no RestDefinition name is mistaken for a pool.
+func PoolName(pool string) string {
+	return truncate(fmt.Sprintf("pool-%s", pool), hash("pool:"+pool))
+}
+
+// ClusterScopedName returns the name of the cluster scoped objects (ClusterRole,
+// ClusterRoleBinding) of the dynamic controller of the RestDefinition nn.
+// A hash of the namespaced name is always appended, so that i.e. 'a-b/c' and
+// 'a/b-c' never collide.
+func ClusterScopedName(nn types.NamespacedName) string {
+	name := fmt.Sprintf("%s-%s-controller", nn.Namespace, nn.Name)
+	return truncate(name, hash(nn.String()))
+}
+
+// ProviderRoleName returns the name of the ClusterRole granting the provider access
+// to the kinds generated for the RestDefinition nn. A hash is always appended, as
+// for ClusterScopedName, and never matches the one of ClusterScopedName.
+func ProviderRoleName(nn types.NamespacedName) string {
+	name := fmt.Sprintf("%s-%s-provider", nn.Namespace, nn.Name)
+	return truncate(name, hash("provider:"+nn.String()))
+}
+
+// SecretsRoleName returns the name of the Role (and RoleBinding) granting the dynamic
+// controller of the RestDefinition nn read access to the secrets referenced in a
+// namespace it watches. A hash is always appended, as for ProviderRoleName.
+func SecretsRoleName(nn types.NamespacedName) string {
+	name := fmt.Sprintf("%s-%s-secrets", nn.Namespace, nn.Name)
+	return truncate(name, hash("secrets:"+nn.String()))
+}
+
+// SafeName returns name unchanged when it is at most MaxLength characters long,
+// otherwise it is truncated and suffixed with a hash of the full name.
+func SafeName(name string) string {
+	if len(name) <= MaxLength {
+		return name
+	}
+	return truncate(name, hash(name))
+}
+
+func truncate(name, suffix string) string {
+	max := MaxLength - len(suffix) - 1
+	if len(name) > max {
+		name = name[:max]
+	}
+	return fmt.Sprintf("%s-%s", strings.TrimRight(name, "-."), suffix)
+}
+
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:hashLength]
+}
@@ -0,0 +1,99 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestControllerName(t *testing.T) {
+	if got := ControllerName("def-pet"); got != "def-pet-controller" {
+		t.Errorf("expected def-pet-controller, got %s", got)
+	}
+
+	long := strings.Repeat("a", 60)
+	got := ControllerName(long)
+	if len(got) != MaxLength {
+		t.Errorf("expected name of %d characters, got %d (%s)", MaxLength, len(got), got)
+	}
+	if got != ControllerName(long) {
+		t.Errorf("expected stable name")
+	}
+	if got == ControllerName(long+"b") {
+		t.Errorf("expected different names for different RestDefinitions")
+	}
+	if errs := validation.IsDNS1123Label(got); len(errs) > 0 {
+		t.Errorf("invalid name %s: %v", got, errs)
+	}
+}
+
+func TestPoolName(t *testing.T) {
+	got := ControllerName(PoolName("github"))
+	if !strings.HasPrefix(got, "pool-github-") || !strings.HasSuffix(got, "-controller") {
+		t.Errorf("expected pool-github-<hash>-controller, got %s", got)
+	}
+	if got == ControllerName("github-pool") || got == ControllerName("pool-github") {
+		t.Errorf("expected the pool name not to collide with a RestDefinition name, got %s", got)
+	}
+	if PoolName("a") == PoolName("b") {
+		t.Errorf("expected different names for different pools")
+	}
+	if got := PoolName(strings.Repeat("a", 62)); len(got) != MaxLength {
+		t.Errorf("expected name of %d characters, got %d (%s)", MaxLength, len(got), got)
+	}
+}
+
+func TestArtifactsName(t *testing.T) {
+	if got := ArtifactsName("def-pet"); got != "def-pet-artifacts" {
+		t.Errorf("expected def-pet-artifacts, got %s", got)
+	}
+	if got := ArtifactsName(strings.Repeat("a", 60)); len(got) != MaxLength {
+		t.Errorf("expected name of %d characters, got %d (%s)", MaxLength, len(got), got)
+	}
+}
+
+func TestOperationsName(t *testing.T) {
+	if got := OperationsName("def-pet"); got != "def-pet-operations" {
+		t.Errorf("expected def-pet-operations, got %s", got)
+	}
+	if got := OperationsName(strings.Repeat("a", 60)); len(got) != MaxLength {
+		t.Errorf("expected name of %d characters, got %d (%s)", MaxLength, len(got), got)
+	}
+}
+
+func TestClusterScopedName(t *testing.T) {
+	a := ClusterScopedName(types.NamespacedName{Namespace: "a-b", Name: "c"})
+	b := ClusterScopedName(types.NamespacedName{Namespace: "a", Name: "b-c"})
+	if a == b {
+		t.Errorf("expected different names, got %s", a)
+	}
+	if !strings.HasPrefix(a, "a-b-c-controller-") {
+		t.Errorf("unexpected name %s", a)
+	}
+
+	long := ClusterScopedName(types.NamespacedName{Namespace: strings.Repeat("n", 63), Name: strings.Repeat("r", 63)})
+	if len(long) > MaxLength {
+		t.Errorf("expected name of at most %d characters, got %d (%s)", MaxLength, len(long), long)
+	}
+	if errs := validation.IsDNS1123Label(long); len(errs) > 0 {
+		t.Errorf("invalid name %s: %v", long, errs)
+	}
+}
+
+func TestProviderRoleName(t *testing.T) {
+	nn := types.NamespacedName{Namespace: "default", Name: "def-pet"}
+	got := ProviderRoleName(nn)
+	if !strings.HasPrefix(got, "default-def-pet-provider-") {
+		t.Errorf("unexpected name %s", got)
+	}
+
+	long := types.NamespacedName{Namespace: strings.Repeat("n", 63), Name: strings.Repeat("r", 63)}
+	if ProviderRoleName(long) == ClusterScopedName(long) {
+		t.Errorf("expected the provider role and the controller cluster role to differ")
+	}
+	if errs := validation.IsDNS1123Label(ProviderRoleName(long)); len(errs) > 0 {
+		t.Errorf("invalid name %s: %v", ProviderRoleName(long), errs)
+	}
+}
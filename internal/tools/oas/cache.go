@@ -0,0 +1,115 @@
+package oas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// evictAfter is how long an entry is kept without being requested: the RestDefinitions
+// are reconciled far more often, so an older entry is one of an oasPath no longer used.
+const evictAfter = time.Hour
+
+// Cache keeps the last fetched version of OAS documents, so that they are
+// downloaded again only when they changed upstream. Only the contents are cached:
+// every Get parses a new document, since the generation modifies the schemas of
+// the document it is given.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	contents []byte
+	etag     string
+	fetched  time.Time
+	used     time.Time
+}
+
+// Revision identifies the version of an OAS document returned by the Cache.
+type Revision struct {
+	// ETag is the entity tag of the document, if the server provided one.
+	ETag string
+	// Digest is the SHA-256 digest of the contents of the document.
+	Digest string
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{
+		entries: map[string]*cacheEntry{},
+		now:     time.Now,
+	}
+}
+
+// Get returns the OAS document at oasPath and its revision.
+// Cached contents checked less than maxAge ago are used as is, otherwise the
+// document is fetched again, conditionally on its entity tag.
+func (c *Cache) Get(oasPath string, maxAge time.Duration) (*libopenapi.DocumentModel[v3.Document], Revision, error) {
+	contents, etag, err := c.contents(oasPath, maxAge)
+	if err != nil {
+		return nil, Revision{}, err
+	}
+
+	doc, err := Parse(contents)
+	if err != nil {
+		return nil, Revision{}, err
+	}
+	sum := sha256.Sum256(contents)
+	return doc, Revision{ETag: etag, Digest: hex.EncodeToString(sum[:])}, nil
+}
+
+func (c *Cache) contents(oasPath string, maxAge time.Duration) ([]byte, string, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	c.evict(now)
+	entry, ok := c.entries[oasPath]
+	if ok {
+		entry.used = now
+	}
+	c.mu.Unlock()
+
+	if ok && maxAge > 0 && now.Sub(entry.fetched) < maxAge {
+		return entry.contents, entry.etag, nil
+	}
+
+	etag := ""
+	if ok {
+		etag = entry.etag
+	}
+	contents, newETag, modified, err := FetchIfModified(oasPath, etag)
+	if err != nil {
+		return nil, "", err
+	}
+	if !modified {
+		contents = entry.contents
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(newETag) == 0 {
+		// Without an entity tag there is nothing to revalidate.
+		delete(c.entries, oasPath)
+		if maxAge > 0 {
+			c.entries[oasPath] = &cacheEntry{contents: contents, fetched: now, used: now}
+		}
+		return contents, "", nil
+	}
+	c.entries[oasPath] = &cacheEntry{contents: contents, etag: newETag, fetched: now, used: now}
+	return contents, newETag, nil
+}
+
+// evict removes the entries not requested for evictAfter. c.mu must be held.
+func (c *Cache) evict(now time.Time) {
+	for oasPath, entry := range c.entries {
+		if now.Sub(entry.used) >= evictAfter {
+			delete(c.entries, oasPath)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package oas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCacheGet(t *testing.T) {
+	contents, err := os.ReadFile("../../controllers/restdefinition/generator/tests/oas/petstore.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	downloads := 0
+	etag := `"v1"`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Header().Set("ETag", etag)
+		w.Write(contents)
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	cache := NewCache()
+	cache.now = func() time.Time { return now }
+
+	oasPath := srv.URL + "/petstore.yaml"
+	doc, rev, err := cache.Get(oasPath, 0)
+	if err != nil {
+		t.Fatalf("failed to get document: %v", err)
+	}
+	if doc == nil || rev.ETag != etag || len(rev.Digest) == 0 || downloads != 1 {
+		t.Fatalf("unexpected first fetch: doc=%v revision=%+v downloads=%d", doc != nil, rev, downloads)
+	}
+
+	// Not modified upstream: the cached contents are parsed again, so that
+	// concurrent reconciles never share a document.
+	cached, cachedRev, err := cache.Get(oasPath, 0)
+	if err != nil {
+		t.Fatalf("failed to get document: %v", err)
+	}
+	if cached == nil || cached == doc || downloads != 1 {
+		t.Errorf("expected a new document from the cached contents, downloads=%d", downloads)
+	}
+	if cachedRev != rev {
+		t.Errorf("expected the same revision for the same contents, got %+v, want %+v", cachedRev, rev)
+	}
+
+	// Modified upstream, but checked less than maxAge ago.
+	etag = `"v2"`
+	now = now.Add(time.Minute)
+	cached, _, err = cache.Get(oasPath, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to get document: %v", err)
+	}
+	if cached == nil || downloads != 1 {
+		t.Errorf("expected cached contents within maxAge, downloads=%d", downloads)
+	}
+
+	now = now.Add(time.Hour)
+	fresh, rev, err := cache.Get(oasPath, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to get document: %v", err)
+	}
+	if fresh == nil || rev.ETag != `"v2"` || downloads != 2 {
+		t.Errorf("expected a new document, etag=%s downloads=%d", rev.ETag, downloads)
+	}
+
+	// Not requested for evictAfter: the entry is evicted and the document downloaded again.
+	now = now.Add(evictAfter)
+	if _, _, err := cache.Get(srv.URL+"/other.yaml", 0); err != nil {
+		t.Fatalf("failed to get document: %v", err)
+	}
+	if _, ok := cache.entries[oasPath]; ok {
+		t.Errorf("expected the entry of %s to be evicted", oasPath)
+	}
+	if _, _, err := cache.Get(oasPath, time.Hour); err != nil {
+		t.Fatalf("failed to get document: %v", err)
+	}
+	if downloads != 4 {
+		t.Errorf("expected the evicted document to be downloaded again, downloads=%d", downloads)
+	}
+}
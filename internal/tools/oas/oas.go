@@ -0,0 +1,98 @@
+package oas
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/filegetter"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// Load downloads the OAS document from oasPath (an URL or a local file),
+// builds the V3 model and resolves its references.
+func Load(oasPath string) (*libopenapi.DocumentModel[v3.Document], error) {
+	doc, _, _, err := LoadIfModified(oasPath, "")
+	return doc, err
+}
+
+// LoadIfModified is like Load, but when etag is not empty the document is only
+// downloaded if its entity tag changed: when it did not, the returned document is
+// nil and modified is false. The entity tag of the document is returned, if any.
+func LoadIfModified(oasPath string, etag string) (doc *libopenapi.DocumentModel[v3.Document], newETag string, modified bool, err error) {
+	contents, newETag, modified, err := FetchIfModified(oasPath, etag)
+	if err != nil || !modified {
+		return nil, newETag, modified, err
+	}
+
+	doc, err = Parse(contents)
+	return doc, newETag, true, err
+}
+
+// FetchIfModified downloads the contents of the OAS document from oasPath, as
+// LoadIfModified, without parsing them.
+func FetchIfModified(oasPath string, etag string) (contents []byte, newETag string, modified bool, err error) {
+	basePath, err := os.MkdirTemp("", "swaggergen-provider")
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to create directory: %w", err)
+	}
+	defer os.RemoveAll(basePath)
+
+	dst := path.Join(basePath, path.Base(oasPath))
+	newETag, modified, err = filegetter.GetFileIfModified(dst, oasPath, nil, etag)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to download file: %w", err)
+	}
+	if !modified {
+		return nil, newETag, false, nil
+	}
+
+	contents, err = os.ReadFile(dst)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to read file: %w", err)
+	}
+	return contents, newETag, true, nil
+}
+
+// Parse builds the V3 model of the OAS document contents and resolves its references.
+func Parse(contents []byte) (*libopenapi.DocumentModel[v3.Document], error) {
+	d, err := libopenapi.NewDocument(contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	doc, modelErrors := d.BuildV3Model()
+	if len(modelErrors) > 0 {
+		return nil, fmt.Errorf("failed to build model: %w", errors.Join(modelErrors...))
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("failed to build model")
+	}
+
+	// Resolve model references
+	resolvingErrors := doc.Index.GetResolver().Resolve()
+	errs := []error{}
+	for i := range resolvingErrors {
+		errs = append(errs, resolvingErrors[i].ErrorRef)
+	}
+	if len(resolvingErrors) > 0 {
+		return nil, &ResolveError{Errors: errs}
+	}
+
+	return doc, nil
+}
+
+// ResolveError reports the references of the OAS document that could not be resolved.
+type ResolveError struct {
+	Errors []error
+}
+
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf("failed to resolve model references: %v", errors.Join(e.Errors...))
+}
+
+func (e *ResolveError) Unwrap() []error {
+	return e.Errors
+}
@@ -18,7 +18,7 @@ func InstallClusterRole(ctx context.Context, kube client.Client, obj *rbacv1.Clu
 			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
 			if err != nil {
 				if apierrors.IsNotFound(err) {
-					return kube.Create(ctx, obj)
+					return CreateOrAdopt(ctx, kube, obj)
 				}
 
 				return err
@@ -61,7 +61,55 @@ func UninstallClusterRole(ctx context.Context, opts UninstallOptions) error {
 	)
 }
 
-func CreateClusterRole(opts types.NamespacedName) rbacv1.ClusterRole {
+// ApplyClusterRole creates the cluster role if it does not exist, otherwise it updates
+// its rules (and labels) when they differ from the desired ones. Returns true if the cluster role was created or its rules changed.
+func ApplyClusterRole(ctx context.Context, kube client.Client, obj *rbacv1.ClusterRole) (changed bool, err error) {
+	err = retry.Do(
+		func() error {
+			tmp := rbacv1.ClusterRole{}
+			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					changed = true
+					return CreateOrAdopt(ctx, kube, obj)
+				}
+
+				return err
+			}
+
+			labelsChanged := mergeLabels(&tmp, obj.Labels)
+			if RulesEqual(tmp.Rules, obj.Rules) {
+				if !labelsChanged {
+					return nil
+				}
+				return kube.Update(ctx, &tmp)
+			}
+
+			tmp.Rules = obj.Rules
+			changed = true
+			return kube.Update(ctx, &tmp)
+		},
+	)
+	return changed, err
+}
+
+// LookupClusterRole returns true if the cluster role exists and grants exactly the rules of obj
+// with the labels of obj.
+func LookupClusterRole(ctx context.Context, kube client.Client, obj *rbacv1.ClusterRole) (exists bool, upToDate bool, err error) {
+	tmp := rbacv1.ClusterRole{}
+	err = kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, false, nil
+		}
+
+		return false, false, err
+	}
+
+	return true, RulesEqual(tmp.Rules, obj.Rules) && hasLabels(tmp.Labels, obj.Labels), nil
+}
+
+func CreateClusterRole(opts types.NamespacedName, rules []rbacv1.PolicyRule) rbacv1.ClusterRole {
 	return rbacv1.ClusterRole{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "rbac.authorization.k8s.io/v1",
@@ -70,17 +118,6 @@ func CreateClusterRole(opts types.NamespacedName) rbacv1.ClusterRole {
 		ObjectMeta: metav1.ObjectMeta{
 			Name: opts.Name,
 		},
-		Rules: []rbacv1.PolicyRule{
-			{
-				APIGroups: []string{""},
-				Resources: []string{"namespaces"},
-				Verbs:     []string{"*"},
-			},
-			{
-				APIGroups: []string{"apiextensions.k8s.io/v1"},
-				Resources: []string{"crds"},
-				Verbs:     []string{"*"},
-			},
-		},
+		Rules: rules,
 	}
 }
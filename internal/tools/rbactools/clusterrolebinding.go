@@ -2,6 +2,7 @@ package rbactools
 
 import (
 	"context"
+	"slices"
 
 	"github.com/avast/retry-go"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -18,7 +19,7 @@ func InstallClusterRoleBinding(ctx context.Context, kube client.Client, obj *rba
 			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
 			if err != nil {
 				if apierrors.IsNotFound(err) {
-					return kube.Create(ctx, obj)
+					return CreateOrAdopt(ctx, kube, obj)
 				}
 
 				return err
@@ -61,7 +62,66 @@ func UninstallClusterRoleBinding(ctx context.Context, opts UninstallOptions) err
 	)
 }
 
-func CreateClusterRoleBinding(opts types.NamespacedName) rbacv1.ClusterRoleBinding {
+// ApplyClusterRoleBinding creates the cluster role binding, or updates its subjects
+// and labels. The role reference of a binding is immutable: the binding is recreated
+// when it changes. Returns true if the binding has been changed.
+func ApplyClusterRoleBinding(ctx context.Context, kube client.Client, obj *rbacv1.ClusterRoleBinding) (changed bool, err error) {
+	err = retry.Do(
+		func() error {
+			tmp := rbacv1.ClusterRoleBinding{}
+			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					changed = true
+					return CreateOrAdopt(ctx, kube, obj)
+				}
+
+				return err
+			}
+
+			if tmp.RoleRef != obj.RoleRef {
+				if err := kube.Delete(ctx, &tmp); client.IgnoreNotFound(err) != nil {
+					return err
+				}
+				changed = true
+				return CreateOrAdopt(ctx, kube, obj)
+			}
+
+			labelsChanged := mergeLabels(&tmp, obj.Labels)
+			if slices.Equal(tmp.Subjects, obj.Subjects) {
+				if !labelsChanged {
+					return nil
+				}
+				return kube.Update(ctx, &tmp)
+			}
+
+			tmp.Subjects = obj.Subjects
+			changed = true
+			return kube.Update(ctx, &tmp)
+		},
+	)
+	return changed, err
+}
+
+// LookupClusterRoleBinding returns true if the cluster role binding exists and binds
+// the cluster role of obj to exactly the subjects of obj, with the labels of obj.
+func LookupClusterRoleBinding(ctx context.Context, kube client.Client, obj *rbacv1.ClusterRoleBinding) (exists bool, upToDate bool, err error) {
+	tmp := rbacv1.ClusterRoleBinding{}
+	err = kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, false, nil
+		}
+
+		return false, false, err
+	}
+
+	return true, tmp.RoleRef == obj.RoleRef && slices.Equal(tmp.Subjects, obj.Subjects) &&
+		hasLabels(tmp.Labels, obj.Labels), nil
+}
+
+// CreateClusterRoleBinding binds the cluster role named opts.Name to the service account sa.
+func CreateClusterRoleBinding(opts types.NamespacedName, sa types.NamespacedName) rbacv1.ClusterRoleBinding {
 	return rbacv1.ClusterRoleBinding{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "rbac.authorization.k8s.io/v1",
@@ -78,8 +138,8 @@ func CreateClusterRoleBinding(opts types.NamespacedName) rbacv1.ClusterRoleBindi
 		Subjects: []rbacv1.Subject{
 			{
 				Kind:      "ServiceAccount",
-				Name:      opts.Name,
-				Namespace: opts.Namespace,
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
 			},
 		},
 	}
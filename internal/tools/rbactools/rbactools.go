@@ -1,6 +1,10 @@
 package rbactools
 
 import (
+	"context"
+	"encoding/json"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -10,3 +14,50 @@ type UninstallOptions struct {
 	NamespacedName types.NamespacedName
 	Log            func(msg string, keysAndValues ...any)
 }
+
+// hasLabels returns true if labels contains all the desired labels.
+func hasLabels(labels, desired map[string]string) bool {
+	for k, v := range desired {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeLabels adds the desired labels to obj. Returns true if obj has been changed.
+func mergeLabels(obj client.Object, desired map[string]string) bool {
+	labels := obj.GetLabels()
+	if hasLabels(labels, desired) {
+		return false
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range desired {
+		labels[k] = v
+	}
+	obj.SetLabels(labels)
+	return true
+}
+
+// CreateOrAdopt creates obj. The cache of the provider only holds the objects with
+// the labels of the generated ones: an existing object with the name of obj but
+// without those labels is not found there and makes the creation fail. Such an
+// object is adopted instead: the labels of obj are merged into it with a patch, so
+// that it enters the cache and is reconciled as any generated object afterwards.
+func CreateOrAdopt(ctx context.Context, kube client.Client, obj client.Object) error {
+	err := kube.Create(ctx, obj)
+	if !apierrors.IsAlreadyExists(err) || len(obj.GetLabels()) == 0 {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"labels": obj.GetLabels()},
+	})
+	if err != nil {
+		return err
+	}
+	live := obj.DeepCopyObject().(client.Object)
+	return kube.Patch(ctx, live, client.RawPatch(types.MergePatchType, patch))
+}
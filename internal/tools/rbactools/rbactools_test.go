@@ -47,18 +47,28 @@ func TestRoleGeneration(t *testing.T) {
 		Rules: []rbacv1.PolicyRule{
 			{
 				APIGroups: []string{"swaggergen.krateo.io"},
-				Resources: []string{"restdefinitions", "restdefinitions/status"},
-				Verbs:     []string{"*"},
+				Resources: []string{"restdefinitions"},
+				Verbs:     []string{"get", "list", "watch"},
 			},
 			{
 				APIGroups: []string{""},
-				Resources: []string{"secrets"},
-				Verbs:     []string{"get"},
+				Resources: []string{"events"},
+				Verbs:     []string{"create", "patch"},
 			},
 			{
 				APIGroups: []string{"test-group"},
-				Resources: []string{"test-kinds", "test-kinds/status"},
-				Verbs:     []string{"*"},
+				Resources: []string{"test-kinds"},
+				Verbs:     []string{"get", "list", "watch", "update", "patch"},
+			},
+			{
+				APIGroups: []string{"test-group"},
+				Resources: []string{"test-kinds/status"},
+				Verbs:     []string{"get", "update", "patch"},
+			},
+			{
+				APIGroups: []string{"test-group"},
+				Resources: []string{"test-kinds/finalizers"},
+				Verbs:     []string{"update"},
 			},
 		},
 	}
@@ -85,6 +95,62 @@ func TestRoleGeneration(t *testing.T) {
 
 }
 
+func TestApplyRole(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewFakeClient()
+
+	nn := types.NamespacedName{Name: "test-role", Namespace: "test-namespace"}
+	role, _ := rbactools.InitRole(nn)
+	rbactools.PopulateRole(schema.GroupVersionKind{Group: "test-group", Version: "v1alpha1", Kind: "test-kind"}, &role)
+
+	exists, _, err := rbactools.LookupRole(ctx, cli, &role)
+	if err != nil || exists {
+		t.Fatalf("expected role not to exist, got exists=%v err=%v", exists, err)
+	}
+
+	changed, err := rbactools.ApplyRole(ctx, cli, &role)
+	if err != nil || !changed {
+		t.Fatalf("expected role to be created, got changed=%v err=%v", changed, err)
+	}
+
+	rbactools.PopulateAuthRole(schema.GroupVersionKind{Group: "test-group", Version: "v1alpha1", Kind: "BasicAuth"}, &role)
+	rbactools.PopulateSecretsRole([]string{"token-b", "token-a"}, &role)
+
+	exists, upToDate, err := rbactools.LookupRole(ctx, cli, &role)
+	if err != nil || !exists || upToDate {
+		t.Fatalf("expected outdated role, got exists=%v upToDate=%v err=%v", exists, upToDate, err)
+	}
+
+	changed, err = rbactools.ApplyRole(ctx, cli, &role)
+	if err != nil || !changed {
+		t.Fatalf("expected role to be updated, got changed=%v err=%v", changed, err)
+	}
+
+	installed := rbacv1.Role{}
+	if err := cli.Get(ctx, nn, &installed); err != nil {
+		t.Fatalf("failed to get role: %v", err)
+	}
+	secretsRule := installed.Rules[len(installed.Rules)-1]
+	if !reflect.DeepEqual(secretsRule.ResourceNames, []string{"token-a", "token-b"}) {
+		t.Errorf("expected sorted secret names, got %v", secretsRule.ResourceNames)
+	}
+
+	changed, err = rbactools.ApplyRole(ctx, cli, &role)
+	if err != nil || changed {
+		t.Errorf("expected role to be unchanged, got changed=%v err=%v", changed, err)
+	}
+}
+
+func TestPopulateSecretsRoleWithoutNames(t *testing.T) {
+	role, _ := rbactools.InitRole(types.NamespacedName{Name: "test-role", Namespace: "test-namespace"})
+	rules := len(role.Rules)
+
+	rbactools.PopulateSecretsRole(nil, &role)
+	if len(role.Rules) != rules {
+		t.Errorf("expected no secrets rule to be added, got %v", role.Rules[len(role.Rules)-1])
+	}
+}
+
 func TestClusterRoleGeneration(t *testing.T) {
 	// TestClusterRoleGeneration tests the generation of a ClusterRole
 	// from a ClusterRoleDefinition.
@@ -96,7 +162,13 @@ func TestClusterRoleGeneration(t *testing.T) {
 	// The expected ClusterRole is then compared with the generated
 	// ClusterRole.
 
-	clusterRole := rbactools.CreateClusterRole(types.NamespacedName{Name: "test-clusterrole"})
+	clusterRole := rbactools.CreateClusterRole(types.NamespacedName{Name: "test-clusterrole"}, []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{"test-group"},
+			Resources: []string{"test-kinds"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+	})
 
 	ctx := context.Background()
 	cli := fake.NewFakeClient()
@@ -118,7 +190,8 @@ func TestClusterBindingRoleGeneration(t *testing.T) {
 	// the same as TestClusterRoleGeneration
 	// but for ClusterRoleBinding
 
-	clusterRoleBinding := rbactools.CreateClusterRoleBinding(types.NamespacedName{Name: "test-clusterrolebinding"})
+	clusterRoleBinding := rbactools.CreateClusterRoleBinding(types.NamespacedName{Name: "test-clusterrolebinding"},
+		types.NamespacedName{Name: "test-serviceaccount", Namespace: "test-namespace"})
 	ctx := context.Background()
 	cli := fake.NewFakeClient()
 
@@ -3,6 +3,7 @@ package rbactools
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/avast/retry-go"
@@ -55,7 +56,7 @@ func InstallRole(ctx context.Context, kube client.Client, obj *rbacv1.Role) erro
 			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
 			if err != nil {
 				if apierrors.IsNotFound(err) {
-					return kube.Create(ctx, obj)
+					return CreateOrAdopt(ctx, kube, obj)
 				}
 
 				return err
@@ -66,14 +67,175 @@ func InstallRole(ctx context.Context, kube client.Client, obj *rbacv1.Role) erro
 	)
 }
 
+// ApplyRole creates the role if it does not exist, otherwise it updates its rules
+// (and labels) when they differ from the desired ones. Returns true if the role was created or its rules changed.
+func ApplyRole(ctx context.Context, kube client.Client, obj *rbacv1.Role) (changed bool, err error) {
+	err = retry.Do(
+		func() error {
+			tmp := rbacv1.Role{}
+			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					changed = true
+					return CreateOrAdopt(ctx, kube, obj)
+				}
+
+				return err
+			}
+
+			labelsChanged := mergeLabels(&tmp, obj.Labels)
+			if RulesEqual(tmp.Rules, obj.Rules) {
+				if !labelsChanged {
+					return nil
+				}
+				return kube.Update(ctx, &tmp)
+			}
+
+			tmp.Rules = obj.Rules
+			changed = true
+			return kube.Update(ctx, &tmp)
+		},
+	)
+	return changed, err
+}
+
+// LookupRole returns true if the role exists and grants exactly the rules of obj
+// with the labels of obj.
+func LookupRole(ctx context.Context, kube client.Client, obj *rbacv1.Role) (exists bool, upToDate bool, err error) {
+	tmp := rbacv1.Role{}
+	err = kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, false, nil
+		}
+
+		return false, false, err
+	}
+
+	return true, RulesEqual(tmp.Rules, obj.Rules) && hasLabels(tmp.Labels, obj.Labels), nil
+}
+
+// RulesEqual returns true if the two lists contain the same rules, regardless of their order.
+func RulesEqual(a, b []rbacv1.PolicyRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	key := func(r rbacv1.PolicyRule) string {
+		return fmt.Sprintf("%v|%v|%v|%v|%v", r.APIGroups, r.Resources, r.ResourceNames, r.Verbs, r.NonResourceURLs)
+	}
+
+	count := map[string]int{}
+	for _, r := range a {
+		count[key(r)]++
+	}
+	for _, r := range b {
+		count[key(r)]--
+		if count[key(r)] < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PopulateRole grants the permissions the dynamic controller needs to reconcile
+// the resources of the supplied kind.
 func PopulateRole(resource schema.GroupVersionKind, role *rbacv1.Role) {
 
 	res := strings.ToLower(flect.Pluralize(resource.Kind))
 
+	role.Rules = append(role.Rules,
+		rbacv1.PolicyRule{
+			APIGroups: []string{resource.Group},
+			Resources: []string{res},
+			Verbs:     []string{"get", "list", "watch", "update", "patch"},
+		},
+		rbacv1.PolicyRule{
+			APIGroups: []string{resource.Group},
+			Resources: []string{fmt.Sprintf("%s/status", res)},
+			Verbs:     []string{"get", "update", "patch"},
+		},
+		rbacv1.PolicyRule{
+			APIGroups: []string{resource.Group},
+			Resources: []string{fmt.Sprintf("%s/finalizers", res)},
+			Verbs:     []string{"update"},
+		},
+	)
+}
+
+// PopulateAuthRole grants read only access to the authentication resources of the supplied kind.
+func PopulateAuthRole(resource schema.GroupVersionKind, role *rbacv1.Role) {
+	res := strings.ToLower(flect.Pluralize(resource.Kind))
+
+	role.Rules = append(role.Rules, rbacv1.PolicyRule{
+		APIGroups: []string{resource.Group},
+		Resources: []string{res},
+		Verbs:     []string{"get", "list", "watch"},
+	})
+}
+
+// PopulateInstancesRole grants the provider access to the resources of the supplied
+// kind, which it deletes (or releases) when their RestDefinition is deleted.
+func PopulateInstancesRole(resource schema.GroupVersionKind, role *rbacv1.Role) {
+	res := strings.ToLower(flect.Pluralize(resource.Kind))
+
 	role.Rules = append(role.Rules, rbacv1.PolicyRule{
 		APIGroups: []string{resource.Group},
-		Resources: []string{res, fmt.Sprintf("%s/status", res)},
-		Verbs:     []string{"*"},
+		Resources: []string{res},
+		Verbs:     []string{"get", "list", "watch", "update", "delete"},
+	})
+}
+
+// PopulateSecretsRole grants read access to the named secrets only.
+// No rule is added when names is empty, since a rule without resourceNames
+// would grant access to every secret of the namespace.
+func PopulateSecretsRole(names []string, role *rbacv1.Role) {
+	if len(names) == 0 {
+		return
+	}
+
+	resourceNames := make([]string, len(names))
+	copy(resourceNames, names)
+	sort.Strings(resourceNames)
+
+	role.Rules = append(role.Rules, rbacv1.PolicyRule{
+		APIGroups:     []string{""},
+		Resources:     []string{"secrets"},
+		ResourceNames: resourceNames,
+		Verbs:         []string{"get"},
+	})
+}
+
+// PopulateConnectionSecretsRole grants the creation of secrets, where the dynamic
+// controller writes the connection details of its resources. The rule has no
+// resourceNames, since the names of the secrets are chosen by the users: the secrets
+// created are then updated through PopulateOwnedSecretsRole.
+func PopulateConnectionSecretsRole(role *rbacv1.Role) {
+	role.Rules = append(role.Rules, rbacv1.PolicyRule{
+		APIGroups: []string{""},
+		Resources: []string{"secrets"},
+		Verbs:     []string{"create"},
+	})
+}
+
+// PopulateOwnedSecretsRole grants write access to the named connection secrets only,
+// the ones created by the dynamic controller (see LookupConnectionSecretNames).
+// No rule is added when names is empty.
+func PopulateOwnedSecretsRole(names []string, role *rbacv1.Role) {
+	if len(names) == 0 {
+		return
+	}
+
+	resourceNames := make([]string, len(names))
+	copy(resourceNames, names)
+	sort.Strings(resourceNames)
+
+	role.Rules = append(role.Rules, rbacv1.PolicyRule{
+		APIGroups:     []string{""},
+		Resources:     []string{"secrets"},
+		ResourceNames: resourceNames,
+		Verbs:         []string{"get", "update", "patch"},
 	})
 }
 
@@ -91,13 +253,13 @@ func InitRole(opts types.NamespacedName) (rbacv1.Role, error) {
 		Rules: []rbacv1.PolicyRule{
 			{
 				APIGroups: []string{"swaggergen.krateo.io"},
-				Resources: []string{"restdefinitions", "restdefinitions/status"},
-				Verbs:     []string{"*"},
+				Resources: []string{"restdefinitions"},
+				Verbs:     []string{"get", "list", "watch"},
 			},
 			{
 				APIGroups: []string{""},
-				Resources: []string{"secrets"},
-				Verbs:     []string{"get"},
+				Resources: []string{"events"},
+				Verbs:     []string{"create", "patch"},
 			},
 		},
 	}
@@ -2,6 +2,7 @@ package rbactools
 
 import (
 	"context"
+	"slices"
 
 	"github.com/avast/retry-go"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -50,7 +51,7 @@ func InstallRoleBinding(ctx context.Context, kube client.Client, obj *rbacv1.Rol
 			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
 			if err != nil {
 				if apierrors.IsNotFound(err) {
-					return kube.Create(ctx, obj)
+					return CreateOrAdopt(ctx, kube, obj)
 				}
 
 				return err
@@ -61,6 +62,64 @@ func InstallRoleBinding(ctx context.Context, kube client.Client, obj *rbacv1.Rol
 	)
 }
 
+// ApplyRoleBinding creates the role binding, or updates its subjects and labels.
+// The role reference of a binding is immutable: the binding is recreated when it
+// changes. Returns true if the binding has been changed.
+func ApplyRoleBinding(ctx context.Context, kube client.Client, obj *rbacv1.RoleBinding) (changed bool, err error) {
+	err = retry.Do(
+		func() error {
+			tmp := rbacv1.RoleBinding{}
+			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					changed = true
+					return CreateOrAdopt(ctx, kube, obj)
+				}
+
+				return err
+			}
+
+			if tmp.RoleRef != obj.RoleRef {
+				if err := kube.Delete(ctx, &tmp); client.IgnoreNotFound(err) != nil {
+					return err
+				}
+				changed = true
+				return CreateOrAdopt(ctx, kube, obj)
+			}
+
+			labelsChanged := mergeLabels(&tmp, obj.Labels)
+			if slices.Equal(tmp.Subjects, obj.Subjects) {
+				if !labelsChanged {
+					return nil
+				}
+				return kube.Update(ctx, &tmp)
+			}
+
+			tmp.Subjects = obj.Subjects
+			changed = true
+			return kube.Update(ctx, &tmp)
+		},
+	)
+	return changed, err
+}
+
+// LookupRoleBinding returns true if the role binding exists and binds the role of
+// obj to exactly the subjects of obj, with the labels of obj.
+func LookupRoleBinding(ctx context.Context, kube client.Client, obj *rbacv1.RoleBinding) (exists bool, upToDate bool, err error) {
+	tmp := rbacv1.RoleBinding{}
+	err = kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, false, nil
+		}
+
+		return false, false, err
+	}
+
+	return true, tmp.RoleRef == obj.RoleRef && slices.Equal(tmp.Subjects, obj.Subjects) &&
+		hasLabels(tmp.Labels, obj.Labels), nil
+}
+
 func CreateRoleBinding(opts types.NamespacedName) rbacv1.RoleBinding {
 	return rbacv1.RoleBinding{
 		TypeMeta: metav1.TypeMeta{
@@ -85,3 +144,14 @@ func CreateRoleBinding(opts types.NamespacedName) rbacv1.RoleBinding {
 		},
 	}
 }
+
+// CreateClusterRoleRoleBinding binds the cluster role to the service account sa
+// in the namespace of opts only.
+func CreateClusterRoleRoleBinding(opts types.NamespacedName, clusterRole string, sa types.NamespacedName) rbacv1.RoleBinding {
+	rb := CreateRoleBinding(opts)
+	rb.RoleRef.Kind = "ClusterRole"
+	rb.RoleRef.Name = clusterRole
+	rb.Subjects[0].Name = sa.Name
+	rb.Subjects[0].Namespace = sa.Namespace
+	return rb
+}
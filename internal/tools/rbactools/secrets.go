@@ -0,0 +1,122 @@
+package rbactools
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LookupSecretNames lists the authentication resources of the supplied kinds in
+// namespace and returns, by namespace, the sorted names of the secrets they reference
+// in their own namespace. An empty namespace selects all namespaces. A secret reference
+// is any spec field holding a 'name' and a 'key' (i.e. passwordRef, tokenRef).
+func LookupSecretNames(ctx context.Context, kube client.Reader, namespace string, gvks []schema.GroupVersionKind) (map[string][]string, error) {
+	sets := map[string]map[string]struct{}{}
+	for _, gvk := range gvks {
+		list := unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+		err := kube.List(ctx, &list, client.InNamespace(namespace))
+		if err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, item := range list.Items {
+			spec, ok := item.Object["spec"].(map[string]any)
+			if !ok {
+				continue
+			}
+			for _, v := range spec {
+				ref, ok := v.(map[string]any)
+				if !ok {
+					continue
+				}
+				name, _ := ref["name"].(string)
+				_, hasKey := ref["key"]
+				ns, _ := ref["namespace"].(string)
+				if len(name) == 0 || !hasKey || (len(ns) > 0 && ns != item.GetNamespace()) {
+					continue
+				}
+				if sets[item.GetNamespace()] == nil {
+					sets[item.GetNamespace()] = map[string]struct{}{}
+				}
+				sets[item.GetNamespace()][name] = struct{}{}
+			}
+		}
+	}
+
+	return sortedNames(sets), nil
+}
+
+// LookupConnectionSecretNames lists the resources of the supplied kinds in namespace
+// and returns, by namespace, the sorted names of the connection secrets they reference
+// in spec.writeConnectionSecretToRef that exist and are owned by the resource, i.e.
+// created by the dynamic controller. An empty namespace selects all namespaces.
+func LookupConnectionSecretNames(ctx context.Context, kube client.Reader, namespace string, gvks []schema.GroupVersionKind) (map[string][]string, error) {
+	sets := map[string]map[string]struct{}{}
+	for _, gvk := range gvks {
+		list := unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+		err := kube.List(ctx, &list, client.InNamespace(namespace))
+		if err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, item := range list.Items {
+			name, _, _ := unstructured.NestedString(item.Object, "spec", "writeConnectionSecretToRef", "name")
+			if len(name) == 0 {
+				continue
+			}
+
+			secret := corev1.Secret{}
+			err := kube.Get(ctx, client.ObjectKey{Namespace: item.GetNamespace(), Name: name}, &secret)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			if !slices.ContainsFunc(secret.GetOwnerReferences(), func(ref metav1.OwnerReference) bool {
+				return ref.UID == item.GetUID()
+			}) {
+				// Not created by the dynamic controller: never overwritten
+				continue
+			}
+
+			if sets[item.GetNamespace()] == nil {
+				sets[item.GetNamespace()] = map[string]struct{}{}
+			}
+			sets[item.GetNamespace()][name] = struct{}{}
+		}
+	}
+
+	return sortedNames(sets), nil
+}
+
+func sortedNames(sets map[string]map[string]struct{}) map[string][]string {
+	res := make(map[string][]string, len(sets))
+	for ns, set := range sets {
+		names := make([]string, 0, len(set))
+		for name := range set {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		res[ns] = names
+	}
+	return res
+}
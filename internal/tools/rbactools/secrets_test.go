@@ -0,0 +1,125 @@
+package rbactools_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/rbactools"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLookupSecretNames(t *testing.T) {
+	basicAuth := schema.GroupVersionKind{Group: "test-group", Version: "v1alpha1", Kind: "BasicAuth"}
+	bearerAuth := schema.GroupVersionKind{Group: "test-group", Version: "v1alpha1", Kind: "BearerAuth"}
+
+	newAuth := func(gvk schema.GroupVersionKind, name, namespace string, spec map[string]any) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+		u.SetGroupVersionKind(gvk)
+		u.SetName(name)
+		u.SetNamespace(namespace)
+		return u
+	}
+
+	scheme := runtime.NewScheme()
+	for _, gvk := range []schema.GroupVersionKind{basicAuth, bearerAuth} {
+		scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newAuth(basicAuth, "basic", "test-namespace", map[string]any{
+			"username":    "admin",
+			"passwordRef": map[string]any{"name": "password", "namespace": "test-namespace", "key": "token"},
+		}),
+		newAuth(bearerAuth, "bearer", "test-namespace", map[string]any{
+			"tokenRef": map[string]any{"name": "bearer-token", "key": "token"},
+		}),
+		newAuth(bearerAuth, "bearer-other", "test-namespace", map[string]any{
+			"tokenRef": map[string]any{"name": "other-token", "namespace": "other-namespace", "key": "token"},
+		}),
+		newAuth(bearerAuth, "bearer-elsewhere", "other-namespace", map[string]any{
+			"tokenRef": map[string]any{"name": "elsewhere-token", "key": "token"},
+		}),
+	).Build()
+
+	names, err := rbactools.LookupSecretNames(context.Background(), cli, "test-namespace",
+		[]schema.GroupVersionKind{basicAuth, bearerAuth})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string][]string{"test-namespace": {"bearer-token", "password"}}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+
+	names, err = rbactools.LookupSecretNames(context.Background(), cli, "",
+		[]schema.GroupVersionKind{basicAuth, bearerAuth})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected = map[string][]string{
+		"test-namespace":  {"bearer-token", "password"},
+		"other-namespace": {"elsewhere-token"},
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestLookupConnectionSecretNames(t *testing.T) {
+	token := schema.GroupVersionKind{Group: "test-group", Version: "v1alpha1", Kind: "Token"}
+
+	newToken := func(name, uid, secret string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{"spec": map[string]any{
+			"writeConnectionSecretToRef": map[string]any{"name": secret},
+		}}}
+		u.SetGroupVersionKind(token)
+		u.SetName(name)
+		u.SetNamespace("test-namespace")
+		u.SetUID(types.UID(uid))
+		return u
+	}
+	newSecret := func(name, owner string) *corev1.Secret {
+		res := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace"}}
+		if len(owner) > 0 {
+			res.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: token.GroupVersion().String(), Kind: token.Kind, Name: owner, UID: types.UID(owner + "-uid"),
+			}}
+		}
+		return res
+	}
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	scheme.AddKnownTypeWithName(token, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(token.GroupVersion().WithKind(token.Kind+"List"), &unstructured.UnstructuredList{})
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newToken("owned", "owned-uid", "owned-secret"),
+		newToken("foreign", "foreign-uid", "foreign-secret"),
+		newToken("pending", "pending-uid", "pending-secret"),
+		newSecret("owned-secret", "owned"),
+		newSecret("foreign-secret", ""),
+	).Build()
+
+	names, err := rbactools.LookupConnectionSecretNames(context.Background(), cli, "test-namespace",
+		[]schema.GroupVersionKind{token})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The secrets not created by the dynamic controller, or not created yet, are left out
+	expected := map[string][]string{"test-namespace": {"owned-secret"}}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
@@ -50,7 +50,7 @@ func InstallServiceAccount(ctx context.Context, kube client.Client, obj *corev1.
 			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
 			if err != nil {
 				if apierrors.IsNotFound(err) {
-					return kube.Create(ctx, obj)
+					return CreateOrAdopt(ctx, kube, obj)
 				}
 
 				return err
@@ -61,6 +61,47 @@ func InstallServiceAccount(ctx context.Context, kube client.Client, obj *corev1.
 	)
 }
 
+// ApplyServiceAccount creates the service account, or adds the labels of obj to it.
+// Returns true if the service account has been changed.
+func ApplyServiceAccount(ctx context.Context, kube client.Client, obj *corev1.ServiceAccount) (changed bool, err error) {
+	err = retry.Do(
+		func() error {
+			tmp := corev1.ServiceAccount{}
+			err := kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					changed = true
+					return CreateOrAdopt(ctx, kube, obj)
+				}
+
+				return err
+			}
+
+			if !mergeLabels(&tmp, obj.Labels) {
+				return nil
+			}
+			changed = true
+			return kube.Update(ctx, &tmp)
+		},
+	)
+	return changed, err
+}
+
+// LookupServiceAccount returns true if the service account exists and has the labels of obj.
+func LookupServiceAccount(ctx context.Context, kube client.Client, obj *corev1.ServiceAccount) (exists bool, upToDate bool, err error) {
+	tmp := corev1.ServiceAccount{}
+	err = kube.Get(ctx, client.ObjectKeyFromObject(obj), &tmp)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, false, nil
+		}
+
+		return false, false, err
+	}
+
+	return true, hasLabels(tmp.Labels, obj.Labels), nil
+}
+
 func CreateServiceAccount(opts types.NamespacedName) corev1.ServiceAccount {
 	return corev1.ServiceAccount{
 		TypeMeta: metav1.TypeMeta{
@@ -0,0 +1,110 @@
+package definition
+
+import (
+	"context"
+	"errors"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/controllers/restdefinition/validation"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-swaggergen-krateo-io-v1alpha1-restdefinition,mutating=false,failurePolicy=fail,sideEffects=None,groups=swaggergen.krateo.io,resources=restdefinitions,verbs=create;update,versions=v1alpha1,name=vrestdefinition.swaggergen.krateo.io,admissionReviewVersions=v1
+
+const (
+	errNotRestDefinition = "object is not a RestDefinition"
+)
+
+func Setup(mgr ctrl.Manager, log logging.Logger, extendedArgs bool) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&definitionv1alpha1.RestDefinition{}).
+		WithValidator(NewValidator(log.WithValues("webhook", "restdefinition-validator"), oas.Load, extendedArgs)).
+		Complete()
+}
+
+var _ admission.CustomValidator = (*Validator)(nil)
+
+// Validator rejects RestDefinitions whose resource does not match the referenced OAS
+// document, or whose dynamic controller needs arguments the provider does not pass.
+type Validator struct {
+	log  logging.Logger
+	load func(oasPath string) (*libopenapi.DocumentModel[v3.Document], error)
+	// extendedArgs is true when the provider passes the extended arguments to the
+	// dynamic controllers.
+	extendedArgs bool
+}
+
+func NewValidator(log logging.Logger, load func(oasPath string) (*libopenapi.DocumentModel[v3.Document], error), extendedArgs bool) *Validator {
+	return &Validator{log: log, load: load, extendedArgs: extendedArgs}
+}
+
+func (v *Validator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*definitionv1alpha1.RestDefinition)
+	if !ok {
+		return nil, errors.New(errNotRestDefinition)
+	}
+
+	return nil, v.validate(cr)
+}
+
+func (v *Validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	cr, ok := newObj.(*definitionv1alpha1.RestDefinition)
+	if !ok {
+		return nil, errors.New(errNotRestDefinition)
+	}
+	old, ok := oldObj.(*definitionv1alpha1.RestDefinition)
+	if !ok {
+		return nil, errors.New(errNotRestDefinition)
+	}
+
+	// Skip validation while the RestDefinition is being deleted, so that
+	// finalizers can always be removed.
+	if cr.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+	// Skip validation when the spec is unchanged (i.e. metadata or status updates),
+	// so that an unreachable OAS document does not block them.
+	if equality.Semantic.DeepEqual(old.Spec, cr.Spec) {
+		return nil, nil
+	}
+
+	return nil, v.validate(cr)
+}
+
+func (v *Validator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *Validator) validate(cr *definitionv1alpha1.RestDefinition) error {
+	gk := schema.GroupKind{Group: definitionv1alpha1.Group, Kind: definitionv1alpha1.RestDefinitionKind}
+
+	if errs := validation.ValidateControllerArgs(&cr.Spec, cr.Namespace, v.extendedArgs); len(errs) > 0 {
+		return apierrors.NewInvalid(gk, cr.Name, errs)
+	}
+
+	doc, err := v.load(cr.Spec.OASPath)
+	if err != nil {
+		if v.log != nil {
+			v.log.Debug("Loading OAS document", "oasPath", cr.Spec.OASPath, "error", err)
+		}
+		return apierrors.NewInvalid(gk, cr.Name, field.ErrorList{
+			field.Invalid(field.NewPath("spec", "oasPath"), cr.Spec.OASPath, err.Error()),
+		})
+	}
+
+	if errs := validation.ValidateRestDefinition(doc, cr); len(errs) > 0 {
+		return apierrors.NewInvalid(gk, cr.Name, errs)
+	}
+
+	return nil
+}
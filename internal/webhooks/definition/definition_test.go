@@ -0,0 +1,112 @@
+package definition
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	definitionv1alpha1 "github.com/krateoplatformops/oasgen-provider/apis/restdefinitions/v1alpha1"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/oas"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidator(t *testing.T) {
+	load := func(oasPath string) (*libopenapi.DocumentModel[v3.Document], error) {
+		if oasPath != "petstore.yaml" {
+			return nil, errors.New("unexpected status code: 404")
+		}
+		return oas.Load("../../controllers/restdefinition/generator/tests/oas/petstore.yaml")
+	}
+	v := NewValidator(nil, load, false)
+
+	newRestDefinition := func(oasPath, path string) *definitionv1alpha1.RestDefinition {
+		return &definitionv1alpha1.RestDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "def-pet", Namespace: "default"},
+			Spec: definitionv1alpha1.RestDefinitionSpec{
+				OASPath:       oasPath,
+				ResourceGroup: "petstore.swagger.io",
+				Resource: definitionv1alpha1.Resource{
+					Kind: "Pet",
+					VerbsDescription: []definitionv1alpha1.VerbsDescription{
+						{Action: "create", Method: "POST", Path: path},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		cr       *definitionv1alpha1.RestDefinition
+		expected string
+	}{
+		{
+			name: "Valid",
+			cr:   newRestDefinition("petstore.yaml", "/pet"),
+		},
+		{
+			name:     "Wrong path",
+			cr:       newRestDefinition("petstore.yaml", "/pets"),
+			expected: `spec.resource.verbsDescription[0].path: Not found: "/pets"`,
+		},
+		{
+			name: "All namespaces without extended arguments",
+			cr: func() *definitionv1alpha1.RestDefinition {
+				cr := newRestDefinition("petstore.yaml", "/pet")
+				cr.Spec.WatchNamespaces = []string{definitionv1alpha1.AllNamespaces}
+				return cr
+			}(),
+			expected: `spec.watchNamespaces: Forbidden: watching all or several namespaces requires the dynamic controller extended arguments`,
+		},
+		{
+			name: "Controller pool without extended arguments",
+			cr: func() *definitionv1alpha1.RestDefinition {
+				cr := newRestDefinition("petstore.yaml", "/pet")
+				cr.Spec.ControllerPool = "shared"
+				return cr
+			}(),
+			expected: `spec.controllerPool: Forbidden: a controller pool requires the dynamic controller extended arguments`,
+		},
+		{
+			name:     "Unreachable OAS",
+			cr:       newRestDefinition("missing.yaml", "/pet"),
+			expected: `spec.oasPath: Invalid value: "missing.yaml": unexpected status code: 404`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := v.ValidateCreate(context.Background(), tc.cr)
+			if len(tc.expected) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !apierrors.IsInvalid(err) {
+				t.Fatalf("expected invalid error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("expected error to contain %q, got %q", tc.expected, err.Error())
+			}
+
+			old := tc.cr.DeepCopy()
+			old.Spec.Resource.Kind = "Dog"
+			_, err = v.ValidateUpdate(context.Background(), old, tc.cr)
+			if !apierrors.IsInvalid(err) {
+				t.Errorf("expected invalid error on update, got %v", err)
+			}
+
+			// Metadata updates are not validated against the OAS document.
+			updated := tc.cr.DeepCopy()
+			updated.Finalizers = append(updated.Finalizers, "finalizer.managedresource.krateo.io")
+			if _, err := v.ValidateUpdate(context.Background(), tc.cr, updated); err != nil {
+				t.Errorf("expected no error when the spec is unchanged, got %v", err)
+			}
+		})
+	}
+}
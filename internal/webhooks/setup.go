@@ -0,0 +1,21 @@
+package webhooks
+
+import (
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	definition "github.com/krateoplatformops/oasgen-provider/internal/webhooks/definition"
+)
+
+// Setup registers all admission webhooks with the supplied manager. extendedArgs
+// tells whether the provider passes the extended arguments to the dynamic controllers.
+func Setup(mgr ctrl.Manager, log logging.Logger, extendedArgs bool) error {
+	for _, setup := range []func(ctrl.Manager, logging.Logger, bool) error{
+		definition.Setup,
+	} {
+		if err := setup(mgr, log, extendedArgs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
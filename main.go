@@ -6,8 +6,12 @@ import (
 	"path/filepath"
 
 	"gopkg.in/alecthomas/kingpin.v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/krateoplatformops/oasgen-provider/apis"
@@ -15,8 +19,11 @@ import (
 	"github.com/krateoplatformops/provider-runtime/pkg/ratelimiter"
 
 	definition "github.com/krateoplatformops/oasgen-provider/internal/controllers/definition"
+	"github.com/krateoplatformops/oasgen-provider/internal/tools/deployment"
+	"github.com/krateoplatformops/oasgen-provider/internal/webhooks"
 	"github.com/krateoplatformops/provider-runtime/pkg/controller"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/stoewer/go-strcase"
 )
@@ -50,6 +57,22 @@ func main() {
 				Default("false").
 				OverrideDefaultFromEnvar(fmt.Sprintf("%s_LEADER_ELECTION", envVarPrefix)).
 				Bool()
+		webhookEnabled = app.Flag("webhook-enabled", "Serve the validating admission webhook for RestDefinitions.").
+				Default("false").
+				OverrideDefaultFromEnvar(fmt.Sprintf("%s_WEBHOOK_ENABLED", envVarPrefix)).
+				Bool()
+		webhookPort = app.Flag("webhook-port", "Port the admission webhook server listens on.").
+				Default("9443").
+				OverrideDefaultFromEnvar(fmt.Sprintf("%s_WEBHOOK_PORT", envVarPrefix)).
+				Int()
+		webhookCertDir = app.Flag("webhook-cert-dir", "Directory containing the admission webhook server TLS certificate (tls.crt and tls.key).").
+				Default("/tmp/k8s-webhook-server/serving-certs").
+				OverrideDefaultFromEnvar(fmt.Sprintf("%s_WEBHOOK_CERT_DIR", envVarPrefix)).
+				String()
+		extendedArgs = app.Flag("dynamic-controller-extended-args", "Pass one -resource argument per resource and the -operations argument to the dynamic controllers. The image set by CDC_IMAGE_TAG must accept them.").
+				Default("false").
+				OverrideDefaultFromEnvar(fmt.Sprintf("%s_DYNAMIC_CONTROLLER_EXTENDED_ARGS", envVarPrefix)).
+				Bool()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -67,15 +90,32 @@ func main() {
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
+	// Only the objects generated for the RestDefinitions are cached, not the
+	// Deployments, ConfigMaps and RBAC of the whole cluster.
+	generated := cache.ByObject{Label: deployment.GeneratedSelector()}
+
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		LeaderElection:   *leaderElection,
 		LeaderElectionID: fmt.Sprintf("leader-election-%s-provider", strcase.KebabCase(providerName)),
 		Cache: cache.Options{
 			SyncPeriod: syncPeriod,
+			ByObject: map[client.Object]cache.ByObject{
+				&appsv1.Deployment{}:         generated,
+				&corev1.ConfigMap{}:          generated,
+				&corev1.ServiceAccount{}:     generated,
+				&rbacv1.Role{}:               generated,
+				&rbacv1.RoleBinding{}:        generated,
+				&rbacv1.ClusterRole{}:        generated,
+				&rbacv1.ClusterRoleBinding{}: generated,
+			},
 		},
 		Metrics: metricsserver.Options{
 			BindAddress: ":8080",
 		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    *webhookPort,
+			CertDir: *webhookCertDir,
+		}),
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 
@@ -87,6 +127,9 @@ func main() {
 	}
 
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add APIs to scheme")
-	kingpin.FatalIfError(definition.Setup(mgr, o), "Cannot setup controllers")
+	kingpin.FatalIfError(definition.Setup(mgr, o, *extendedArgs), "Cannot setup controllers")
+	if *webhookEnabled {
+		kingpin.FatalIfError(webhooks.Setup(mgr, log, *extendedArgs), "Cannot setup webhooks")
+	}
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }